@@ -0,0 +1,43 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// CompletionPolicy turns the instances of a ResourceGraphDefinition into
+// one-shot workflows. Instead of being kept in sync forever, the instances
+// reach a terminal Succeeded or Failed state, after which their resources are
+// no longer reconciled.
+type CompletionPolicy struct {
+	// SuccessWhen lists the expressions under which the instance succeeded.
+	// The instance succeeds once all of them are true. Expressions can
+	// reference the resources of the instance and the instance spec, for
+	// example "${export.status.succeeded > 0}".
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	SuccessWhen []string `json:"successWhen"`
+	// FailureWhen lists the expressions under which the instance failed. The
+	// instance fails as soon as one of them is true. Failure expressions are
+	// evaluated before success expressions.
+	//
+	// +kubebuilder:validation:Optional
+	FailureWhen []string `json:"failureWhen,omitempty"`
+	// TTLSecondsAfterFinished is the time a finished instance is kept before
+	// it is deleted, along with its resources. If omitted, finished instances
+	// are kept until they are deleted.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
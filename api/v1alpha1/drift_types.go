@@ -0,0 +1,44 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// DriftPolicy controls how kro handles changes made to a resource outside of
+// kro. A field drifted when kro sets it, another field manager changed it, and
+// its live value differs from the one kro would apply.
+type DriftPolicy struct {
+	// Action is what kro does with drifted fields. Correct overwrites them
+	// with the desired value on the next reconciliation. Report leaves them
+	// untouched and reports them in the Drifted condition of the instance.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="Correct"
+	// +kubebuilder:validation:Enum=Correct;Report
+	Action DriftAction `json:"action,omitempty"`
+	// Ignore lists field paths on which drift is neither corrected nor
+	// reported, e.g. "spec.replicas" or "metadata.annotations".
+	//
+	// +kubebuilder:validation:Optional
+	Ignore []string `json:"ignore,omitempty"`
+}
+
+// DriftAction is what kro does with the drifted fields of a resource.
+type DriftAction string
+
+const (
+	// DriftActionCorrect overwrites drifted fields.
+	DriftActionCorrect DriftAction = "Correct"
+	// DriftActionReport reports drifted fields without overwriting them.
+	DriftActionReport DriftAction = "Report"
+)
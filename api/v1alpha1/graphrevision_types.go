@@ -0,0 +1,89 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GraphRevisionSpec is the immutable snapshot of a ResourceGraphDefinition
+// generation.
+//
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="GraphRevision spec is immutable"
+type GraphRevisionSpec struct {
+	// ResourceGraphDefinitionName is the name of the ResourceGraphDefinition
+	// the revision was created from.
+	//
+	// +kubebuilder:validation:Required
+	ResourceGraphDefinitionName string `json:"resourceGraphDefinitionName"`
+	// Revision is the generation of the ResourceGraphDefinition the revision
+	// was created from.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	Revision int64 `json:"revision"`
+	// Hash is the hash of the snapshot, used to detect revisions that do not
+	// match the ResourceGraphDefinition generation they were created from.
+	//
+	// +kubebuilder:validation:Required
+	Hash string `json:"hash"`
+	// TopologicalOrder is the order the resources of the compiled graph are
+	// reconciled in.
+	//
+	// +kubebuilder:validation:Optional
+	TopologicalOrder []string `json:"topologicalOrder,omitempty"`
+	// Snapshot is the ResourceGraphDefinition spec the graph was compiled
+	// from. Instances pinned to the revision are reconciled with a graph
+	// compiled from it.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Snapshot ResourceGraphDefinitionSpec `json:"snapshot"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="RESOURCEGRAPHDEFINITION",type=string,priority=0,JSONPath=`.spec.resourceGraphDefinitionName`
+// +kubebuilder:printcolumn:name="REVISION",type=integer,priority=0,JSONPath=`.spec.revision`
+// +kubebuilder:printcolumn:name="HASH",type=string,priority=1,JSONPath=`.spec.hash`
+// +kubebuilder:printcolumn:name="AGE",type="date",priority=0,JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:shortName=gr,scope=Cluster
+
+// GraphRevision is an immutable snapshot of the graph of a
+// ResourceGraphDefinition generation. kro creates one revision per generation,
+// named after the ResourceGraphDefinition and the generation, and owned by the
+// ResourceGraphDefinition. Instances record the revision they were last
+// reconciled with in status.graphRevision, and can be pinned to a revision with
+// the kro.run/graph-revision label.
+type GraphRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GraphRevisionSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GraphRevisionList contains a list of GraphRevision
+type GraphRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GraphRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GraphRevision{}, &GraphRevisionList{})
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// Hook turns a resource into a lifecycle hook. Hooks run once per trigger:
+// once per generation of the instance for PreCreate and PostReady hooks, and
+// once when the instance is deleted for PreDelete hooks. A hook runs by
+// creating its resource, and completes when the readyWhen expressions of the
+// resource are satisfied, or, without readyWhen expressions, when the resource
+// reports a Complete condition, like Jobs do.
+type Hook struct {
+	// Phase is when the hook runs. PreCreate hooks run before the other
+	// resources of the instance are created or updated, and block them until
+	// they complete. PostReady hooks run once the other resources of the
+	// instance are ready. PreDelete hooks run when the instance is deleted,
+	// and block the deletion of its resources until they complete.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=PreCreate;PostReady;PreDelete
+	Phase HookPhase `json:"phase"`
+	// CleanupPolicy is what happens to the resource of the hook once the hook
+	// completed. Retain keeps it until the hook runs again or the instance is
+	// deleted. DeleteOnCompletion deletes it as soon as the hook completed.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="Retain"
+	// +kubebuilder:validation:Enum=Retain;DeleteOnCompletion
+	CleanupPolicy HookCleanupPolicy `json:"cleanupPolicy,omitempty"`
+}
+
+// HookPhase is when a hook runs.
+type HookPhase string
+
+const (
+	// HookPhasePreCreate hooks run before the other resources of the instance
+	// are created or updated.
+	HookPhasePreCreate HookPhase = "PreCreate"
+	// HookPhasePostReady hooks run once the other resources of the instance
+	// are ready.
+	HookPhasePostReady HookPhase = "PostReady"
+	// HookPhasePreDelete hooks run before the resources of the instance are
+	// deleted.
+	HookPhasePreDelete HookPhase = "PreDelete"
+)
+
+// HookCleanupPolicy is what happens to the resource of a completed hook.
+type HookCleanupPolicy string
+
+const (
+	// HookCleanupRetain keeps the resource of completed hooks.
+	HookCleanupRetain HookCleanupPolicy = "Retain"
+	// HookCleanupDeleteOnCompletion deletes the resource of hooks once they
+	// completed.
+	HookCleanupDeleteOnCompletion HookCleanupPolicy = "DeleteOnCompletion"
+)
@@ -0,0 +1,66 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReconcilePolicy tunes how often the instances of a ResourceGraphDefinition
+// are reconciled, for instance to poll slow cloud resources less often than
+// the resources of an application. Unset fields keep the settings of the
+// controller.
+type ReconcilePolicy struct {
+	// ResyncPeriod is the interval at which every instance is reconciled,
+	// even if nothing changed, to correct drift of its resources.
+	// Example: "30m"
+	//
+	// +kubebuilder:validation:Optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+	// ReadinessPollInterval is the interval at which instances waiting for
+	// their resources to become ready are reconciled again.
+	// Example: "1m"
+	//
+	// +kubebuilder:validation:Optional
+	ReadinessPollInterval *metav1.Duration `json:"readinessPollInterval,omitempty"`
+	// RetryBackoff bounds the delay before retrying an instance whose
+	// reconciliation failed.
+	//
+	// +kubebuilder:validation:Optional
+	RetryBackoff *RetryBackoff `json:"retryBackoff,omitempty"`
+	// Workers is the maximum number of instances reconciled at once, out of
+	// the workers of the controller.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Workers *int32 `json:"workers,omitempty"`
+}
+
+// RetryBackoff bounds the exponential backoff of failing instances.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.minDelay) || !has(self.maxDelay) || duration(self.minDelay) <= duration(self.maxDelay)",message="minDelay must not exceed maxDelay"
+type RetryBackoff struct {
+	// MinDelay is the delay before the first retry. It doubles at each
+	// failure.
+	// Example: "5s"
+	//
+	// +kubebuilder:validation:Optional
+	MinDelay *metav1.Duration `json:"minDelay,omitempty"`
+	// MaxDelay is the maximum delay between retries.
+	// Example: "10m"
+	//
+	// +kubebuilder:validation:Optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+}
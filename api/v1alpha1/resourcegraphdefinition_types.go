@@ -35,8 +35,51 @@ type ResourceGraphDefinitionSpec struct {
 	//
 	// +kubebuilder:validation:Optional
 	Resources []*Resource `json:"resources,omitempty"`
+	// Rollout controls how changes to the ResourceGraphDefinition are rolled
+	// out across existing instances. If omitted, every instance is reconciled
+	// with a change as soon as it is accepted.
+	//
+	// +kubebuilder:validation:Optional
+	Rollout *RolloutPolicy `json:"rollout,omitempty"`
+	// Completion turns the instances into one-shot workflows, reaching a
+	// terminal Succeeded or Failed state. If omitted, instances are
+	// reconciled for as long as they exist.
+	//
+	// +kubebuilder:validation:Optional
+	Completion *CompletionPolicy `json:"completion,omitempty"`
+	// PriorityClass sets the share of the controller workers the instances
+	// get when instances of several ResourceGraphDefinitions are waiting to
+	// be reconciled. Waiting instances of each ResourceGraphDefinition are
+	// served in turns, High serving twice as many instances per turn as
+	// Normal, and Normal twice as many as Low.
+	// If omitted, defaults to Normal.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Low;Normal;High
+	PriorityClass PriorityClass `json:"priorityClass,omitempty"`
+	// Reconcile tunes how often the instances are reconciled: their resync
+	// period, how often instances waiting for readiness are polled, the
+	// backoff of failing instances and their share of the workers. Settings
+	// overridden in the configuration file of the controller take precedence.
+	// If omitted, the settings of the controller apply.
+	//
+	// +kubebuilder:validation:Optional
+	Reconcile *ReconcilePolicy `json:"reconcile,omitempty"`
 }
 
+// PriorityClass is the share of the controller workers the instances of a
+// ResourceGraphDefinition get.
+type PriorityClass string
+
+const (
+	// PriorityClassLow serves half as many instances per turn as Normal.
+	PriorityClassLow PriorityClass = "Low"
+	// PriorityClassNormal is the default priority class.
+	PriorityClassNormal PriorityClass = "Normal"
+	// PriorityClassHigh serves twice as many instances per turn as Normal.
+	PriorityClassHigh PriorityClass = "High"
+)
+
 // Schema defines the structure and behavior of instances created from a ResourceGraphDefinition.
 // It specifies the API group, version, and kind for the generated CRD, along with the
 // spec and status schemas using SimpleSchema syntax. You can also define custom types,
@@ -69,6 +112,16 @@ type Schema struct {
 	// +kubebuilder:default="kro.run"
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="group is immutable"
 	Group string `json:"group,omitempty"`
+	// Scope is the scope of the generated CRD. Cluster scoped instances have
+	// no namespace: their namespaced resources must either set a namespace
+	// explicitly or are created in the "default" namespace.
+	// If omitted, defaults to "Namespaced". This field is immutable after creation.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="Namespaced"
+	// +kubebuilder:validation:Enum=Namespaced;Cluster
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="scope is immutable"
+	Scope InstanceScope `json:"scope,omitempty"`
 	// Spec defines the schema for the instance's spec section using SimpleSchema syntax.
 	// This becomes the OpenAPI schema for instances of the generated CRD.
 	// Use SimpleSchema's concise syntax to define fields, types, defaults, and validations.
@@ -99,6 +152,89 @@ type Schema struct {
 	// Metadata to apply to the generated CRD
 	// +kubebuilder:validation:Optional
 	Metadata *CRDMetadata `json:"metadata,omitempty"`
+
+	// Versions declares additional versions served by the generated CRD.
+	// The version named by APIVersion is always the storage version, and is
+	// the only version the instance controller reconciles. Every additional
+	// version is converted to and from the storage version by kro's
+	// conversion webhook, using the CEL field mappings declared on it.
+	// Status is shared by all versions.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=map
+	// +listMapKey=name
+	Versions []SchemaVersion `json:"versions,omitempty"`
+}
+
+// InstanceScope defines the scope of the instances of a generated CRD.
+type InstanceScope string
+
+const (
+	// InstanceScopeNamespaced means instances live in a namespace.
+	InstanceScopeNamespaced InstanceScope = "Namespaced"
+	// InstanceScopeCluster means instances are cluster scoped.
+	InstanceScopeCluster InstanceScope = "Cluster"
+)
+
+// IsClusterScoped returns true if the schema generates a cluster scoped CRD.
+func (s *Schema) IsClusterScoped() bool {
+	return s.Scope == InstanceScopeCluster
+}
+
+// SchemaVersion declares an additional version of the generated CRD. Each
+// version has its own spec schema and a set of CEL field mappings used to
+// convert objects between this version and the storage version.
+type SchemaVersion struct {
+	// Name is the version identifier, following the same conventions as
+	// Schema.APIVersion. It must differ from Schema.APIVersion.
+	// Example: "v1alpha1", "v1beta1"
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^v[0-9]+(alpha[0-9]+|beta[0-9]+)?$`
+	Name string `json:"name"`
+	// Spec defines the schema for the instance's spec section in this version
+	// using SimpleSchema syntax. Custom types declared in Schema.Types can be
+	// referenced here as well.
+	//
+	// +kubebuilder:validation:Optional
+	Spec runtime.RawExtension `json:"spec,omitempty"`
+	// Served controls whether this version is served by the API server.
+	// Defaults to true.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	Served *bool `json:"served,omitempty"`
+	// Deprecated marks this version as deprecated. Clients using it receive
+	// a warning from the API server.
+	//
+	// +kubebuilder:validation:Optional
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Conversion holds the CEL field mappings used to convert objects between
+	// this version and the storage version. Fields that are not mapped are
+	// copied as-is; fields unknown to the target version are pruned by the
+	// API server.
+	//
+	// +kubebuilder:validation:Optional
+	Conversion *VersionConversion `json:"conversion,omitempty"`
+}
+
+// VersionConversion maps field paths to CEL expressions. The expressions are
+// evaluated against the object being converted, which is available as `self`.
+// An expression that evaluates to null removes the target field.
+type VersionConversion struct {
+	// ToStorage maps storage version field paths to CEL expressions evaluated
+	// against an object of this version.
+	// Example: {"spec.replicas": "self.spec.size"}
+	//
+	// +kubebuilder:validation:Optional
+	ToStorage map[string]string `json:"toStorage,omitempty"`
+	// FromStorage maps field paths of this version to CEL expressions evaluated
+	// against an object of the storage version.
+	// Example: {"spec.size": "self.spec.replicas"}
+	//
+	// +kubebuilder:validation:Optional
+	FromStorage map[string]string `json:"fromStorage,omitempty"`
 }
 
 // CRDMetadata defines metadata to be applied to the generated CRD.
@@ -112,23 +248,37 @@ type CRDMetadata struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// ExternalRefMetadata identifies the external resources to reference, either
+// a single object by name or every object matching a label selector.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.name) != has(self.selector)",message="exactly one of name or selector must be set"
 type ExternalRefMetadata struct {
 	// Name is the name of the external resource to reference.
-	// This field is required.
+	// Mutually exclusive with Selector.
 	//
-	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Optional
 	Name string `json:"name,omitempty"`
 	// Namespace is the namespace of the external resource.
 	// If empty, the instance's namespace will be used.
 	//
 	// +kubebuilder:validation:Optional
 	Namespace string `json:"namespace,omitempty"`
+	// Selector selects every object of the given kind matching the labels.
+	// When set, the external reference resolves to a list of objects that
+	// can be iterated over in forEach or aggregated in CEL expressions.
+	// Label values can contain CEL expressions. Mutually exclusive with Name.
+	//
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 }
 
 // ExternalRef is a reference to an external resource that already exists in the cluster.
 // It allows you to read and use existing resources in your ResourceGraphDefinition
 // without creating them. The referenced resource's fields can be accessed using CEL
 // expressions in other resources.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.default) || (has(self.optional) && self.optional)",message="default can only be set on optional external references"
+// +kubebuilder:validation:XValidation:rule="!has(self.optional) || !self.optional || has(self.metadata.name)",message="optional external references must select a resource by name"
 type ExternalRef struct {
 	// APIVersion is the API version of the external resource.
 	// Example: "v1" for core resources, "apps/v1" for Deployments.
@@ -140,10 +290,24 @@ type ExternalRef struct {
 	//
 	// +kubebuilder:validation:Required
 	Kind string `json:"kind"`
-	// Metadata contains the name and optional namespace of the external resource.
+	// Metadata identifies the external resources, by name or label selector,
+	// and their optional namespace.
 	//
 	// +kubebuilder:validation:Required
 	Metadata ExternalRefMetadata `json:"metadata"`
+	// Optional allows the external resource to be missing. When it does not
+	// exist, the reference resolves to Default if set, or to an empty optional
+	// value otherwise, instead of blocking the resources that depend on it.
+	// Optional references must select a single resource by name.
+	//
+	// +kubebuilder:validation:Optional
+	Optional bool `json:"optional,omitempty"`
+	// Default is the object used in place of the external resource when it
+	// does not exist. It follows the schema of the referenced kind and can
+	// contain CEL expressions. Only allowed on optional references.
+	//
+	// +kubebuilder:validation:Optional
+	Default *runtime.RawExtension `json:"default,omitempty"`
 }
 
 // ForEachDimension defines a single expansion axis in a forEach block.
@@ -202,8 +366,55 @@ type Resource struct {
 	//
 	// +kubebuilder:validation:Optional
 	ForEach []ForEachDimension `json:"forEach,omitempty"`
+	// Drift controls how changes made to the resource outside of kro are
+	// handled. By default, they are overwritten on the next reconciliation.
+	// Not supported on external references.
+	//
+	// +kubebuilder:validation:Optional
+	Drift *DriftPolicy `json:"drift,omitempty"`
+	// IgnoreFields lists field paths kro only sets when creating the resource.
+	// Once another field manager, such as a HorizontalPodAutoscaler or a
+	// mutating webhook, manages one of these fields, kro stops applying it and
+	// leaves it to that manager.
+	// Example: ["spec.replicas"]
+	//
+	// +kubebuilder:validation:Optional
+	IgnoreFields []string `json:"ignoreFields,omitempty"`
+	// UpdatePolicy controls how changes to the template are rolled out to the
+	// resource. Update applies them in place. Recreate deletes the resource
+	// and creates it again whenever the template changes.
+	// RecreateOnImmutableError updates the resource in place, and only
+	// recreates it when the update is rejected because it changes an
+	// immutable field, such as the template of a Job.
+	// Not supported on external references.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="Update"
+	// +kubebuilder:validation:Enum=Update;Recreate;RecreateOnImmutableError
+	UpdatePolicy UpdatePolicy `json:"updatePolicy,omitempty"`
+	// Hook makes this resource a lifecycle hook, such as a Job migrating a
+	// database before the application is updated, or backing it up before
+	// the instance is deleted. Not supported on external references and
+	// collections.
+	//
+	// +kubebuilder:validation:Optional
+	Hook *Hook `json:"hook,omitempty"`
 }
 
+// UpdatePolicy is how changes to the template of a resource are rolled out.
+type UpdatePolicy string
+
+const (
+	// UpdatePolicyUpdate updates resources in place.
+	UpdatePolicyUpdate UpdatePolicy = "Update"
+	// UpdatePolicyRecreate deletes and creates resources again whenever
+	// their template changes.
+	UpdatePolicyRecreate UpdatePolicy = "Recreate"
+	// UpdatePolicyRecreateOnImmutableError deletes and creates resources
+	// again when an update changes an immutable field.
+	UpdatePolicyRecreateOnImmutableError UpdatePolicy = "RecreateOnImmutableError"
+)
+
 // ResourceGraphDefinitionState defines the state of the resource graph definition.
 type ResourceGraphDefinitionState string
 
@@ -231,6 +442,9 @@ type ResourceGraphDefinitionStatus struct {
 	// Resources provides detailed information about each resource in the graph,
 	// including their dependencies.
 	Resources []ResourceInformation `json:"resources,omitempty"`
+	// Rollout reports the progress of the rollout of the latest change across
+	// existing instances. It is only set if a rollout policy is declared.
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
 }
 
 // ResourceInformation provides detailed information about a specific resource
@@ -0,0 +1,109 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RolloutPolicy controls how a change to a ResourceGraphDefinition is rolled
+// out across its existing instances. The change is first rolled out to the
+// canary instances, then to batches of the remaining instances. A batch only
+// starts once every instance of the previous batches is Ready with the new
+// revision. Instances that are not rolled out yet keep being reconciled with
+// the last revision that was completely rolled out.
+type RolloutPolicy struct {
+	// Canary selects the instances the change is rolled out to first. If
+	// omitted, the rollout starts with the first batch.
+	//
+	// +kubebuilder:validation:Optional
+	Canary *metav1.LabelSelector `json:"canary,omitempty"`
+	// BatchPercentage is the percentage of the instances that are not
+	// canaries added to the rollout by each batch.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=25
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	BatchPercentage int32 `json:"batchPercentage,omitempty"`
+	// ProgressDeadlineSeconds is the time the instances of a batch have to
+	// become Ready before the batch is considered failed.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=1
+	ProgressDeadlineSeconds int32 `json:"progressDeadlineSeconds,omitempty"`
+	// OnFailure is the action taken when a batch fails. Halt stops the
+	// rollout and leaves the instances it reached on the new revision.
+	// Rollback moves every instance back to the previous revision.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="Halt"
+	// +kubebuilder:validation:Enum=Halt;Rollback
+	OnFailure RolloutFailurePolicy `json:"onFailure,omitempty"`
+}
+
+// RolloutFailurePolicy is the action taken when a rollout batch fails.
+type RolloutFailurePolicy string
+
+const (
+	// RolloutFailurePolicyHalt stops the rollout.
+	RolloutFailurePolicyHalt RolloutFailurePolicy = "Halt"
+	// RolloutFailurePolicyRollback moves every instance back to the previous
+	// revision.
+	RolloutFailurePolicyRollback RolloutFailurePolicy = "Rollback"
+)
+
+// RolloutPhase is the phase of a rollout.
+type RolloutPhase string
+
+const (
+	// RolloutPhaseProgressing means the rollout is in progress.
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhaseCompleted means every instance was rolled out.
+	RolloutPhaseCompleted RolloutPhase = "Completed"
+	// RolloutPhaseHalted means a batch failed and the rollout was stopped.
+	RolloutPhaseHalted RolloutPhase = "Halted"
+	// RolloutPhaseRolledBack means a batch failed and every instance was
+	// moved back to the previous revision.
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+)
+
+// RolloutStatus reports the progress of a rollout.
+type RolloutStatus struct {
+	// Revision is the revision being rolled out.
+	Revision int64 `json:"revision,omitempty"`
+	// StableRevision is the last revision that was completely rolled out.
+	// Instances the rollout has not reached yet are reconciled with it.
+	StableRevision int64 `json:"stableRevision,omitempty"`
+	// Phase is the phase of the rollout.
+	Phase RolloutPhase `json:"phase,omitempty"`
+	// Batch is the current batch. Batch 0 holds the canary instances.
+	Batch int32 `json:"batch,omitempty"`
+	// StartTime is the time the rollout started. Instances created after it
+	// are reconciled with the new revision right away.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// BatchStartTime is the time the current batch started.
+	BatchStartTime *metav1.Time `json:"batchStartTime,omitempty"`
+	// TotalInstances is the number of instances.
+	TotalInstances int32 `json:"totalInstances,omitempty"`
+	// UpdatedInstances is the number of instances the rollout reached.
+	UpdatedInstances int32 `json:"updatedInstances,omitempty"`
+	// ReadyInstances is the number of instances the rollout reached that are
+	// Ready with the new revision.
+	ReadyInstances int32 `json:"readyInstances,omitempty"`
+	// Message describes the last failure of the rollout.
+	Message string `json:"message,omitempty"`
+}
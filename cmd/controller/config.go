@@ -0,0 +1,94 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+
+	kroconfig "github.com/kubernetes-sigs/kro/pkg/config"
+	"github.com/kubernetes-sigs/kro/pkg/dynamiccontroller"
+)
+
+// configFlags maps the flags having a setting in the configuration file to a
+// function copying the setting from src to dst.
+var configFlags = map[string]func(dst, src *kroconfig.KroControllerConfiguration){
+	"client-qps": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.Client.QPS = src.Client.QPS
+	},
+	"client-burst": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.Client.Burst = src.Client.Burst
+	},
+	"resource-graph-definition-concurrent-reconciles": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.ResourceGraphDefinitionController.Workers = src.ResourceGraphDefinitionController.Workers
+	},
+	"dynamic-controller-concurrent-reconciles": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.DynamicController.Workers = src.DynamicController.Workers
+	},
+	"dynamic-controller-default-resync-period": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.DynamicController.ResyncPeriod = src.DynamicController.ResyncPeriod
+	},
+	"dynamic-controller-default-queue-max-retries": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.DynamicController.QueueMaxRetries = src.DynamicController.QueueMaxRetries
+	},
+	"dynamic-controller-progress-deadline": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.DynamicController.ProgressDeadline = src.DynamicController.ProgressDeadline
+	},
+	"dynamic-controller-rate-limiter-min-delay": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.DynamicController.RateLimiter.MinDelay = src.DynamicController.RateLimiter.MinDelay
+	},
+	"dynamic-controller-rate-limiter-max-delay": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.DynamicController.RateLimiter.MaxDelay = src.DynamicController.RateLimiter.MaxDelay
+	},
+	"dynamic-controller-rate-limiter-rate-limit": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.DynamicController.RateLimiter.RateLimit = src.DynamicController.RateLimiter.RateLimit
+	},
+	"dynamic-controller-rate-limiter-burst-limit": func(dst, src *kroconfig.KroControllerConfiguration) {
+		dst.DynamicController.RateLimiter.BurstLimit = src.DynamicController.RateLimiter.BurstLimit
+	},
+}
+
+// loadConfiguration returns the configuration built from the flags, with the
+// configuration file at path overriding the flags left to their default.
+func loadConfiguration(path string, fromFlags *kroconfig.KroControllerConfiguration) (*kroconfig.KroControllerConfiguration, error) {
+	cfg := *fromFlags
+	if path == "" {
+		return &cfg, nil
+	}
+	if err := kroconfig.LoadFile(path, &cfg); err != nil {
+		return nil, err
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if set, ok := configFlags[f.Name]; ok {
+			set(&cfg, fromFlags)
+		}
+	})
+	return &cfg, nil
+}
+
+// dynamicControllerConfig returns the settings of the dynamic controller from
+// the configuration.
+func dynamicControllerConfig(cfg *kroconfig.KroControllerConfiguration) dynamiccontroller.Config {
+	dyn := cfg.DynamicController
+	return dynamiccontroller.Config{
+		Workers:          dyn.Workers,
+		ResyncPeriod:     dyn.ResyncPeriod.Duration,
+		QueueMaxRetries:  dyn.QueueMaxRetries,
+		MinRetryDelay:    dyn.RateLimiter.MinDelay.Duration,
+		MaxRetryDelay:    dyn.RateLimiter.MaxDelay.Duration,
+		RateLimit:        dyn.RateLimiter.RateLimit,
+		BurstLimit:       dyn.RateLimiter.BurstLimit,
+		ProgressDeadline: dyn.ProgressDeadline.Duration,
+	}
+}
@@ -15,11 +15,17 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -28,13 +34,21 @@ import (
 	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	xv1alpha1 "github.com/kubernetes-sigs/kro/api/v1alpha1"
+	kroadmission "github.com/kubernetes-sigs/kro/pkg/admission"
 	kroclient "github.com/kubernetes-sigs/kro/pkg/client"
+	kroconfig "github.com/kubernetes-sigs/kro/pkg/config"
 	resourcegraphdefinitionctrl "github.com/kubernetes-sigs/kro/pkg/controller/resourcegraphdefinition"
+	"github.com/kubernetes-sigs/kro/pkg/conversion"
+	"github.com/kubernetes-sigs/kro/pkg/debug"
 	"github.com/kubernetes-sigs/kro/pkg/dynamiccontroller"
 	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/sharding"
+	"github.com/kubernetes-sigs/kro/pkg/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -53,6 +67,7 @@ func init() {
 
 func main() {
 	var (
+		configFile                                  string
 		metricsAddr                                 string
 		enableLeaderElection                        bool
 		enableControllerWarmup                      bool
@@ -70,12 +85,47 @@ func main() {
 		resyncPeriod            int
 		queueMaxRetries         int
 		gracefulShutdownTimeout time.Duration
+		progressDeadline        time.Duration
+		watchNamespaces         string
+		watchNamespaceSelector  string
 		// var dynamicControllerDefaultResyncPeriod int
 		qps   float64
 		burst int
+		// webhook parameters
+		enableConversionWebhook   bool
+		webhookPort               int
+		webhookCertDir            string
+		webhookServiceName        string
+		webhookServiceNamespace   string
+		webhookServicePort        int
+		conversionWebhookCABundle string
+		enableValidatingWebhook   bool
+		enableInstanceWebhook     bool
+		// sharding parameters
+		enableSharding         bool
+		shardingKey            string
+		shardingLeaseNamespace string
+		shardingLeaseDuration  time.Duration
+		shardingRenewPeriod    time.Duration
+		// tracing parameters
+		tracingEndpoint    string
+		tracingSampleRatio float64
+		// debug parameters
+		enableDebugEndpoint bool
+		// metrics parameters
+		metricsSecure  bool
+		metricsCertDir string
 	)
 
+	flag.StringVar(&configFile, "config", "",
+		"Path to a "+kroconfig.Kind+" file. It is reloaded when it changes, and flags set explicitly "+
+			"override its settings.")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8078", "The address the metric endpoint binds to.")
+	flag.BoolVar(&metricsSecure, "metrics-secure", false,
+		"Serve the metrics endpoint over HTTPS. Required by --enable-debug-endpoint.")
+	flag.StringVar(&metricsCertDir, "metrics-cert-dir", "",
+		"Directory holding the metrics server tls.crt and tls.key. "+
+			"A self-signed certificate is generated if empty.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8079", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
@@ -115,11 +165,68 @@ func main() {
 		"interval at which the controller will re list resources even with no changes, in seconds.")
 	flag.IntVar(&queueMaxRetries, "dynamic-controller-default-queue-max-retries", 20,
 		"maximum number of retries for an item in the queue will be retried before being dropped")
+	flag.DurationVar(&progressDeadline, "dynamic-controller-progress-deadline", 5*time.Minute,
+		"How long the dynamic controller may reconcile no instance while instances are queued, or fail to watch "+
+			"a resource, before the health probe fails. 0 disables the check.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma separated list of namespaces to serve instances and manage resources in. "+
+			"By default, every namespace is served.")
+	flag.StringVar(&watchNamespaceSelector, "watch-namespace-selector", "",
+		"Label selector of the namespaces to serve instances and manage resources in. "+
+			"Cannot be used with --watch-namespaces.")
 	// qps and burst
 	flag.Float64Var(&qps, "client-qps", 100, "The number of queries per second to allow")
 	flag.IntVar(&burst, "client-burst", 150,
 		"The number of requests that can be stored for processing before the server starts enforcing the QPS limit")
 
+	// webhook parameters
+	flag.BoolVar(&enableConversionWebhook, "enable-conversion-webhook", false,
+		"Serve the conversion webhook for ResourceGraphDefinitions declaring multiple schema versions.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server listens on.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "",
+		"Directory holding the webhook server tls.crt and tls.key. "+
+			"Defaults to <temp-dir>/k8s-webhook-server/serving-certs.")
+	flag.StringVar(&webhookServiceName, "webhook-service-name", "kro-webhook",
+		"Name of the service the API server uses to reach the webhook server.")
+	flag.StringVar(&webhookServiceNamespace, "webhook-service-namespace", "kro-system",
+		"Namespace of the service the API server uses to reach the webhook server.")
+	flag.IntVar(&webhookServicePort, "webhook-service-port", 443,
+		"Port of the service the API server uses to reach the webhook server.")
+	flag.StringVar(&conversionWebhookCABundle, "conversion-webhook-ca-bundle-file", "",
+		"Path to the PEM encoded CA bundle injected into multi-version instance CRDs. "+
+			"Required by --enable-conversion-webhook.")
+	flag.BoolVar(&enableValidatingWebhook, "enable-validating-webhook", false,
+		"Serve the validating webhook rejecting invalid ResourceGraphDefinitions at admission time.")
+	flag.BoolVar(&enableInstanceWebhook, "enable-instance-validating-webhook", false,
+		"Serve the validating webhook rejecting instances whose resources fail to render at admission time.")
+
+	// sharding parameters
+	flag.BoolVar(&enableSharding, "sharding-enabled", false,
+		"Shard the reconciliation of instances across the replicas of the controller. "+
+			"Requires --leader-elect to be set.")
+	flag.StringVar(&shardingKey, "sharding-key", string(sharding.KeyInstance),
+		"What instances are assigned to replicas by: instance or namespace.")
+	flag.StringVar(&shardingLeaseNamespace, "sharding-lease-namespace", "",
+		"Namespace of the coordination.k8s.io/lease objects of the replicas sharing instances. "+
+			"By default it will try to use the namespace of the service account mounted to the controller pod.")
+	flag.DurationVar(&shardingLeaseDuration, "sharding-lease-duration", 15*time.Second,
+		"Duration after which the instances of a replica that stopped renewing its lease move to other replicas.")
+	flag.DurationVar(&shardingRenewPeriod, "sharding-renew-period", 5*time.Second,
+		"Interval at which replicas renew their lease and list the other replicas.")
+
+	// tracing parameters
+	flag.StringVar(&tracingEndpoint, "tracing-otlp-endpoint", "",
+		"URL of the OTLP/HTTP collector reconciliation traces are exported to, such as "+
+			"http://otel-collector:4318. Tracing is disabled by default.")
+	flag.Float64Var(&tracingSampleRatio, "tracing-sample-ratio", 0.1,
+		"Ratio of the reconciliations traced, between 0 and 1.")
+
+	// debug parameters
+	flag.BoolVar(&enableDebugEndpoint, "enable-debug-endpoint", false,
+		"Serve the internals of the controller as JSON at "+debug.Path+" on the metrics server. "+
+			"Requests are authenticated and authorized against the Kubernetes API server. "+
+			"Requires --metrics-secure.")
+
 	opts := zap.Options{
 		Development: true,
 	}
@@ -130,9 +237,88 @@ func main() {
 	rootLogger := zap.New(zap.UseFlagOptions(&opts))
 	ctrl.SetLogger(rootLogger)
 
+	// Callers of the debug endpoint send their bearer token.
+	if enableDebugEndpoint && !metricsSecure {
+		setupLog.Error(nil, "--enable-debug-endpoint requires --metrics-secure")
+		os.Exit(1)
+	}
+
+	if watchNamespaces != "" && watchNamespaceSelector != "" {
+		setupLog.Error(nil, "--watch-namespaces and --watch-namespace-selector are mutually exclusive")
+		os.Exit(1)
+	}
+	var namespaces []string
+	for _, ns := range strings.Split(watchNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	var namespaceSelector labels.Selector
+	if watchNamespaceSelector != "" {
+		var err error
+		if namespaceSelector, err = labels.Parse(watchNamespaceSelector); err != nil {
+			setupLog.Error(err, "invalid namespace selector")
+			os.Exit(1)
+		}
+	}
+
+	if enableSharding && !enableLeaderElection {
+		setupLog.Error(nil, "--sharding-enabled requires --leader-elect")
+		os.Exit(1)
+	}
+	if key := sharding.Key(shardingKey); key != sharding.KeyInstance && key != sharding.KeyNamespace {
+		setupLog.Error(nil, "invalid sharding key", "key", shardingKey)
+		os.Exit(1)
+	}
+
+	// Settings of the configuration file are overridden by the flags set
+	// explicitly.
+	fromFlags := &kroconfig.KroControllerConfiguration{
+		TypeMeta: metav1.TypeMeta{APIVersion: kroconfig.APIVersion, Kind: kroconfig.Kind},
+		Client: kroconfig.ClientConfiguration{
+			QPS:   float32(qps),
+			Burst: burst,
+		},
+		ResourceGraphDefinitionController: kroconfig.ResourceGraphDefinitionControllerConfiguration{
+			Workers: resourceGraphDefinitionConcurrentReconciles,
+		},
+		DynamicController: kroconfig.DynamicControllerConfiguration{
+			Workers:          dynamicControllerConcurrentReconciles,
+			ResyncPeriod:     metav1.Duration{Duration: time.Duration(resyncPeriod) * time.Second},
+			QueueMaxRetries:  queueMaxRetries,
+			ProgressDeadline: metav1.Duration{Duration: progressDeadline},
+			RequeueDuration:  metav1.Duration{Duration: 3 * time.Second},
+			RateLimiter: kroconfig.RateLimiterConfiguration{
+				MinDelay:   metav1.Duration{Duration: minRetryDelay},
+				MaxDelay:   metav1.Duration{Duration: maxRetryDelay},
+				RateLimit:  rateLimit,
+				BurstLimit: burstLimit,
+			},
+		},
+	}
+	configWatcher, err := kroconfig.NewWatcher(rootLogger, configFile, kroconfig.DefaultPollInterval,
+		func() (*kroconfig.KroControllerConfiguration, error) {
+			return loadConfiguration(configFile, fromFlags)
+		})
+	if err != nil {
+		setupLog.Error(err, "unable to load configuration file")
+		os.Exit(1)
+	}
+	cfg := configWatcher.Current()
+
+	ctx := ctrl.SetupSignalHandler()
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Endpoint:    tracingEndpoint,
+		SampleRatio: tracingSampleRatio,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
 	set, err := kroclient.NewSet(kroclient.Config{
-		QPS:   float32(qps),
-		Burst: burst,
+		QPS:   cfg.Client.QPS,
+		Burst: cfg.Client.Burst,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to create client set")
@@ -142,8 +328,14 @@ func main() {
 
 	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme: scheme,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		}),
 		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
+			BindAddress:   metricsAddr,
+			SecureServing: metricsSecure,
+			CertDir:       metricsCertDir,
 		},
 		Client: client.Options{
 			HTTPClient: set.HTTPClient(),
@@ -175,15 +367,26 @@ func main() {
 		os.Exit(1)
 	}
 
-	dc := dynamiccontroller.NewDynamicController(rootLogger, dynamiccontroller.Config{
-		Workers:         dynamicControllerConcurrentReconciles,
-		ResyncPeriod:    time.Duration(resyncPeriod) * time.Second,
-		QueueMaxRetries: queueMaxRetries,
-		MinRetryDelay:   minRetryDelay,
-		MaxRetryDelay:   maxRetryDelay,
-		RateLimit:       rateLimit,
-		BurstLimit:      burstLimit,
-	}, set.Metadata(), set.RESTMapper())
+	var coordinator *sharding.Coordinator
+	if enableSharding {
+		coordinator, err = newShardCoordinator(set, shardingKey, shardingLeaseNamespace,
+			shardingLeaseDuration, shardingRenewPeriod)
+		if err != nil {
+			setupLog.Error(err, "unable to create shard coordinator")
+			os.Exit(1)
+		}
+	}
+	// Avoid handing a typed nil to the dynamic controller.
+	var shard dynamiccontroller.Shard
+	if coordinator != nil {
+		shard = coordinator
+	}
+
+	dcConfig := dynamicControllerConfig(cfg)
+	dcConfig.Namespaces = namespaces
+	dcConfig.NamespaceSelector = namespaceSelector
+	dcConfig.Shard = shard
+	dc := dynamiccontroller.NewDynamicController(rootLogger, dcConfig, set.Metadata(), set.RESTMapper())
 
 	resourceGraphDefinitionGraphBuilder, err := graph.NewBuilder(restConfig, set.HTTPClient())
 	if err != nil {
@@ -191,37 +394,210 @@ func main() {
 		os.Exit(1)
 	}
 
+	var conversionWebhook *conversion.Webhook
+	if enableConversionWebhook {
+		// Without a CA bundle, the API server can't verify the webhook
+		// server and every conversion fails.
+		if conversionWebhookCABundle == "" {
+			setupLog.Error(nil, "--enable-conversion-webhook requires --conversion-webhook-ca-bundle-file")
+			os.Exit(1)
+		}
+		caBundle, err := os.ReadFile(conversionWebhookCABundle)
+		if err != nil {
+			setupLog.Error(err, "unable to read conversion webhook CA bundle")
+			os.Exit(1)
+		}
+		if len(caBundle) == 0 {
+			setupLog.Error(nil, "conversion webhook CA bundle is empty", "path", conversionWebhookCABundle)
+			os.Exit(1)
+		}
+		path := conversion.WebhookPath
+		port := int32(webhookServicePort)
+		conversionWebhook = conversion.NewWebhook(rootLogger, extv1.WebhookClientConfig{
+			Service: &extv1.ServiceReference{
+				Namespace: webhookServiceNamespace,
+				Name:      webhookServiceName,
+				Path:      &path,
+				Port:      &port,
+			},
+			CABundle: caBundle,
+		})
+		mgr.GetWebhookServer().Register(conversion.WebhookPath, conversionWebhook)
+	}
+
+	if enableValidatingWebhook {
+		mgr.GetWebhookServer().Register(
+			kroadmission.ResourceGraphDefinitionWebhookPath,
+			kroadmission.NewResourceGraphDefinitionWebhook(scheme, kroadmission.NewResourceGraphDefinitionValidator(
+				resourceGraphDefinitionGraphBuilder,
+				set.CRD(kroclient.CRDWrapperConfig{}),
+			)),
+		)
+	}
+
+	var instanceValidator *kroadmission.InstanceValidator
+	if enableInstanceWebhook {
+		instanceValidator = kroadmission.NewInstanceValidator()
+		mgr.GetWebhookServer().Register(
+			kroadmission.InstanceWebhookPath,
+			kroadmission.NewInstanceWebhook(instanceValidator),
+		)
+	}
+
 	rgd := resourcegraphdefinitionctrl.NewResourceGraphDefinitionReconciler(
 		set,
 		allowCRDDeletion,
 		dc,
 		resourceGraphDefinitionGraphBuilder,
-		resourceGraphDefinitionConcurrentReconciles,
+		cfg.ResourceGraphDefinitionController.Workers,
+		conversionWebhook,
+		enableSharding,
+		configWatcher,
 	)
 	if err := rgd.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ResourceGraphDefinition")
 		os.Exit(1)
 	}
 
+	if conversionWebhook != nil || instanceValidator != nil {
+		// Webhook requests reach every replica, not only the leader.
+		webhooks := resourcegraphdefinitionctrl.NewWebhookReconciler(
+			resourceGraphDefinitionGraphBuilder,
+			conversionWebhook,
+			instanceValidator,
+		)
+		if err := webhooks.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ResourceGraphDefinitionWebhooks")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.Add(dc); err != nil {
 		setupLog.Error(err, "unable to add dynamic controller to manager")
 		os.Exit(1)
 	}
 
+	configWatcher.OnChange(func(prev, next *kroconfig.KroControllerConfiguration) {
+		if settings := prev.RestartRequired(next); len(settings) > 0 {
+			setupLog.Info("configuration changes require a restart to apply", "settings", settings)
+		}
+		dc.Reconfigure(dynamicControllerConfig(next))
+		// Apply the overrides of each ResourceGraphDefinition.
+		go func() {
+			if err := rgd.EnqueueAll(ctx); err != nil {
+				setupLog.Error(err, "unable to apply configuration to resource graph definitions")
+			}
+		}()
+	})
+	if err := mgr.Add(configWatcher); err != nil {
+		setupLog.Error(err, "unable to add configuration watcher to manager")
+		os.Exit(1)
+	}
+
+	if coordinator != nil {
+		// Instances moving to this replica are reconciled right away.
+		coordinator.OnChange(dc.Resync)
+		if err := mgr.Add(coordinator); err != nil {
+			setupLog.Error(err, "unable to add shard coordinator to manager")
+			os.Exit(1)
+		}
+	}
+
+	if enableDebugEndpoint {
+		filter, err := filters.WithAuthenticationAndAuthorization(restConfig, set.HTTPClient())
+		if err != nil {
+			setupLog.Error(err, "unable to create debug endpoint filter")
+			os.Exit(1)
+		}
+		handler, err := filter(setupLog.WithValues("path", debug.Path), debug.NewHandler(rootLogger, dc, rgd))
+		if err != nil {
+			setupLog.Error(err, "unable to filter debug endpoint")
+			os.Exit(1)
+		}
+		if err := mgr.AddMetricsServerExtraHandler(debug.Path, handler); err != nil {
+			setupLog.Error(err, "unable to add debug endpoint")
+			os.Exit(1)
+		}
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	if err = mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	if err = mgr.AddHealthzCheck("dynamic-controller", dc.HealthzCheck); err != nil {
+		setupLog.Error(err, "unable to set up dynamic controller health check")
+		os.Exit(1)
+	}
 
 	if err = mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	dcReadyz := dc.ReadyzCheck
+	if dc.NeedLeaderElection() {
+		// Replicas waiting to be elected do not start the dynamic controller.
+		dcReadyz = onceElected(mgr.Elected(), dc.ReadyzCheck)
+	}
+	if err = mgr.AddReadyzCheck("dynamic-controller", dcReadyz); err != nil {
+		setupLog.Error(err, "unable to set up dynamic controller ready check")
+		os.Exit(1)
+	}
 
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctx)
+	// Flush the spans of the last reconciliations.
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "problem shutting down tracing")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// onceElected returns a checker succeeding until the manager is elected
+// leader, and running check afterwards.
+func onceElected(elected <-chan struct{}, check healthz.Checker) healthz.Checker {
+	return func(req *http.Request) error {
+		select {
+		case <-elected:
+			return check(req)
+		default:
+			return nil
+		}
+	}
+}
+
+// serviceAccountNamespaceFile holds the namespace of the service account
+// mounted to the controller pod.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// newShardCoordinator creates the coordinator sharing instances with the other
+// replicas. Replicas are identified by their hostname, the name of their pod.
+func newShardCoordinator(
+	set *kroclient.Set,
+	key, namespace string,
+	leaseDuration, renewPeriod time.Duration,
+) (*sharding.Coordinator, error) {
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostname: %w", err)
+	}
+	if namespace == "" {
+		data, err := os.ReadFile(serviceAccountNamespaceFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the namespace of the service account, set --sharding-lease-namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+	return sharding.NewCoordinator(ctrl.Log, sharding.Config{
+		Client:        set.Kubernetes(),
+		Namespace:     namespace,
+		Group:         "kro",
+		Identity:      identity,
+		Key:           sharding.Key(key),
+		LeaseDuration: leaseDuration,
+		RenewPeriod:   renewPeriod,
+	}), nil
+}
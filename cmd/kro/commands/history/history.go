@@ -0,0 +1,284 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	kroclient "github.com/kubernetes-sigs/kro/pkg/client"
+	"github.com/kubernetes-sigs/kro/pkg/history"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+type HistoryConfig struct {
+	namespace   string
+	revision    int64
+	diff        string
+	toRevision  int64
+	restoreSpec bool
+}
+
+var config = &HistoryConfig{}
+
+func init() {
+	historyCmd.Flags().StringVarP(&config.namespace, "namespace", "n", metav1.NamespaceDefault,
+		"Namespace of the instance")
+	historyCmd.Flags().Int64Var(&config.revision, "revision", 0,
+		"Print the resources recorded in the given revision")
+	historyCmd.Flags().StringVar(&config.diff, "diff", "",
+		"Print the difference between two revisions, e.g. --diff 2,3")
+
+	rollbackCmd.Flags().StringVarP(&config.namespace, "namespace", "n", metav1.NamespaceDefault,
+		"Namespace of the instance")
+	rollbackCmd.Flags().Int64Var(&config.toRevision, "to-revision", 0,
+		"Revision to roll back to")
+	rollbackCmd.Flags().BoolVar(&config.restoreSpec, "restore-spec", false,
+		"Also overwrite the instance spec with the spec recorded in the revision")
+	_ = rollbackCmd.MarkFlagRequired("to-revision")
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history TYPE NAME",
+	Short: "Show the history of an instance",
+	Long: "Show the history of an instance. Every time the resources kro renders for an instance " +
+		"change, a revision is recorded along with the instance spec and the graph revision they " +
+		"were rendered from.",
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		c, err := newInstanceClient(args[0])
+		if err != nil {
+			return err
+		}
+		inst, err := c.get(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		revisions, err := history.List(ctx, c.set.Kubernetes(), inst)
+		if err != nil {
+			return err
+		}
+		if len(revisions) == 0 {
+			return fmt.Errorf("no history found for %s %s", args[0], args[1])
+		}
+
+		switch {
+		case config.diff != "":
+			return printDiff(revisions, config.diff)
+		case config.revision != 0:
+			entry, err := findEntry(revisions, config.revision)
+			if err != nil {
+				return err
+			}
+			return printResources(entry)
+		default:
+			printRevisions(revisions)
+			return nil
+		}
+	},
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback TYPE NAME",
+	Short: "Roll an instance back to a previous revision",
+	Long: "Roll an instance back to a previous revision. The instance is pinned to the graph " +
+		"revision the resources of that revision were rendered from, until the " +
+		metadata.GraphRevisionLabel + " label is removed. The instance spec is left unchanged, " +
+		"unless --restore-spec is set, in which case it is overwritten with the spec recorded in " +
+		"the revision.",
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		c, err := newInstanceClient(args[0])
+		if err != nil {
+			return err
+		}
+		inst, err := c.get(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		revisions, err := history.List(ctx, c.set.Kubernetes(), inst)
+		if err != nil {
+			return err
+		}
+		entry, err := findEntry(revisions, config.toRevision)
+		if err != nil {
+			return err
+		}
+		if entry.GraphRevision == 0 {
+			return fmt.Errorf("revision %d does not record a graph revision", config.toRevision)
+		}
+
+		if config.restoreSpec {
+			fmt.Fprintf(os.Stderr, "Warning: the spec of %s %s is overwritten with the spec recorded in "+
+				"revision %d, changes made to it since are lost.\n", args[0], args[1], config.toRevision)
+		}
+		_, err = history.Rollback(ctx, c.resource, inst, entry, config.restoreSpec)
+		if err != nil {
+			return fmt.Errorf("failed to roll back %s %s: %w", args[0], args[1], err)
+		}
+		fmt.Printf("%s %s rolled back to revision %d (graph revision %d)\n",
+			args[0], args[1], config.toRevision, entry.GraphRevision)
+		if !config.restoreSpec && !equality.Semantic.DeepEqual(entry.Spec, inst.Object["spec"]) {
+			fmt.Printf("The spec differs from the spec recorded in revision %d and was left unchanged: "+
+				"the resources are rendered from the current spec. Use --restore-spec to restore it.\n",
+				config.toRevision)
+		}
+		fmt.Printf("The instance is pinned to graph revision %d. To release it, run:\n"+
+			"  kubectl label %s %s -n %s %s-\n",
+			entry.GraphRevision, args[0], args[1], inst.GetNamespace(), metadata.GraphRevisionLabel)
+		return nil
+	},
+}
+
+// instanceClient reads and writes the instances of a single kind.
+type instanceClient struct {
+	set      kroclient.SetInterface
+	resource dynamic.ResourceInterface
+}
+
+func newInstanceClient(kind string) (*instanceClient, error) {
+	set, err := kroclient.NewSet(kroclient.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client set: %w", err)
+	}
+
+	mapper := set.RESTMapper()
+	gvk, err := mapper.KindFor(schema.ParseGroupResource(kind).WithVersion(""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource type %q: %w", kind, err)
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource type %q: %w", kind, err)
+	}
+
+	var resource dynamic.ResourceInterface = set.Dynamic().Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resource = set.Dynamic().Resource(mapping.Resource).Namespace(config.namespace)
+	}
+	return &instanceClient{set: set, resource: resource}, nil
+}
+
+func (c *instanceClient) get(ctx context.Context, name string) (*unstructured.Unstructured, error) {
+	inst, err := c.resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance %s: %w", name, err)
+	}
+	return inst, nil
+}
+
+func findEntry(revisions []appsv1.ControllerRevision, revision int64) (*history.Entry, error) {
+	for i := range revisions {
+		if revisions[i].Revision == revision {
+			return history.Decode(&revisions[i])
+		}
+	}
+	return nil, fmt.Errorf("revision %d not found", revision)
+}
+
+func printRevisions(revisions []appsv1.ControllerRevision) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tGRAPH REVISION\tGENERATION\tAGE")
+	for _, cr := range revisions {
+		age := duration.HumanDuration(time.Since(cr.CreationTimestamp.Time))
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", cr.Revision,
+			cr.Annotations[history.GraphRevisionAnnotation],
+			cr.Annotations[history.InstanceGenerationAnnotation],
+			age)
+	}
+	_ = w.Flush()
+}
+
+func printResources(entry *history.Entry) error {
+	out, err := renderResources(entry)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+func printDiff(revisions []appsv1.ControllerRevision, spec string) error {
+	from, to, ok := strings.Cut(spec, ",")
+	if !ok {
+		return fmt.Errorf("invalid --diff %q: expected two revisions, e.g. 2,3", spec)
+	}
+	var texts [2]string
+	for i, s := range []string{from, to} {
+		revision, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid revision %q: %w", s, err)
+		}
+		entry, err := findEntry(revisions, revision)
+		if err != nil {
+			return err
+		}
+		if texts[i], err = renderResources(entry); err != nil {
+			return err
+		}
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(texts[0]),
+		B:        difflib.SplitLines(texts[1]),
+		FromFile: "revision " + strings.TrimSpace(from),
+		ToFile:   "revision " + strings.TrimSpace(to),
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to compute diff: %w", err)
+	}
+	fmt.Print(diff)
+	return nil
+}
+
+// renderResources returns the resources recorded in the entry as a multi
+// document YAML stream.
+func renderResources(entry *history.Entry) (string, error) {
+	var b strings.Builder
+	for _, r := range entry.Resources {
+		data, err := yaml.Marshal(r)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal resource: %w", err)
+		}
+		b.WriteString("---\n")
+		b.Write(data)
+	}
+	return b.String(), nil
+}
+
+func AddHistoryCommands(rootCmd *cobra.Command) {
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(rollbackCmd)
+}
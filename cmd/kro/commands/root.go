@@ -18,10 +18,12 @@ import (
 	"github.com/spf13/cobra"
 
 	generate "github.com/kubernetes-sigs/kro/cmd/kro/commands/generate"
+	history "github.com/kubernetes-sigs/kro/cmd/kro/commands/history"
 	validate "github.com/kubernetes-sigs/kro/cmd/kro/commands/validate"
 )
 
 func AddCommands(root *cobra.Command) {
 	generate.AddGenerateCommands(root)
+	history.AddHistoryCommands(root)
 	validate.AddValidateCommands(root)
 }
@@ -21,6 +21,8 @@ import (
 	"github.com/kubernetes-sigs/kro/api/v1alpha1"
 	kroclient "github.com/kubernetes-sigs/kro/pkg/client"
 	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/graph/crd"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
 )
@@ -32,11 +34,17 @@ var validateCmd = &cobra.Command{
 		`if the ResourceGraphDefinition is valid and can be used to create a ResourceGraph.`,
 }
 
-var resourceGroupDefinitionFile string
+var (
+	resourceGroupDefinitionFile string
+	againstFile                 string
+)
 
 func init() {
 	validateRGDCmd.PersistentFlags().StringVarP(&resourceGroupDefinitionFile, "file", "f", "",
 		"Path to the ResourceGroupDefinition file")
+	validateRGDCmd.PersistentFlags().StringVar(&againstFile, "against", "",
+		"Path to a previous version of the ResourceGroupDefinition. If set, the generated CRDs "+
+			"are compared and breaking schema changes are reported")
 }
 
 var validateRGDCmd = &cobra.Command{
@@ -47,18 +55,39 @@ var validateRGDCmd = &cobra.Command{
 			return fmt.Errorf("ResourceGroupDefinition file is required")
 		}
 
-		data, err := os.ReadFile(resourceGroupDefinitionFile)
+		rgd, err := readRGD(resourceGroupDefinitionFile)
 		if err != nil {
-			return fmt.Errorf("failed to read ResourceGroupDefinition file: %w", err)
+			return err
 		}
 
-		var rgd v1alpha1.ResourceGraphDefinition
-		if err = yaml.Unmarshal(data, &rgd); err != nil {
-			return fmt.Errorf("failed to unmarshal ResourceGroupDefinition: %w", err)
+		builder, err := newGraphBuilder()
+		if err != nil {
+			return err
 		}
 
-		if err := validateRGD(&rgd); err != nil {
-			return fmt.Errorf("validation failed: %w", err)
+		rgdGraph, err := builder.NewResourceGraphDefinition(rgd)
+		if err != nil {
+			return fmt.Errorf("validation failed: failed to create ResourceGraphDefinition: %w", err)
+		}
+
+		if againstFile != "" {
+			oldRGD, err := readRGD(againstFile)
+			if err != nil {
+				return err
+			}
+			oldGraph, err := builder.NewResourceGraphDefinition(oldRGD)
+			if err != nil {
+				return fmt.Errorf("failed to build previous ResourceGraphDefinition: %w", err)
+			}
+
+			incompatibilities := crd.CheckCompatibility(oldGraph.CRD, rgdGraph.CRD)
+			for _, incompatibility := range incompatibilities {
+				fmt.Printf("breaking change: %s\n", incompatibility)
+			}
+			if len(incompatibilities) > 0 && !metadata.AllowsBreakingChanges(rgd) {
+				return fmt.Errorf("validation failed: %d breaking changes detected, set the %s=true "+
+					"annotation to allow them", len(incompatibilities), metadata.AllowBreakingChangesAnnotation)
+			}
 		}
 
 		fmt.Println("Validation successful! The ResourceGraphDefinition is valid.")
@@ -66,23 +95,30 @@ var validateRGDCmd = &cobra.Command{
 	},
 }
 
-func validateRGD(rgd *v1alpha1.ResourceGraphDefinition) error {
-	set, err := kroclient.NewSet(kroclient.Config{})
+func readRGD(path string) (*v1alpha1.ResourceGraphDefinition, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to create client set: %w", err)
+		return nil, fmt.Errorf("failed to read ResourceGroupDefinition file: %w", err)
 	}
 
-	builder, err := graph.NewBuilder(set.RESTConfig(), set.HTTPClient())
-	if err != nil {
-		return fmt.Errorf("failed to create graph builder: %w", err)
+	var rgd v1alpha1.ResourceGraphDefinition
+	if err = yaml.Unmarshal(data, &rgd); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ResourceGroupDefinition: %w", err)
 	}
+	return &rgd, nil
+}
 
-	_, err = builder.NewResourceGraphDefinition(rgd)
+func newGraphBuilder() (*graph.Builder, error) {
+	set, err := kroclient.NewSet(kroclient.Config{})
 	if err != nil {
-		return fmt.Errorf("failed to create ResourceGraphDefinition: %w", err)
+		return nil, fmt.Errorf("failed to create client set: %w", err)
 	}
 
-	return nil
+	builder, err := graph.NewBuilder(set.RESTConfig(), set.HTTPClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create graph builder: %w", err)
+	}
+	return builder, nil
 }
 
 func AddValidateCommands(rootCmd *cobra.Command) {
@@ -69,3 +69,17 @@ Selector labels
 app.kubernetes.io/name: {{ include "kro.name" . }}
 app.kubernetes.io/instance: {{ .Release.Name }}
 {{- end }}
+
+{{/*
+Returns true if a webhook is enabled
+*/}}
+{{- define "kro.webhookEnabled" -}}
+{{- if or .Values.webhook.conversion.enabled .Values.webhook.validation.enabled .Values.webhook.instanceValidation.enabled }}true{{- end }}
+{{- end }}
+
+{{/*
+Directory the serving certificate of the webhook server is mounted to
+*/}}
+{{- define "kro.webhookCertDir" -}}
+/var/run/kro/webhook
+{{- end }}
@@ -0,0 +1,212 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+// InstanceWebhookPath is the path the instance validating webhook is served
+// on.
+const InstanceWebhookPath = "/kro/validate-instance"
+
+// InstanceValidator rejects instances that the graph of their kind would fail
+// to render, before they are persisted. A single validator is shared by all the
+// kinds generated by kro; kinds are registered on every replica once their
+// graph is built.
+//
+// Validation is a dry render of the graph: only the expressions depending on
+// the instance alone are evaluated, and the cluster is never read.
+type InstanceValidator struct {
+	mu     sync.RWMutex
+	graphs map[schema.GroupKind]*graph.Graph
+}
+
+var _ admission.Handler = (*InstanceValidator)(nil)
+
+// NewInstanceValidator creates a new instance validator with no registered
+// kinds.
+func NewInstanceValidator() *InstanceValidator {
+	return &InstanceValidator{
+		graphs: make(map[schema.GroupKind]*graph.Graph),
+	}
+}
+
+// NewInstanceWebhook returns the admission webhook serving the given
+// validator.
+func NewInstanceWebhook(validator *InstanceValidator) *admission.Webhook {
+	return &admission.Webhook{Handler: validator}
+}
+
+// Register registers (or replaces) the graph instances of its kind are
+// validated against.
+func (v *InstanceValidator) Register(g *graph.Graph) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.graphs[instanceGroupKind(g)] = g
+}
+
+// Deregister stops validating instances of the given group kind.
+func (v *InstanceValidator) Deregister(gk schema.GroupKind) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.graphs, gk)
+}
+
+func (v *InstanceValidator) graphFor(gk schema.GroupKind) (*graph.Graph, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	g, ok := v.graphs[gk]
+	return g, ok
+}
+
+// Handle implements admission.Handler. The types of the kro API are always
+// allowed. Instances of kinds that are not registered, whose graph is not built
+// yet, are rejected with a server error, so that the failurePolicy of the
+// webhook decides whether they are admitted. Updates leaving the spec unchanged
+// are always allowed, so that instances can always be deleted.
+func (v *InstanceValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	switch req.Operation {
+	case admissionv1.Create, admissionv1.Update:
+	default:
+		return admission.Allowed("")
+	}
+	gk := schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}
+	if isKroType(gk) {
+		return admission.Allowed("")
+	}
+
+	instance := &unstructured.Unstructured{}
+	if err := instance.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if instance.GetNamespace() == "" {
+		instance.SetNamespace(req.Namespace)
+	}
+	if req.Operation == admissionv1.Update {
+		oldInstance := &unstructured.Unstructured{}
+		if err := oldInstance.UnmarshalJSON(req.OldObject.Raw); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if !instance.GetDeletionTimestamp().IsZero() ||
+			equality.Semantic.DeepEqual(oldInstance.Object["spec"], instance.Object["spec"]) {
+			return admission.Allowed("")
+		}
+	}
+
+	g, ok := v.graphFor(gk)
+	if !ok {
+		return admission.Errored(http.StatusServiceUnavailable,
+			fmt.Errorf("the graph of %s is not built yet", gk))
+	}
+
+	// The graph renders instances of its storage version. Instances of other
+	// versions are only validated if they can be converted.
+	if version := g.Instance.Meta.GVR.Version; instance.GroupVersionKind().Version != version {
+		if g.Converter == nil {
+			return admission.Allowed("")
+		}
+		converted, err := g.Converter.Convert(instance, version)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to convert instance to %s: %w", version, err))
+		}
+		instance = converted
+	}
+
+	if errs := validateInstance(g, instance); len(errs) > 0 {
+		statusErr := apierrors.NewInvalid(gk, instance.GetName(), errs)
+		return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &statusErr.ErrStatus,
+		}}
+	}
+	return admission.Allowed("")
+}
+
+// validateInstance dry renders the graph for the instance, and returns an
+// error for every failure, on the instance field the failing expression
+// references.
+func validateInstance(g *graph.Graph, instance *unstructured.Unstructured) field.ErrorList {
+	rt, err := runtime.FromGraph(g, instance)
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("spec"), err)}
+	}
+
+	var errs field.ErrorList
+	for _, dryRunErr := range rt.DryRun() {
+		errs = append(errs, field.Invalid(
+			fieldPathForExpression(dryRunErr.Expression),
+			field.OmitValueType{},
+			dryRunErr.Error(),
+		))
+	}
+	return errs
+}
+
+// schemaFieldPattern matches the first instance field referenced by an
+// expression, e.g. schema.spec.name.
+var schemaFieldPattern = regexp.MustCompile(`\bschema((?:\.[A-Za-z_][A-Za-z0-9_]*)+)`)
+
+// fieldPathForExpression returns the path of the instance field the
+// expression references, or spec if it can't be determined.
+func fieldPathForExpression(expr string) *field.Path {
+	m := schemaFieldPattern.FindStringSubmatchIndex(expr)
+	if m == nil {
+		return field.NewPath("spec")
+	}
+	segments := strings.Split(expr[m[2]+1:m[3]], ".")
+	// Drop member functions, e.g. size in schema.spec.name.size().
+	if m[1] < len(expr) && expr[m[1]] == '(' {
+		segments = segments[:len(segments)-1]
+	}
+	if len(segments) == 0 {
+		return field.NewPath("spec")
+	}
+	return field.NewPath(segments[0], segments[1:]...)
+}
+
+func instanceGroupKind(g *graph.Graph) schema.GroupKind {
+	return schema.GroupKind{Group: g.CRD.Spec.Group, Kind: g.CRD.Spec.Names.Kind}
+}
+
+// isKroType returns true if the group kind is a type of the kro API rather than
+// an instance kind, which may share its group.
+func isKroType(gk schema.GroupKind) bool {
+	if gk.Group != v1alpha1.GroupVersion.Group {
+		return false
+	}
+	switch gk.Kind {
+	case "ResourceGraphDefinition", "GraphRevision":
+		return true
+	}
+	return false
+}
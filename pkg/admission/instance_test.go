@@ -0,0 +1,215 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/graph/variable"
+)
+
+func newInstanceGraph() *graph.Graph {
+	return &graph.Graph{
+		CRD: &extv1.CustomResourceDefinition{
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Group: "kro.run",
+				Names: extv1.CustomResourceDefinitionNames{Kind: "WebApp"},
+			},
+		},
+		Instance: &graph.Node{Meta: graph.NodeMeta{
+			ID:   graph.InstanceNodeID,
+			Type: graph.NodeTypeInstance,
+			GVR:  schema.GroupVersionResource{Group: "kro.run", Version: "v1alpha1", Resource: "webapps"},
+		}},
+		TopologicalOrder: []string{"config"},
+		Nodes: map[string]*graph.Node{
+			"config": {
+				Meta: graph.NodeMeta{ID: "config", Type: graph.NodeTypeResource, Namespaced: true},
+				Template: &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata":   map[string]any{"name": "${schema.spec.name}"},
+				}},
+				Variables: []*variable.ResourceField{{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "metadata.name",
+						Expressions:          []string{"schema.spec.name"},
+						StandaloneExpression: true,
+					},
+					Kind: variable.ResourceVariableKindStatic,
+				}},
+			},
+		},
+	}
+}
+
+func newInstanceRequest(op admissionv1.Operation, name string, oldName string) admission.Request {
+	raw := func(name string) runtime.RawExtension {
+		data, _ := json.Marshal(map[string]any{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "WebApp",
+			"metadata":   map[string]any{"name": "test"},
+			"spec":       map[string]any{"name": name},
+		})
+		return runtime.RawExtension{Raw: data}
+	}
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: op,
+		Kind:      metav1.GroupVersionKind{Group: "kro.run", Version: "v1alpha1", Kind: "WebApp"},
+		Namespace: "default",
+		Name:      "test",
+		Object:    raw(name),
+	}}
+	if op == admissionv1.Update {
+		req.OldObject = raw(oldName)
+	}
+	return req
+}
+
+func TestInstanceValidator(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		register    bool
+		req         admission.Request
+		wantAllowed bool
+		wantCode    int32
+		wantField   string
+		wantMessage string
+	}{
+		{
+			name:        "valid instance",
+			register:    true,
+			req:         newInstanceRequest(admissionv1.Create, "app", ""),
+			wantAllowed: true,
+		},
+		{
+			name:        "invalid computed name",
+			register:    true,
+			req:         newInstanceRequest(admissionv1.Create, "My_App", ""),
+			wantField:   "spec.name",
+			wantMessage: `resource "config" metadata.name: expression "schema.spec.name"`,
+		},
+		{
+			name:     "unregistered kind",
+			req:      newInstanceRequest(admissionv1.Create, "My_App", ""),
+			wantCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:        "unregistered kind update without spec changes",
+			req:         newInstanceRequest(admissionv1.Update, "My_App", "My_App"),
+			wantAllowed: true,
+		},
+		{
+			name:        "update with spec changes",
+			register:    true,
+			req:         newInstanceRequest(admissionv1.Update, "My_App", "app"),
+			wantField:   "spec.name",
+			wantMessage: `invalid name "My_App"`,
+		},
+		{
+			name:        "update without spec changes",
+			register:    true,
+			req:         newInstanceRequest(admissionv1.Update, "My_App", "My_App"),
+			wantAllowed: true,
+		},
+		{
+			name:        "delete",
+			register:    true,
+			req:         newInstanceRequest(admissionv1.Delete, "My_App", ""),
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewInstanceValidator()
+			if tt.register {
+				validator.Register(newInstanceGraph())
+			}
+
+			resp := validator.Handle(ctx, tt.req)
+			if tt.wantAllowed {
+				assert.True(t, resp.Allowed, "response: %+v", resp.Result)
+				return
+			}
+			require.False(t, resp.Allowed)
+			require.NotNil(t, resp.Result)
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, resp.Result.Code)
+				return
+			}
+			assert.Equal(t, metav1.StatusReasonInvalid, resp.Result.Reason)
+			require.NotNil(t, resp.Result.Details)
+			require.Len(t, resp.Result.Details.Causes, 1)
+			assert.Equal(t, tt.wantField, resp.Result.Details.Causes[0].Field)
+			assert.Contains(t, resp.Result.Details.Causes[0].Message, tt.wantMessage)
+		})
+	}
+}
+
+func TestInstanceValidator_KroTypes(t *testing.T) {
+	req := newInstanceRequest(admissionv1.Create, "My_App", "")
+	req.Kind = metav1.GroupVersionKind{Group: "kro.run", Version: "v1alpha1", Kind: "ResourceGraphDefinition"}
+
+	resp := NewInstanceValidator().Handle(context.Background(), req)
+	assert.True(t, resp.Allowed, "response: %+v", resp.Result)
+}
+
+func TestInstanceValidator_Deregister(t *testing.T) {
+	validator := NewInstanceValidator()
+	validator.Register(newInstanceGraph())
+	validator.Deregister(schema.GroupKind{Group: "kro.run", Kind: "WebApp"})
+
+	resp := validator.Handle(context.Background(), newInstanceRequest(admissionv1.Create, "My_App", ""))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), resp.Result.Code)
+}
+
+func TestFieldPathForExpression(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: "schema.spec.name", want: "spec.name"},
+		{expr: "schema.spec.name.lowerAscii() + '-app'", want: "spec.name"},
+		{expr: "schema.metadata.namespace", want: "metadata.namespace"},
+		{expr: "'prefix-' + schema.spec.config.size", want: "spec.config.size"},
+		{expr: "region + '-app'", want: "spec"},
+		{expr: "", want: "spec"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			assert.Equal(t, tt.want, fieldPathForExpression(tt.expr).String())
+		})
+	}
+}
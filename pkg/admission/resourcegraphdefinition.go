@@ -0,0 +1,247 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	kcrd "github.com/kubernetes-sigs/kro/pkg/graph/crd"
+	"github.com/kubernetes-sigs/kro/pkg/graph/fieldpath"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+// ResourceGraphDefinitionWebhookPath is the path the ResourceGraphDefinition
+// validating webhook is served on.
+const ResourceGraphDefinitionWebhookPath = "/kro/validate-resourcegraphdefinition"
+
+// GraphBuilder builds the graph of a ResourceGraphDefinition, validating it
+// in the process.
+type GraphBuilder interface {
+	NewResourceGraphDefinition(rgd *v1alpha1.ResourceGraphDefinition) (*graph.Graph, error)
+}
+
+// CRDGetter retrieves CRDs by name.
+type CRDGetter interface {
+	Get(ctx context.Context, name string) (*extv1.CustomResourceDefinition, error)
+}
+
+// ResourceGraphDefinitionValidator rejects ResourceGraphDefinitions that the
+// controller would fail to reconcile: those the graph builder rejects, and
+// those that would apply breaking changes to the CRD of existing instances.
+//
+// It runs the same checks as the controller, which still reports them in the
+// ResourceGraphValid condition for objects admitted before the webhook was
+// enabled.
+type ResourceGraphDefinitionValidator struct {
+	builder GraphBuilder
+	crds    CRDGetter
+}
+
+var _ admission.Validator[*v1alpha1.ResourceGraphDefinition] = (*ResourceGraphDefinitionValidator)(nil)
+
+// NewResourceGraphDefinitionValidator creates a new ResourceGraphDefinition
+// validator.
+func NewResourceGraphDefinitionValidator(builder GraphBuilder, crds CRDGetter) *ResourceGraphDefinitionValidator {
+	return &ResourceGraphDefinitionValidator{
+		builder: builder,
+		crds:    crds,
+	}
+}
+
+// NewResourceGraphDefinitionWebhook returns the admission webhook serving the
+// given validator.
+func NewResourceGraphDefinitionWebhook(
+	scheme *runtime.Scheme,
+	validator *ResourceGraphDefinitionValidator,
+) *admission.Webhook {
+	return admission.WithValidator(scheme, validator)
+}
+
+// ValidateCreate implements admission.Validator.
+func (v *ResourceGraphDefinitionValidator) ValidateCreate(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+) (admission.Warnings, error) {
+	return nil, v.validate(ctx, rgd)
+}
+
+// ValidateUpdate implements admission.Validator. Updates that leave the spec
+// untouched, such as finalizer or label changes, are always allowed so that a
+// ResourceGraphDefinition that became invalid can still be deleted.
+func (v *ResourceGraphDefinitionValidator) ValidateUpdate(
+	ctx context.Context,
+	oldRGD, newRGD *v1alpha1.ResourceGraphDefinition,
+) (admission.Warnings, error) {
+	if !newRGD.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	if equality.Semantic.DeepEqual(oldRGD.Spec, newRGD.Spec) &&
+		metadata.AllowsBreakingChanges(oldRGD) == metadata.AllowsBreakingChanges(newRGD) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, newRGD)
+}
+
+// ValidateDelete implements admission.Validator.
+func (v *ResourceGraphDefinitionValidator) ValidateDelete(
+	_ context.Context,
+	_ *v1alpha1.ResourceGraphDefinition,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *ResourceGraphDefinitionValidator) validate(ctx context.Context, rgd *v1alpha1.ResourceGraphDefinition) error {
+	processedRGD, err := v.builder.NewResourceGraphDefinition(rgd)
+	if err != nil {
+		return newInvalidError(rgd, field.ErrorList{
+			field.Invalid(fieldPathForBuildError(rgd, err), field.OmitValueType{}, err.Error()),
+		})
+	}
+
+	errs, err := v.checkCompatibility(ctx, rgd, processedRGD.CRD)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if len(errs) > 0 {
+		return newInvalidError(rgd, errs)
+	}
+	return nil
+}
+
+// checkCompatibility compares the CRD generated for the ResourceGraphDefinition
+// with the one in the cluster, and returns an error for every breaking change,
+// unless breaking changes are explicitly allowed.
+func (v *ResourceGraphDefinitionValidator) checkCompatibility(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+	crd *extv1.CustomResourceDefinition,
+) (field.ErrorList, error) {
+	if v.crds == nil || metadata.AllowsBreakingChanges(rgd) {
+		return nil, nil
+	}
+
+	existing, err := v.crds.Get(ctx, crd.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get existing CRD %s: %w", crd.Name, err)
+	}
+	// CRDs owned by another ResourceGraphDefinition, or not owned by kro, are
+	// rejected by the controller with a dedicated error.
+	if !metadata.IsKROOwned(&existing.ObjectMeta) ||
+		existing.Labels[metadata.ResourceGraphDefinitionNameLabel] != rgd.Name {
+		return nil, nil
+	}
+
+	var errs field.ErrorList
+	for _, incompatibility := range kcrd.CheckCompatibility(existing, crd) {
+		errs = append(errs, field.Forbidden(
+			fieldPathForIncompatibility(rgd, incompatibility),
+			fmt.Sprintf("breaking change to CRD %s: %s (set the %s=true annotation to apply it anyway)",
+				crd.Name, incompatibility, metadata.AllowBreakingChangesAnnotation),
+		))
+	}
+	return errs, nil
+}
+
+func newInvalidError(rgd *v1alpha1.ResourceGraphDefinition, errs field.ErrorList) error {
+	gk := v1alpha1.GroupVersion.WithKind("ResourceGraphDefinition").GroupKind()
+	return apierrors.NewInvalid(gk, rgd.Name, errs)
+}
+
+// fieldPathForBuildError returns the field of the ResourceGraphDefinition an
+// error of the graph builder originates from. Errors that the builder doesn't
+// attribute to a field are reported on spec.
+func fieldPathForBuildError(rgd *v1alpha1.ResourceGraphDefinition, err error) *field.Path {
+	specPath := field.NewPath("spec")
+	var fieldErr *graph.FieldError
+	if !errors.As(err, &fieldErr) {
+		return specPath
+	}
+	if fieldErr.ResourceID == "" {
+		return appendFieldPath(specPath, fieldErr.Field)
+	}
+
+	index := slices.IndexFunc(rgd.Spec.Resources, func(r *v1alpha1.Resource) bool {
+		return r.ID == fieldErr.ResourceID
+	})
+	if index < 0 {
+		return specPath.Child("resources")
+	}
+	resourcePath := specPath.Child("resources").Index(index)
+	switch {
+	case fieldErr.Field != "":
+		return appendFieldPath(resourcePath, fieldErr.Field)
+	case rgd.Spec.Resources[index].ExternalRef != nil:
+		return resourcePath.Child("externalRef")
+	default:
+		return resourcePath.Child("template")
+	}
+}
+
+// fieldPathForIncompatibility maps a breaking CRD change to the schema field
+// of the ResourceGraphDefinition that introduced it.
+func fieldPathForIncompatibility(rgd *v1alpha1.ResourceGraphDefinition, i kcrd.Incompatibility) *field.Path {
+	schemaPath := field.NewPath("spec", "schema")
+	switch i.Type {
+	case kcrd.IncompatibilityScopeChanged:
+		return schemaPath.Child("scope")
+	case kcrd.IncompatibilityVersionRemoved:
+		return schemaPath.Child("versions")
+	}
+
+	base := schemaPath
+	if i.Version != rgd.Spec.Schema.APIVersion {
+		index := slices.IndexFunc(rgd.Spec.Schema.Versions, func(v v1alpha1.SchemaVersion) bool {
+			return v.Name == i.Version
+		})
+		if index < 0 {
+			return schemaPath.Child("versions")
+		}
+		base = schemaPath.Child("versions").Index(index)
+	}
+	return appendFieldPath(base, i.Path)
+}
+
+// appendFieldPath appends a kro field path, e.g. spec.containers[0].image, to
+// base. Paths that can't be parsed are ignored.
+func appendFieldPath(base *field.Path, path string) *field.Path {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return base
+	}
+	out := base
+	for _, segment := range segments {
+		if segment.Index >= 0 {
+			out = out.Index(segment.Index)
+			continue
+		}
+		out = out.Child(segment.Name)
+	}
+	return out
+}
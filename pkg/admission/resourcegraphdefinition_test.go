@@ -0,0 +1,327 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	kcrd "github.com/kubernetes-sigs/kro/pkg/graph/crd"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+type fakeBuilder struct {
+	graph *graph.Graph
+	err   error
+	calls int
+}
+
+func (b *fakeBuilder) NewResourceGraphDefinition(_ *v1alpha1.ResourceGraphDefinition) (*graph.Graph, error) {
+	b.calls++
+	return b.graph, b.err
+}
+
+type fakeCRDGetter map[string]*extv1.CustomResourceDefinition
+
+func (g fakeCRDGetter) Get(_ context.Context, name string) (*extv1.CustomResourceDefinition, error) {
+	crd, ok := g[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, name)
+	}
+	return crd, nil
+}
+
+func newRGD(name string, resourceIDs ...string) *v1alpha1.ResourceGraphDefinition {
+	rgd := &v1alpha1.ResourceGraphDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.ResourceGraphDefinitionSpec{
+			Schema: &v1alpha1.Schema{
+				Kind:       "WebApp",
+				APIVersion: "v1alpha1",
+				Versions:   []v1alpha1.SchemaVersion{{Name: "v1beta1"}},
+			},
+		},
+	}
+	for _, id := range resourceIDs {
+		rgd.Spec.Resources = append(rgd.Spec.Resources, &v1alpha1.Resource{
+			ID:       id,
+			Template: runtime.RawExtension{Raw: []byte(`{}`)},
+		})
+	}
+	return rgd
+}
+
+func newCRD(owner string, fields ...string) *extv1.CustomResourceDefinition {
+	properties := map[string]extv1.JSONSchemaProps{}
+	for _, f := range fields {
+		properties[f] = extv1.JSONSchemaProps{Type: "string"}
+	}
+	crd := &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapps.kro.run"},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "kro.run",
+			Scope: extv1.NamespaceScoped,
+			Versions: []extv1.CustomResourceDefinitionVersion{{
+				Name:    "v1alpha1",
+				Served:  true,
+				Storage: true,
+				Schema: &extv1.CustomResourceValidation{
+					OpenAPIV3Schema: &extv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]extv1.JSONSchemaProps{
+							"spec": {Type: "object", Properties: properties},
+						},
+					},
+				},
+			}},
+		},
+	}
+	if owner != "" {
+		crd.Labels = map[string]string{
+			metadata.OwnedLabel:                       "true",
+			metadata.ResourceGraphDefinitionNameLabel: owner,
+		}
+	}
+	return crd
+}
+
+func requireInvalid(t *testing.T, err error, wantFields ...string) {
+	t.Helper()
+	require.Error(t, err)
+	require.True(t, apierrors.IsInvalid(err), "expected an Invalid error, got %v", err)
+
+	var statusErr *apierrors.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	var fields []string
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	assert.Equal(t, wantFields, fields)
+}
+
+func TestResourceGraphDefinitionValidator_Build(t *testing.T) {
+	ctx := context.Background()
+	rgd := newRGD("webapp", "deployment", "service")
+
+	t.Run("valid", func(t *testing.T) {
+		validator := NewResourceGraphDefinitionValidator(&fakeBuilder{graph: &graph.Graph{CRD: newCRD("webapp", "name")}}, fakeCRDGetter{})
+		_, err := validator.ValidateCreate(ctx, rgd)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		builder := &fakeBuilder{err: fmt.Errorf(`failed to build resource "service": %w`, &graph.FieldError{
+			ResourceID: "service",
+			Err:        errors.New("failed to get schema for resource service: not found"),
+		})}
+		validator := NewResourceGraphDefinitionValidator(builder, fakeCRDGetter{})
+		_, err := validator.ValidateCreate(ctx, rgd)
+		requireInvalid(t, err, "spec.resources[1].template")
+		assert.Contains(t, err.Error(), "failed to get schema for resource service")
+	})
+
+	t.Run("update without spec changes", func(t *testing.T) {
+		builder := &fakeBuilder{err: errors.New("invalid")}
+		validator := NewResourceGraphDefinitionValidator(builder, fakeCRDGetter{})
+
+		updated := rgd.DeepCopy()
+		updated.Finalizers = []string{"kro.run/finalizer"}
+		_, err := validator.ValidateUpdate(ctx, rgd, updated)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, builder.calls)
+	})
+
+	t.Run("update of a deleted object", func(t *testing.T) {
+		builder := &fakeBuilder{err: errors.New("invalid")}
+		validator := NewResourceGraphDefinitionValidator(builder, fakeCRDGetter{})
+
+		updated := rgd.DeepCopy()
+		updated.Spec.Resources = updated.Spec.Resources[:1]
+		now := metav1.Now()
+		updated.DeletionTimestamp = &now
+		_, err := validator.ValidateUpdate(ctx, rgd, updated)
+		assert.NoError(t, err)
+	})
+
+	t.Run("update with spec changes", func(t *testing.T) {
+		builder := &fakeBuilder{err: &graph.FieldError{ResourceID: "deployment", Err: errors.New("invalid")}}
+		validator := NewResourceGraphDefinitionValidator(builder, fakeCRDGetter{})
+
+		updated := rgd.DeepCopy()
+		updated.Spec.Resources = updated.Spec.Resources[:1]
+		_, err := validator.ValidateUpdate(ctx, rgd, updated)
+		requireInvalid(t, err, "spec.resources[0].template")
+	})
+}
+
+func TestResourceGraphDefinitionValidator_Compatibility(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		existing   *extv1.CustomResourceDefinition
+		annotation string
+		wantFields []string
+	}{
+		{
+			name: "no existing CRD",
+		},
+		{
+			name:     "compatible change",
+			existing: newCRD("webapp", "name"),
+		},
+		{
+			name:       "field removed",
+			existing:   newCRD("webapp", "name", "image"),
+			wantFields: []string{"spec.schema.spec.image"},
+		},
+		{
+			name:       "field removed with breaking changes allowed",
+			existing:   newCRD("webapp", "name", "image"),
+			annotation: "true",
+		},
+		{
+			name:     "CRD owned by another ResourceGraphDefinition",
+			existing: newCRD("other", "name", "image"),
+		},
+		{
+			name:     "CRD not owned by kro",
+			existing: newCRD("", "name", "image"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crds := fakeCRDGetter{}
+			if tt.existing != nil {
+				crds[tt.existing.Name] = tt.existing
+			}
+			rgd := newRGD("webapp", "deployment")
+			if tt.annotation != "" {
+				rgd.Annotations = map[string]string{metadata.AllowBreakingChangesAnnotation: tt.annotation}
+			}
+
+			validator := NewResourceGraphDefinitionValidator(&fakeBuilder{graph: &graph.Graph{CRD: newCRD("", "name")}}, crds)
+			_, err := validator.ValidateCreate(ctx, rgd)
+			if len(tt.wantFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			requireInvalid(t, err, tt.wantFields...)
+			assert.Contains(t, err.Error(), metadata.AllowBreakingChangesAnnotation)
+		})
+	}
+}
+
+func TestFieldPathForBuildError(t *testing.T) {
+	rgd := newRGD("webapp", "deployment", "service", "config")
+	rgd.Spec.Resources[2].ExternalRef = &v1alpha1.ExternalRef{}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{
+			err:  errors.New("unknown error"),
+			want: "spec",
+		},
+		{
+			err:  &graph.FieldError{Field: "schema.kind"},
+			want: "spec.schema.kind",
+		},
+		{
+			err:  fmt.Errorf("failed to build resourcegraphdefinition 'webapp': %w", &graph.FieldError{Field: "schema.status.endpoint"}),
+			want: "spec.schema.status.endpoint",
+		},
+		{
+			err:  &graph.FieldError{Field: "resources"},
+			want: "spec.resources",
+		},
+		{
+			err:  &graph.FieldError{ResourceID: "service", Field: "id"},
+			want: "spec.resources[1].id",
+		},
+		{
+			err:  &graph.FieldError{ResourceID: "deployment", Field: "readyWhen"},
+			want: "spec.resources[0].readyWhen",
+		},
+		{
+			err:  &graph.FieldError{ResourceID: "service", Field: "template.spec.ports[0].port"},
+			want: "spec.resources[1].template.spec.ports[0].port",
+		},
+		{
+			err:  &graph.FieldError{ResourceID: "service"},
+			want: "spec.resources[1].template",
+		},
+		{
+			err:  &graph.FieldError{ResourceID: "config"},
+			want: "spec.resources[2].externalRef",
+		},
+		{
+			err:  &graph.FieldError{ResourceID: "unknown", Field: "readyWhen"},
+			want: "spec.resources",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, fieldPathForBuildError(rgd, tt.err).String())
+		})
+	}
+}
+
+func TestFieldPathForIncompatibility(t *testing.T) {
+	rgd := newRGD("webapp")
+
+	tests := []struct {
+		incompatibility kcrd.Incompatibility
+		want            string
+	}{
+		{
+			incompatibility: kcrd.Incompatibility{Type: kcrd.IncompatibilityScopeChanged},
+			want:            "spec.schema.scope",
+		},
+		{
+			incompatibility: kcrd.Incompatibility{Type: kcrd.IncompatibilityVersionRemoved, Version: "v1beta1"},
+			want:            "spec.schema.versions",
+		},
+		{
+			incompatibility: kcrd.Incompatibility{Type: kcrd.IncompatibilityTypeChanged, Version: "v1alpha1", Path: "spec.replicas"},
+			want:            "spec.schema.spec.replicas",
+		},
+		{
+			incompatibility: kcrd.Incompatibility{Type: kcrd.IncompatibilityFieldRemoved, Version: "v1beta1", Path: "spec.size"},
+			want:            "spec.schema.versions[0].spec.size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, fieldPathForIncompatibility(rgd, tt.incompatibility).String())
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/util/sets"
 	apiservercel "k8s.io/apiserver/pkg/cel"
 	k8scellib "k8s.io/apiserver/pkg/cel/library"
 	"k8s.io/apiserver/pkg/cel/openapi"
@@ -49,6 +50,10 @@ type envOptions struct {
 	// possible. OpenAPI's AnyOf, OneOf, and VendorExtensions features like
 	// x-kubernetes-int-or-string will fall back to dyn or any type.
 	typedResources map[string]*spec.Schema
+	// optionalResources are typed resources that may be absent. They are
+	// declared as optional_type(T) so that expressions must handle a missing
+	// value, e.g. with the optional field selection operator ".?".
+	optionalResources sets.Set[string]
 	// customDeclarations will be added to the CEL environment.
 	customDeclarations []cel.EnvOption
 }
@@ -79,6 +84,17 @@ func WithTypedResources(schemas map[string]*spec.Schema) EnvOption {
 	}
 }
 
+// WithOptionalResources declares the given typed resources as optional values.
+// Names that are not typed resources are ignored.
+func WithOptionalResources(names []string) EnvOption {
+	return func(opts *envOptions) {
+		if opts.optionalResources == nil {
+			opts.optionalResources = sets.New[string]()
+		}
+		opts.optionalResources.Insert(names...)
+	}
+}
+
 // WithListVariables adds list-typed variable declarations to the CEL environment.
 // Used for collection resources so they support list operations/macros like all()
 // exists(), filter(), and map() etc...
@@ -130,6 +146,9 @@ func DefaultEnvironment(options ...EnvOption) (*cel.Env, error) {
 				declTypes = append(declTypes, declType)
 
 				celType := declType.CelType()
+				if opts.optionalResources.Has(name) {
+					celType = cel.OptionalType(celType)
+				}
 
 				// Add variable declaration
 				declarations = append(declarations, cel.Variable(name, celType))
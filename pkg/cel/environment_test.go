@@ -20,6 +20,7 @@ import (
 	"github.com/google/cel-go/cel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
 func TestWithResourceIDs(t *testing.T) {
@@ -176,3 +177,72 @@ func Test_CELEnvHasFunction(t *testing.T) {
 		})
 	}
 }
+
+func TestWithOptionalResources(t *testing.T) {
+	configMap := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: []string{"object"},
+			Properties: map[string]spec.Schema{
+				"data": {
+					SchemaProps: spec.SchemaProps{
+						Type: []string{"object"},
+						AdditionalProperties: &spec.SchemaOrBool{
+							Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		optional []string
+		expr     string
+		wantType *cel.Type
+	}{
+		{
+			name:     "required resource",
+			expr:     `config.data.region`,
+			wantType: cel.StringType,
+		},
+		{
+			name:     "optional resource propagates through field selection",
+			optional: []string{"config"},
+			expr:     `config.data.region`,
+			wantType: cel.OptionalType(cel.StringType),
+		},
+		{
+			name:     "optional resource with optional field selection",
+			optional: []string{"config"},
+			expr:     `config.?data.?region`,
+			wantType: cel.OptionalType(cel.StringType),
+		},
+		{
+			name:     "optional resource with a fallback value",
+			optional: []string{"config"},
+			expr:     `config.?data.?region.orValue("us-west-2")`,
+			wantType: cel.StringType,
+		},
+		{
+			name:     "optional resource supports hasValue",
+			optional: []string{"config"},
+			expr:     `config.hasValue()`,
+			wantType: cel.BoolType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := DefaultEnvironment(
+				WithTypedResources(map[string]*spec.Schema{"config": configMap}),
+				WithOptionalResources(tt.optional),
+			)
+			require.NoError(t, err)
+
+			ast, issues := env.Compile(tt.expr)
+			require.NoError(t, issues.Err())
+			assert.True(t, tt.wantType.IsExactType(ast.OutputType()), "got %v", ast.OutputType())
+		})
+	}
+}
@@ -174,6 +174,16 @@ func (w *CRDWrapper) patch(ctx context.Context, newCRD v1.CustomResourceDefiniti
 		return fmt.Errorf("failed to marshal CRD for patch: %w", err)
 	}
 
+	// A merge patch never removes fields that are omitted from the patch. When a
+	// CRD goes back to a single version, explicitly reset the conversion strategy
+	// and drop the webhook configuration.
+	if newCRD.Spec.Conversion == nil || newCRD.Spec.Conversion.Strategy == v1.NoneConverter {
+		patchBytes, err = resetConversion(patchBytes)
+		if err != nil {
+			return fmt.Errorf("failed to build CRD patch: %w", err)
+		}
+	}
+
 	_, err = w.client.Patch(
 		ctx,
 		newCRD.Name,
@@ -184,6 +194,24 @@ func (w *CRDWrapper) patch(ctx context.Context, newCRD v1.CustomResourceDefiniti
 	return err
 }
 
+// resetConversion sets the conversion of a marshalled CRD merge patch to the
+// None strategy, with a null webhook so any existing configuration is removed.
+func resetConversion(patchBytes []byte) ([]byte, error) {
+	patch := map[string]interface{}{}
+	if err := json.Unmarshal(patchBytes, &patch); err != nil {
+		return nil, err
+	}
+	spec, ok := patch["spec"].(map[string]interface{})
+	if !ok {
+		return patchBytes, nil
+	}
+	spec["conversion"] = map[string]interface{}{
+		"strategy": string(v1.NoneConverter),
+		"webhook":  nil,
+	}
+	return json.Marshal(patch)
+}
+
 // Delete removes a CRD if it exists
 func (w *CRDWrapper) Delete(ctx context.Context, name string) error {
 	log := logr.FromContext(ctx)
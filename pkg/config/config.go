@@ -0,0 +1,241 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config holds the KroControllerConfiguration, the versioned
+// configuration file of the kro controller, and watches the file for changes.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// APIVersion is the apiVersion of the configuration file.
+	APIVersion = "config.kro.run/v1alpha1"
+	// Kind is the kind of the configuration file.
+	Kind = "KroControllerConfiguration"
+)
+
+// KroControllerConfiguration configures the kro controller.
+type KroControllerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Client configures the client of the Kubernetes API server.
+	Client ClientConfiguration `json:"client"`
+	// ResourceGraphDefinitionController configures the controller of
+	// ResourceGraphDefinitions.
+	ResourceGraphDefinitionController ResourceGraphDefinitionControllerConfiguration `json:"resourceGraphDefinitionController"`
+	// DynamicController configures the controller of instances.
+	DynamicController DynamicControllerConfiguration `json:"dynamicController"`
+	// ResourceGraphDefinitions overrides the DynamicController configuration
+	// for the instances of some ResourceGraphDefinitions.
+	ResourceGraphDefinitions []ResourceGraphDefinitionOverrides `json:"resourceGraphDefinitions,omitempty"`
+}
+
+// ClientConfiguration configures the client of the Kubernetes API server.
+type ClientConfiguration struct {
+	// QPS is the number of queries per second to allow.
+	QPS float32 `json:"qps"`
+	// Burst is the number of requests that can be stored for processing
+	// before the server starts enforcing the QPS limit.
+	Burst int `json:"burst"`
+}
+
+// ResourceGraphDefinitionControllerConfiguration configures the controller of
+// ResourceGraphDefinitions.
+type ResourceGraphDefinitionControllerConfiguration struct {
+	// Workers is the number of ResourceGraphDefinitions reconciled in
+	// parallel.
+	Workers int `json:"workers"`
+}
+
+// DynamicControllerConfiguration configures the controller of instances.
+type DynamicControllerConfiguration struct {
+	// Workers is the number of instances reconciled in parallel.
+	Workers int `json:"workers"`
+	// ResyncPeriod is the interval at which the watched resources are
+	// listed again, even if nothing changed.
+	ResyncPeriod metav1.Duration `json:"resyncPeriod"`
+	// QueueMaxRetries is the number of times a failing instance is retried
+	// before being dropped from the queue.
+	QueueMaxRetries int `json:"queueMaxRetries"`
+	// ProgressDeadline is how long the controller may reconcile no instance
+	// while instances are queued, or fail to watch a resource, before the
+	// liveness probe fails. 0 disables the check.
+	ProgressDeadline metav1.Duration `json:"progressDeadline"`
+	// RequeueDuration is the interval at which instances waiting for their
+	// resources to become ready are reconciled again.
+	RequeueDuration metav1.Duration `json:"requeueDuration"`
+	// RateLimiter configures the rate limiting of the instances.
+	RateLimiter RateLimiterConfiguration `json:"rateLimiter"`
+}
+
+// RateLimiterConfiguration configures the rate limiting of instances.
+type RateLimiterConfiguration struct {
+	// MinDelay is the minimum delay before retrying a failed instance.
+	MinDelay metav1.Duration `json:"minDelay"`
+	// MaxDelay is the maximum delay before retrying a failed instance.
+	MaxDelay metav1.Duration `json:"maxDelay"`
+	// RateLimit is the maximum number of instances processed per second.
+	RateLimit int `json:"rateLimit"`
+	// BurstLimit is the maximum number of instances in a burst.
+	BurstLimit int `json:"burstLimit"`
+}
+
+// ResourceGraphDefinitionOverrides overrides the DynamicController
+// configuration for the instances of a ResourceGraphDefinition, and its
+// spec.reconcile. Unset fields keep the value of spec.reconcile, or else of the
+// DynamicController configuration.
+type ResourceGraphDefinitionOverrides struct {
+	// Name is the name of the ResourceGraphDefinition.
+	Name string `json:"name"`
+	// Workers is the maximum number of instances reconciled in parallel.
+	Workers int `json:"workers,omitempty"`
+	// ResyncPeriod is the interval at which every instance is reconciled,
+	// even if nothing changed.
+	ResyncPeriod metav1.Duration `json:"resyncPeriod,omitempty"`
+	// RequeueDuration is the interval at which instances waiting for their
+	// resources to become ready are reconciled again.
+	RequeueDuration metav1.Duration `json:"requeueDuration,omitempty"`
+	// RateLimiter configures the rate limiting of the instances.
+	RateLimiter RateLimiterConfiguration `json:"rateLimiter,omitempty"`
+}
+
+// ResourceGraphDefinition returns the overrides of a ResourceGraphDefinition,
+// empty if it has none.
+func (c *KroControllerConfiguration) ResourceGraphDefinition(name string) ResourceGraphDefinitionOverrides {
+	for _, o := range c.ResourceGraphDefinitions {
+		if o.Name == name {
+			return o
+		}
+	}
+	return ResourceGraphDefinitionOverrides{Name: name}
+}
+
+// LoadFile reads the configuration file at path into c. Settings missing
+// from the file keep their value in c.
+func LoadFile(path string, c *KroControllerConfiguration) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	return Load(data, c)
+}
+
+// Load decodes a configuration file into c, and validates the result.
+// Settings missing from the file keep their value in c.
+func Load(data []byte, c *KroControllerConfiguration) error {
+	// Unknown fields are likely typos, which would otherwise go unnoticed.
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return fmt.Errorf("failed to decode configuration file: %w", err)
+	}
+	if c.APIVersion != APIVersion || c.Kind != Kind {
+		return fmt.Errorf("unsupported configuration file %s, %s, expected %s, %s",
+			c.APIVersion, c.Kind, APIVersion, Kind)
+	}
+	return c.Validate()
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c *KroControllerConfiguration) Validate() error {
+	var errs []error
+	if c.Client.QPS <= 0 {
+		errs = append(errs, errors.New("client.qps must be positive"))
+	}
+	if c.Client.Burst <= 0 {
+		errs = append(errs, errors.New("client.burst must be positive"))
+	}
+	if c.ResourceGraphDefinitionController.Workers <= 0 {
+		errs = append(errs, errors.New("resourceGraphDefinitionController.workers must be positive"))
+	}
+
+	dyn := c.DynamicController
+	if dyn.Workers <= 0 {
+		errs = append(errs, errors.New("dynamicController.workers must be positive"))
+	}
+	if dyn.ResyncPeriod.Duration < 0 {
+		errs = append(errs, errors.New("dynamicController.resyncPeriod must not be negative"))
+	}
+	if dyn.QueueMaxRetries < 0 {
+		errs = append(errs, errors.New("dynamicController.queueMaxRetries must not be negative"))
+	}
+	if dyn.ProgressDeadline.Duration < 0 {
+		errs = append(errs, errors.New("dynamicController.progressDeadline must not be negative"))
+	}
+	if dyn.RequeueDuration.Duration <= 0 {
+		errs = append(errs, errors.New("dynamicController.requeueDuration must be positive"))
+	}
+	if dyn.RateLimiter.RateLimit <= 0 || dyn.RateLimiter.BurstLimit <= 0 {
+		errs = append(errs, errors.New("dynamicController.rateLimiter.rateLimit and burstLimit must be positive"))
+	}
+	errs = append(errs, dyn.RateLimiter.validate("dynamicController.rateLimiter")...)
+
+	names := make(map[string]bool, len(c.ResourceGraphDefinitions))
+	for i, o := range c.ResourceGraphDefinitions {
+		field := fmt.Sprintf("resourceGraphDefinitions[%d]", i)
+		switch {
+		case o.Name == "":
+			errs = append(errs, fmt.Errorf("%s.name must be set", field))
+		case names[o.Name]:
+			errs = append(errs, fmt.Errorf("%s.name: duplicate ResourceGraphDefinition %q", field, o.Name))
+		}
+		names[o.Name] = true
+		if o.Workers < 0 {
+			errs = append(errs, fmt.Errorf("%s.workers must not be negative", field))
+		}
+		if o.ResyncPeriod.Duration < 0 {
+			errs = append(errs, fmt.Errorf("%s.resyncPeriod must not be negative", field))
+		}
+		if o.RequeueDuration.Duration < 0 {
+			errs = append(errs, fmt.Errorf("%s.requeueDuration must not be negative", field))
+		}
+		errs = append(errs, o.RateLimiter.validate(field+".rateLimiter")...)
+	}
+	return errors.Join(errs...)
+}
+
+func (r RateLimiterConfiguration) validate(field string) []error {
+	var errs []error
+	if r.MinDelay.Duration < 0 || r.MaxDelay.Duration < 0 || r.RateLimit < 0 || r.BurstLimit < 0 {
+		errs = append(errs, fmt.Errorf("%s settings must not be negative", field))
+	}
+	if r.MinDelay.Duration > 0 && r.MaxDelay.Duration > 0 && r.MinDelay.Duration > r.MaxDelay.Duration {
+		errs = append(errs, fmt.Errorf("%s.minDelay must not exceed maxDelay", field))
+	}
+	return errs
+}
+
+// RestartRequired returns the settings that differ between c and next but
+// are only applied when the controller starts.
+func (c *KroControllerConfiguration) RestartRequired(next *KroControllerConfiguration) []string {
+	var fields []string
+	if c.Client != next.Client {
+		fields = append(fields, "client")
+	}
+	if c.ResourceGraphDefinitionController.Workers != next.ResourceGraphDefinitionController.Workers {
+		fields = append(fields, "resourceGraphDefinitionController.workers")
+	}
+	if c.DynamicController.Workers != next.DynamicController.Workers {
+		fields = append(fields, "dynamicController.workers")
+	}
+	if c.DynamicController.ResyncPeriod != next.DynamicController.ResyncPeriod {
+		fields = append(fields, "dynamicController.resyncPeriod")
+	}
+	return fields
+}
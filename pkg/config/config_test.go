@@ -0,0 +1,137 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaults returns a valid configuration, as built from the default flags.
+func defaults() *KroControllerConfiguration {
+	return &KroControllerConfiguration{
+		Client:                            ClientConfiguration{QPS: 100, Burst: 150},
+		ResourceGraphDefinitionController: ResourceGraphDefinitionControllerConfiguration{Workers: 1},
+		DynamicController: DynamicControllerConfiguration{
+			Workers:          1,
+			ResyncPeriod:     metav1.Duration{Duration: 10 * time.Hour},
+			QueueMaxRetries:  20,
+			ProgressDeadline: metav1.Duration{Duration: 5 * time.Minute},
+			RequeueDuration:  metav1.Duration{Duration: 3 * time.Second},
+			RateLimiter: RateLimiterConfiguration{
+				MinDelay:   metav1.Duration{Duration: 200 * time.Millisecond},
+				MaxDelay:   metav1.Duration{Duration: 1000 * time.Second},
+				RateLimit:  10,
+				BurstLimit: 100,
+			},
+		},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	c := defaults()
+	require.NoError(t, Load([]byte(`
+apiVersion: config.kro.run/v1alpha1
+kind: KroControllerConfiguration
+dynamicController:
+  workers: 8
+  rateLimiter:
+    maxDelay: 5m
+resourceGraphDefinitions:
+- name: cloud-stack
+  workers: 2
+  resyncPeriod: 30m
+  requeueDuration: 1m
+`), c))
+
+	assert.Equal(t, 8, c.DynamicController.Workers)
+	assert.Equal(t, 5*time.Minute, c.DynamicController.RateLimiter.MaxDelay.Duration)
+	// Settings missing from the file are kept.
+	assert.Equal(t, 200*time.Millisecond, c.DynamicController.RateLimiter.MinDelay.Duration)
+	assert.Equal(t, float32(100), c.Client.QPS)
+
+	o := c.ResourceGraphDefinition("cloud-stack")
+	assert.Equal(t, 2, o.Workers)
+	assert.Equal(t, 30*time.Minute, o.ResyncPeriod.Duration)
+	assert.Equal(t, time.Minute, o.RequeueDuration.Duration)
+	assert.Equal(t, ResourceGraphDefinitionOverrides{Name: "app-stack"}, c.ResourceGraphDefinition("app-stack"))
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "missing kind",
+			data: "apiVersion: config.kro.run/v1alpha1",
+			err:  "unsupported configuration file",
+		},
+		{
+			name: "unknown field",
+			data: "apiVersion: config.kro.run/v1alpha1\nkind: KroControllerConfiguration\ndynamicController:\n  worker: 2",
+			err:  `unknown field "worker"`,
+		},
+		{
+			name: "invalid global setting",
+			data: "apiVersion: config.kro.run/v1alpha1\nkind: KroControllerConfiguration\nclient:\n  qps: 0",
+			err:  "client.qps must be positive",
+		},
+		{
+			name: "duplicate overrides",
+			data: `apiVersion: config.kro.run/v1alpha1
+kind: KroControllerConfiguration
+resourceGraphDefinitions:
+- name: a
+- name: a`,
+			err: `duplicate ResourceGraphDefinition "a"`,
+		},
+		{
+			name: "inverted delays",
+			data: `apiVersion: config.kro.run/v1alpha1
+kind: KroControllerConfiguration
+resourceGraphDefinitions:
+- name: a
+  rateLimiter:
+    minDelay: 1m
+    maxDelay: 1s`,
+			err: "resourceGraphDefinitions[0].rateLimiter.minDelay must not exceed maxDelay",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Load([]byte(tt.data), defaults())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	prev, next := defaults(), defaults()
+	next.DynamicController.QueueMaxRetries = 5
+	next.DynamicController.RateLimiter.RateLimit = 50
+	next.ResourceGraphDefinitions = []ResourceGraphDefinitionOverrides{{Name: "a", Workers: 2}}
+	assert.Empty(t, prev.RestartRequired(next))
+
+	next.Client.QPS = 50
+	next.DynamicController.Workers = 4
+	assert.Equal(t, []string{"client", "dynamicController.workers"}, prev.RestartRequired(next))
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// DefaultPollInterval is the interval at which the configuration file is
+// checked for changes by default.
+const DefaultPollInterval = 10 * time.Second
+
+// Loader builds the configuration from the configuration file.
+type Loader func() (*KroControllerConfiguration, error)
+
+// Watcher holds the current configuration, and reloads it whenever the
+// configuration file changes. The file is polled rather than watched with
+// inotify, since mounted ConfigMaps are updated by swapping symlinks.
+type Watcher struct {
+	log      logr.Logger
+	path     string
+	interval time.Duration
+	load     Loader
+
+	mu       sync.RWMutex
+	current  *KroControllerConfiguration
+	data     []byte
+	onChange []func(prev, next *KroControllerConfiguration)
+}
+
+// NewWatcher returns a Watcher of the configuration file at path, starting
+// with the configuration loaded by load. If path is empty, the configuration
+// never changes.
+func NewWatcher(log logr.Logger, path string, interval time.Duration, load Loader) (*Watcher, error) {
+	w := &Watcher{
+		log:      log.WithName("config-watcher").WithValues("path", path),
+		path:     path,
+		interval: interval,
+		load:     load,
+	}
+	var err error
+	if path != "" {
+		if w.data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if w.current, err = load(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Current returns the current configuration. It must not be modified.
+func (w *Watcher) Current() *KroControllerConfiguration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// OnChange registers a function called with the previous and the new
+// configuration whenever the configuration changes.
+func (w *Watcher) OnChange(fn func(prev, next *KroControllerConfiguration)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChange = append(w.onChange, fn)
+}
+
+// Start implements manager.Runnable. It polls the configuration file until
+// ctx is done.
+func (w *Watcher) Start(ctx context.Context) error {
+	if w.path == "" {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.reload()
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every
+// replica follows the configuration file.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// reload loads the configuration again if the configuration file changed.
+// Invalid configurations are logged and ignored, keeping the current one.
+func (w *Watcher) reload() {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		w.log.Error(err, "failed to read configuration file")
+		return
+	}
+
+	w.mu.RLock()
+	unchanged := bytes.Equal(data, w.data)
+	w.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	next, err := w.load()
+	if err != nil {
+		w.log.Error(err, "invalid configuration file, keeping the current configuration")
+		// Only report the same invalid file once.
+		w.mu.Lock()
+		w.data = data
+		w.mu.Unlock()
+		return
+	}
+
+	w.mu.Lock()
+	prev := w.current
+	w.current, w.data = next, data
+	onChange := w.onChange
+	w.mu.Unlock()
+
+	w.log.Info("configuration file changed")
+	for _, fn := range onChange {
+		fn(prev, next)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(workers string) {
+		require.NoError(t, os.WriteFile(path, []byte(`apiVersion: config.kro.run/v1alpha1
+kind: KroControllerConfiguration
+resourceGraphDefinitions:
+- name: a
+  workers: `+workers), 0o600))
+	}
+	load := func() (*KroControllerConfiguration, error) {
+		c := defaults()
+		return c, LoadFile(path, c)
+	}
+
+	write("1")
+	w, err := NewWatcher(logr.Discard(), path, DefaultPollInterval, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, w.Current().ResourceGraphDefinition("a").Workers)
+
+	var changes int
+	w.OnChange(func(prev, next *KroControllerConfiguration) {
+		changes++
+		assert.Equal(t, 1, prev.ResourceGraphDefinition("a").Workers)
+		assert.Equal(t, 2, next.ResourceGraphDefinition("a").Workers)
+	})
+
+	w.reload()
+	assert.Zero(t, changes, "the file did not change")
+
+	write("2")
+	w.reload()
+	assert.Equal(t, 1, changes)
+	assert.Equal(t, 2, w.Current().ResourceGraphDefinition("a").Workers)
+
+	// Invalid configurations are ignored.
+	write("-1")
+	w.reload()
+	assert.Equal(t, 1, changes)
+	assert.Equal(t, 2, w.Current().ResourceGraphDefinition("a").Workers)
+}
+
+func TestNewWatcher_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("kind: Unknown"), 0o600))
+	_, err := NewWatcher(logr.Discard(), path, DefaultPollInterval, func() (*KroControllerConfiguration, error) {
+		c := defaults()
+		return c, LoadFile(path, c)
+	})
+	assert.Error(t, err)
+}
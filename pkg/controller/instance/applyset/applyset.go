@@ -28,6 +28,7 @@ import (
 	"sync"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -37,6 +38,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
+
+	"github.com/kubernetes-sigs/kro/pkg/tracing"
 )
 
 // Compile-time check that ApplySet implements Interface.
@@ -166,7 +169,7 @@ type Config struct {
 	Client          dynamic.Interface
 	RESTMapper      meta.RESTMapper
 	Log             logr.Logger
-	ParentNamespace string // fallback namespace for namespaced resources without namespace set
+	ParentNamespace string // fallback namespace for namespaced resources without namespace set, empty for cluster scoped parents
 }
 
 // New creates an ApplySet for a specific parent (instance).
@@ -236,8 +239,10 @@ func (a *ApplySet) Project(resources []Resource) (Metadata, error) {
 
 // Apply runs SSA on all resources and returns batch-only metadata.
 // Caller should call Prune separately after Apply succeeds.
-func (a *ApplySet) Apply(ctx context.Context, resources []Resource, mode ApplyMode) (*ApplyResult, Metadata, error) {
-	result := &ApplyResult{}
+func (a *ApplySet) Apply(ctx context.Context, resources []Resource, mode ApplyMode) (result *ApplyResult, _ Metadata, err error) {
+	ctx, span := tracing.Start(ctx, "ApplySet.Apply", attribute.Int("resources", len(resources)))
+	defer func() { tracing.End(span, err) }()
+	result = &ApplyResult{}
 
 	// Collect GKs and namespaces for batch metadata
 	desiredGKs := sets.New[schema.GroupKind]()
@@ -314,15 +319,16 @@ func (a *ApplySet) Apply(ctx context.Context, resources []Resource, mode ApplyMo
 }
 
 // Prune deletes orphaned resources (those with applyset label but not in KeepUIDs).
-func (a *ApplySet) Prune(ctx context.Context, opts PruneOptions) (*PruneResult, error) {
+func (a *ApplySet) Prune(ctx context.Context, opts PruneOptions) (_ *PruneResult, err error) {
+	ctx, span := tracing.Start(ctx, "ApplySet.Prune", attribute.Int("keep", len(opts.KeepUIDs)))
+	defer func() { tracing.End(span, err) }()
 	scopeGKs := opts.Scope.GroupKinds
 
-	// Always include parent namespace in prune scope
+	// Always include parent namespace in prune scope. Cluster scoped parents
+	// have no namespace: every namespace they own is an additional namespace.
 	scopeNamespaces := opts.Scope.Namespaces.Clone()
 	if a.parentNamespace != "" {
 		scopeNamespaces.Insert(a.parentNamespace)
-	} else {
-		scopeNamespaces.Insert(metav1.NamespaceDefault)
 	}
 
 	// Convert GKs to RESTMappings
@@ -351,6 +357,13 @@ func (a *ApplySet) applyResource(
 	options metav1.ApplyOptions,
 ) ApplyResultItem {
 	item := ApplyResultItem{ID: r.ID}
+	ctx, span := tracing.Start(ctx, "applyResource",
+		attribute.String("id", r.ID),
+		attribute.String("gvr", mapping.Resource.String()),
+		attribute.String("namespace", r.Object.GetNamespace()),
+		attribute.String("name", r.Object.GetName()),
+	)
+	defer func() { tracing.End(span, item.Error) }()
 
 	// Conflict check using observed state (from controller GET), if provided.
 	var currentApplySetID string
@@ -734,6 +734,38 @@ func TestProject(t *testing.T) {
 	}
 }
 
+func TestProject_ClusterScopedParent(t *testing.T) {
+	mapper := newTestRESTMapper()
+	parent := newTestParent(schema.GroupVersionKind{
+		Group: "kro.run", Version: "v1alpha1", Kind: "TestKind",
+	})
+	parent.Namespace = ""
+
+	applier := New(Config{
+		Client:     newFakeDynamicClient(),
+		RESTMapper: mapper,
+		Log:        logr.Discard(),
+	}, parent)
+
+	metadata, err := applier.Project([]Resource{
+		{ID: "cm1", Object: newConfigMap("cm1", "")},
+		{ID: "cm2", Object: newConfigMap("cm2", "other-ns")},
+		{ID: "ns", Object: newNamespace("team-a")},
+	})
+	if err != nil {
+		t.Fatalf("Project() error = %v", err)
+	}
+
+	// Without a parent namespace every namespace is an additional namespace,
+	// and resources without a namespace land in the default namespace.
+	if got, want := metadata.NamespacesString(), "default,other-ns"; got != want {
+		t.Errorf("Project().NamespacesString() = %q, want %q", got, want)
+	}
+	if metadata.ID == ID(newTestParent(parent.gvk)) {
+		t.Errorf("Project().ID = %q, want an ID distinct from the namespaced parent", metadata.ID)
+	}
+}
+
 func TestPrune_ParentAnnotationsContributeToPruneScope(t *testing.T) {
 	mapper := newTestRESTMapper()
 
@@ -0,0 +1,121 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubernetes-sigs/kro/pkg/requeue"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+// reconcileCompletion evaluates the completion expressions of a one-shot
+// instance after its resources were reconciled, whether or not they are all
+// resolved. When the instance finished, its
+// completion time is recorded in its status, after which its resources are no
+// longer reconciled.
+func (c *Controller) reconcileCompletion(rcx *ReconcileContext) error {
+	outcome, expr, err := rcx.Runtime.Instance().EvaluateCompletion()
+	if err != nil {
+		rcx.Mark.CompletionUnknown("failed to evaluate completion: %v", err)
+		return err
+	}
+
+	var reason, eventType string
+	switch outcome {
+	case runtime.CompletionRunning:
+		rcx.Mark.Running()
+		return nil
+	case runtime.CompletionSucceeded:
+		rcx.Mark.Succeeded()
+		reason, eventType = "Succeeded", corev1.EventTypeNormal
+	case runtime.CompletionFailed:
+		rcx.Mark.Failed(expr)
+		reason, eventType = "Failed", corev1.EventTypeWarning
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if err := unstructured.SetNestedField(rcx.Instance.Object, now, "status", "completionTime"); err != nil {
+		return fmt.Errorf("failed to record completion time: %w", err)
+	}
+	completionsTotal.WithLabelValues(rcx.GVR.String(), reason).Inc()
+	rcx.Log.Info("instance finished", "outcome", reason)
+	if c.recorder != nil {
+		note := "Success expressions are met"
+		if outcome == runtime.CompletionFailed {
+			note = fmt.Sprintf("Failure expression %q is met", expr)
+		}
+		c.recorder.Eventf(rcx.Instance, nil, eventType, reason, "Complete", note)
+	}
+	return nil
+}
+
+// reconcileFinished returns true if the instance is a one-shot instance that
+// already finished, in which case its resources must be left untouched. Once
+// the time to live of a finished instance expired, the instance is deleted.
+// Until then, the returned error requeues the instance when it expires.
+func (c *Controller) reconcileFinished(rcx *ReconcileContext) (bool, error) {
+	completion := rcx.Runtime.Instance().Spec.Completion
+	if completion == nil {
+		return false, nil
+	}
+	finishedAt, ok := completionTime(rcx.Instance)
+	if !ok {
+		return false, nil
+	}
+	if completion.TTLAfterFinished == nil {
+		return true, nil
+	}
+
+	if remaining := time.Until(finishedAt.Add(*completion.TTLAfterFinished)); remaining > 0 {
+		return true, requeue.NeededAfter(
+			fmt.Errorf("instance finished, deleting it in %s", remaining.Round(time.Second)), remaining)
+	}
+
+	uid := rcx.Instance.GetUID()
+	err := rcx.InstanceClient().Delete(rcx.Ctx, rcx.Instance.GetName(), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return true, fmt.Errorf("failed to delete expired instance: %w", err)
+	}
+	expirationsTotal.WithLabelValues(rcx.GVR.String()).Inc()
+	rcx.Log.Info("deleted finished instance", "ttl", *completion.TTLAfterFinished)
+	if c.recorder != nil {
+		c.recorder.Eventf(rcx.Instance, nil, corev1.EventTypeNormal, "Expired", "Delete",
+			"Deleting instance %s after it finished", completion.TTLAfterFinished.String())
+	}
+	return true, nil
+}
+
+// completionTime returns the time the instance finished, as recorded in its
+// status.
+func completionTime(instance *unstructured.Unstructured) (time.Time, bool) {
+	value, found, _ := unstructured.NestedString(instance.Object, "status", "completionTime")
+	if !found {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
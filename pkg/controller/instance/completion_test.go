@@ -0,0 +1,260 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/graph/variable"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+	"github.com/kubernetes-sigs/kro/pkg/requeue"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+var exportGVR = schema.GroupVersionResource{Group: "kro.run", Version: "v1alpha1", Resource: "exports"}
+
+func newCompletionContext(t *testing.T, completion *graph.Completion, phase, completedAt string) *ReconcileContext {
+	t.Helper()
+	inst := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "kro.run/v1alpha1",
+		"kind":       "Export",
+		"metadata":   map[string]any{"name": "export", "namespace": "default", "uid": "1234"},
+		"spec":       map[string]any{"phase": phase},
+	}}
+	if completedAt != "" {
+		inst.Object["status"] = map[string]any{"completionTime": completedAt}
+	}
+	g := &graph.Graph{
+		Instance: &graph.Node{
+			Meta:       graph.NodeMeta{ID: graph.InstanceNodeID, Type: graph.NodeTypeInstance, GVR: exportGVR, Namespaced: true},
+			Template:   &unstructured.Unstructured{Object: map[string]any{}},
+			Completion: completion,
+		},
+		Nodes: map[string]*graph.Node{},
+	}
+	rt, err := runtime.FromGraph(g, inst)
+	require.NoError(t, err)
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{exportGVR: "ExportList"},
+		inst,
+	)
+	return NewReconcileContext(context.Background(), logr.Discard(), exportGVR, client, nil,
+		metadata.NewKROMetaLabeler(), rt, ReconcileConfig{}, inst)
+}
+
+func TestReconcileCompletion(t *testing.T) {
+	completion := &graph.Completion{
+		SuccessWhen: []string{"schema.spec.phase == 'Done'"},
+		FailureWhen: []string{"schema.spec.phase == 'Error'"},
+	}
+
+	tests := []struct {
+		name       string
+		phase      string
+		wantStatus metav1.ConditionStatus
+		wantReason string
+		wantState  string
+	}{
+		{name: "running", phase: "Exporting", wantStatus: metav1.ConditionUnknown, wantReason: "Running"},
+		{name: "succeeded", phase: "Done", wantStatus: metav1.ConditionTrue, wantReason: "Succeeded", wantState: InstanceStateSucceeded},
+		{name: "failed", phase: "Error", wantStatus: metav1.ConditionFalse, wantReason: "Failed", wantState: InstanceStateFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcx := newCompletionContext(t, completion, tt.phase, "")
+			recorder := events.NewFakeRecorder(1)
+			c := &Controller{recorder: recorder}
+
+			require.NoError(t, c.reconcileCompletion(rcx))
+
+			cond := rcx.Mark.cs.Get(Completed)
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.wantStatus, cond.Status)
+			assert.Equal(t, tt.wantReason, *cond.Reason)
+
+			finished := tt.wantState != ""
+			_, ok := completionTime(rcx.Instance)
+			assert.Equal(t, finished, ok)
+			assert.Equal(t, finished, len(recorder.Events) == 1)
+			if finished {
+				assert.Equal(t, tt.wantState, rcx.initialStatus()["state"])
+			}
+		})
+	}
+}
+
+func TestReconcileFinished(t *testing.T) {
+	ttl := time.Hour
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name         string
+		completion   *graph.Completion
+		completedAt  string
+		wantFinished bool
+		wantRequeue  bool
+		wantDeleted  bool
+	}{
+		{
+			name:        "not a one-shot instance",
+			completedAt: now.Format(time.RFC3339),
+		},
+		{
+			name:       "not finished",
+			completion: &graph.Completion{SuccessWhen: []string{"true"}, TTLAfterFinished: &ttl},
+		},
+		{
+			name:         "finished without ttl",
+			completion:   &graph.Completion{SuccessWhen: []string{"true"}},
+			completedAt:  now.Add(-24 * time.Hour).Format(time.RFC3339),
+			wantFinished: true,
+		},
+		{
+			name:         "finished before its ttl",
+			completion:   &graph.Completion{SuccessWhen: []string{"true"}, TTLAfterFinished: &ttl},
+			completedAt:  now.Add(-time.Minute).Format(time.RFC3339),
+			wantFinished: true,
+			wantRequeue:  true,
+		},
+		{
+			name:         "expired",
+			completion:   &graph.Completion{SuccessWhen: []string{"true"}, TTLAfterFinished: &ttl},
+			completedAt:  now.Add(-2 * time.Hour).Format(time.RFC3339),
+			wantFinished: true,
+			wantDeleted:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcx := newCompletionContext(t, tt.completion, "Done", tt.completedAt)
+			c := &Controller{}
+
+			finished, err := c.reconcileFinished(rcx)
+			assert.Equal(t, tt.wantFinished, finished)
+
+			var after *requeue.RequeueNeededAfter
+			if tt.wantRequeue {
+				require.True(t, errors.As(err, &after), "expected a requeue, got %v", err)
+				assert.InDelta(t, (ttl - time.Minute).Seconds(), after.Duration().Seconds(), 5)
+			} else {
+				require.NoError(t, err)
+			}
+
+			_, err = rcx.InstanceClient().Get(rcx.Ctx, "export", metav1.GetOptions{})
+			assert.Equal(t, tt.wantDeleted, apierrors.IsNotFound(err))
+		})
+	}
+}
+
+func TestReconcileResourcesAndCompletion_FailsWithUnresolvedDependents(t *testing.T) {
+	configMapGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	configMap := func(name string, data map[string]any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": name, "namespace": "default"},
+			"data":       data,
+		}}
+	}
+	// The job failed, and the report it would have written is never
+	// resolved.
+	g := &graph.Graph{
+		Instance: &graph.Node{
+			Meta: graph.NodeMeta{
+				ID: graph.InstanceNodeID, Type: graph.NodeTypeInstance, GVR: exportGVR, Namespaced: true,
+				Dependencies: []string{"job"},
+			},
+			Template: &unstructured.Unstructured{Object: map[string]any{}},
+			Completion: &graph.Completion{
+				SuccessWhen: []string{"job.data.phase == 'Succeeded'"},
+				FailureWhen: []string{"job.data.phase == 'Failed'"},
+			},
+		},
+		Nodes: map[string]*graph.Node{
+			"job": {
+				Meta:     graph.NodeMeta{ID: "job", Type: graph.NodeTypeResource, GVR: configMapGVR, Namespaced: true},
+				Template: configMap("job", map[string]any{"phase": "Failed"}),
+			},
+			"report": {
+				Meta: graph.NodeMeta{
+					ID: "report", Type: graph.NodeTypeResource, GVR: configMapGVR, Namespaced: true,
+					Dependencies: []string{"job"},
+				},
+				Template: configMap("report", map[string]any{"result": "${job.data.result}"}),
+				Variables: []*variable.ResourceField{{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.result",
+						Expressions:          []string{"job.data.result"},
+						StandaloneExpression: true,
+					},
+					Kind: variable.ResourceVariableKindDynamic,
+				}},
+			},
+		},
+		TopologicalOrder: []string{"job", "report"},
+	}
+	rcx := newCompletionContext(t, nil, "", "")
+	rt, err := runtime.FromGraph(g, rcx.Instance)
+	require.NoError(t, err)
+	rcx.Runtime = rt
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	rcx.RestMapper = mapper
+	rcx.Client.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "*",
+		func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+			patch := action.(k8stesting.PatchAction)
+			if patch.GetPatchType() != types.ApplyPatchType {
+				return true, rcx.Instance, nil
+			}
+			obj := &unstructured.Unstructured{}
+			if err := obj.UnmarshalJSON(patch.GetPatch()); err != nil {
+				return true, nil, err
+			}
+			obj.SetUID(types.UID("uid-" + obj.GetName()))
+			return true, obj, nil
+		})
+	c := &Controller{recorder: events.NewFakeRecorder(1)}
+
+	require.NoError(t, c.reconcileResourcesAndCompletion(rcx))
+
+	cond := rcx.Mark.cs.Get(Completed)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "Failed", *cond.Reason)
+	_, finished := completionTime(rcx.Instance)
+	assert.True(t, finished)
+}
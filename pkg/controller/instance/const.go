@@ -20,7 +20,8 @@ const (
 	// Set at the beginning of prepareResource before any operations.
 	ResourceStateInProgress = "IN_PROGRESS"
 	// ResourceStateDeleting means delete was called but the resource still exists.
-	// Set when deletion is initiated but not yet confirmed.
+	// Set when deletion is initiated but not yet confirmed, including when a
+	// resource is deleted to be recreated.
 	ResourceStateDeleting = "DELETING"
 	// ResourceStateSkipped means the resource was not applied.
 	// Set when includeWhen=false, dependency is skipped, or external ref during deletion.
@@ -40,4 +41,7 @@ const (
 
 	// FieldManagerForLabeler is the field manager name used when applying labels.
 	FieldManagerForLabeler = "kro.run/labeller"
+	// FieldManagerForRollout is the field manager name used when applying the
+	// ready graph revision label.
+	FieldManagerForRollout = "kro.run/rollout"
 )
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 
+	"github.com/kubernetes-sigs/kro/pkg/apis"
 	"github.com/kubernetes-sigs/kro/pkg/metadata"
 	"github.com/kubernetes-sigs/kro/pkg/requeue"
 	"github.com/kubernetes-sigs/kro/pkg/runtime"
@@ -41,9 +42,19 @@ type ReconcileContext struct {
 	Runtime  runtime.Interface
 	Instance *unstructured.Unstructured
 	Config   ReconcileConfig
+	// GraphRevision is the revision of the ResourceGraphDefinition the
+	// runtime was created from.
+	GraphRevision int64
 
 	Mark         *ConditionsMarker
 	StateManager *StateManager
+	// HookRuns holds the latest completed run of each hook, keyed by node
+	// ID. It is persisted in the status of the instance.
+	HookRuns map[string]string
+
+	// conditionTypes are the conditions of the instance: Succeeded rooted
+	// conditions for one-shot instances, Ready rooted ones otherwise.
+	conditionTypes apis.ConditionTypes
 }
 
 // NewReconcileContext constructs a ReconcileContext for a single reconciliation cycle.
@@ -66,18 +77,24 @@ func NewReconcileContext(
 	config ReconcileConfig,
 	instance *unstructured.Unstructured,
 ) *ReconcileContext {
+	ct := condSet
+	if rt.Instance().Spec.Completion != nil {
+		ct = completionCondSet
+	}
 	return &ReconcileContext{
-		Ctx:          ctx,
-		Log:          log,
-		GVR:          gvr,
-		Client:       client,
-		RestMapper:   restMapper,
-		Labeler:      labeler,
-		Runtime:      rt,
-		Instance:     instance,
-		Config:       config,
-		Mark:         NewConditionsMarkerFor(instance),
-		StateManager: newStateManager(),
+		Ctx:            ctx,
+		Log:            log,
+		GVR:            gvr,
+		Client:         client,
+		RestMapper:     restMapper,
+		Labeler:        labeler,
+		Runtime:        rt,
+		Instance:       instance,
+		Config:         config,
+		Mark:           newConditionsMarker(instance, ct),
+		StateManager:   newStateManager(),
+		HookRuns:       hookRunsFrom(instance),
+		conditionTypes: ct,
 	}
 }
 
@@ -97,6 +114,16 @@ func (rcx *ReconcileContext) getResourceNamespace(desired *unstructured.Unstruct
 	return metav1.NamespaceDefault
 }
 
+// instanceIdentityMetadata returns the metadata identifying obj in SSA patches.
+// The namespace is omitted for cluster scoped instances.
+func instanceIdentityMetadata(obj metav1.Object) map[string]interface{} {
+	md := map[string]interface{}{"name": obj.GetName()}
+	if ns := obj.GetNamespace(); ns != "" {
+		md["namespace"] = ns
+	}
+	return md
+}
+
 func (rcx *ReconcileContext) InstanceClient() dynamic.ResourceInterface {
 	base := rcx.Client.Resource(rcx.GVR)
 	if rcx.Instance.GetNamespace() != "" {
@@ -17,19 +17,25 @@ package instance
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	kroclient "github.com/kubernetes-sigs/kro/pkg/client"
 	"github.com/kubernetes-sigs/kro/pkg/graph"
 	"github.com/kubernetes-sigs/kro/pkg/metadata"
 	"github.com/kubernetes-sigs/kro/pkg/runtime"
+	"github.com/kubernetes-sigs/kro/pkg/tracing"
 )
 
 // ReconcileConfig holds configuration parameters for the reconciliation process.
@@ -46,6 +52,47 @@ type ReconcileConfig struct {
 	// TODO(a-hilaly): need to define think the different deletion policies we need to
 	// support.
 	DeletionPolicy string
+	// HistoryLimit is the number of history entries kept per instance. If 0,
+	// no history is recorded.
+	HistoryLimit int
+}
+
+// ExternalWatcher tracks the label selectors instances use to read external
+// collections, so that changes to the selected objects trigger reconciliations.
+type ExternalWatcher interface {
+	// WatchExternalSelector records the selector used by the instance to read
+	// the external collection id. An empty namespace matches every namespace.
+	WatchExternalSelector(
+		parent schema.GroupVersionResource,
+		instance types.NamespacedName,
+		id string,
+		gvr schema.GroupVersionResource,
+		namespace string,
+		selector labels.Selector,
+	)
+	// ForgetExternalSelectors drops every selector recorded for the instance.
+	ForgetExternalSelectors(parent schema.GroupVersionResource, instance types.NamespacedName)
+}
+
+// NamespaceScope reports the namespaces watched by kro.
+type NamespaceScope interface {
+	// WatchedNamespaces returns the namespaces watched by kro, or nil if it
+	// watches every namespace.
+	WatchedNamespaces() []string
+}
+
+// GraphRevisions resolves the graphs of previous revisions of a
+// ResourceGraphDefinition, for instances pinned to them.
+type GraphRevisions interface {
+	// Graph returns the graph compiled from the given revision.
+	Graph(ctx context.Context, revision int64) (*graph.Graph, error)
+}
+
+// Rollout decides the revision instances that are not pinned are reconciled
+// with while a change to the ResourceGraphDefinition is rolled out.
+type Rollout interface {
+	// Revision returns the revision the instance must be reconciled with.
+	Revision(inst metav1.Object) int64
 }
 
 // Controller manages the reconciliation of a single instance of a ResourceGraphDefinition,
@@ -74,9 +121,35 @@ type Controller struct {
 	client kroclient.SetInterface
 	gvr    schema.GroupVersionResource
 	rgd    *graph.Graph
+	// rgdName is the name of the ResourceGraphDefinition, labelling the
+	// health metrics of its instances.
+	rgdName string
+	// revision is the revision of the ResourceGraphDefinition rgd was
+	// compiled from. Instances pinned to another revision are reconciled with
+	// the graph returned by revisions.
+	revision  int64
+	revisions GraphRevisions
+	// rollout picks the revision of instances that are not pinned. If nil,
+	// they are reconciled with the latest revision.
+	rollout Rollout
 
 	labeler         metadata.Labeler
 	reconcileConfig ReconcileConfig
+	externalWatcher ExternalWatcher
+	// namespaces restricts the namespaces resources are managed in. If nil,
+	// every namespace is watched.
+	namespaces NamespaceScope
+
+	// historyHashes caches the hash of the latest history entry of each
+	// instance, keyed by UID, to avoid listing the history on every
+	// reconciliation.
+	historyHashes sync.Map
+	// recreations holds the last time each resource was recreated, keyed by
+	// recreationKey, to rate limit recreations.
+	recreations sync.Map
+
+	// recorder records events on instances. If nil, no events are recorded.
+	recorder events.EventRecorder
 }
 
 // NewController constructs a new controller with static RGD.
@@ -87,20 +160,39 @@ func NewController(
 	rgd *graph.Graph,
 	client kroclient.SetInterface,
 	labeler metadata.Labeler,
+	externalWatcher ExternalWatcher,
+	namespaces NamespaceScope,
+	revision int64,
+	revisions GraphRevisions,
+	rollout Rollout,
+	recorder events.EventRecorder,
 ) *Controller {
 	return &Controller{
 		log:             log,
 		client:          client,
 		gvr:             gvr,
 		rgd:             rgd,
+		rgdName:         labeler.Labels()[metadata.ResourceGraphDefinitionNameLabel],
+		revision:        revision,
+		revisions:       revisions,
+		rollout:         rollout,
 		labeler:         labeler,
 		reconcileConfig: reconcileConfig,
+		externalWatcher: externalWatcher,
+		namespaces:      namespaces,
+		recorder:        recorder,
 	}
 }
 
 // Reconcile implements the controller-runtime Reconcile interface.
 func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (err error) {
-	log := c.log.WithValues("namespace", req.Namespace, "name", req.Name)
+	ctx, span := tracing.Start(ctx, "Controller.Reconcile",
+		attribute.String("gvr", c.gvr.String()),
+		attribute.String("namespace", req.Namespace),
+		attribute.String("name", req.Name),
+	)
+	defer func() { tracing.End(span, err) }()
+	log := tracing.LoggerWithTrace(ctx, c.log.WithValues("namespace", req.Namespace, "name", req.Name))
 
 	//--------------------------------------------------------------
 	// 1. Load instance; if gone, nothing to do
@@ -111,6 +203,7 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (err error
 		Get(ctx, req.Name, metav1.GetOptions{})
 	if apierrors.IsNotFound(err) {
 		log.Info("instance not found (likely deleted)")
+		c.Release(req.NamespacedName)
 		return nil
 	}
 	if err != nil {
@@ -119,9 +212,19 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (err error
 	}
 
 	//--------------------------------------------------------------
-	// 2. Create a fresh runtime for this reconciliation
+	// 2. Pick the graph revision and create a fresh runtime for this
+	//    reconciliation
 	//--------------------------------------------------------------
-	runtimeObj, err := runtime.FromGraph(c.rgd, inst)
+	revision, rgd, revisionErr := c.graphFor(ctx, inst)
+	if revisionErr != nil {
+		// Report the error with the latest graph, keeping the revision the
+		// instance was last reconciled with. Deletion can proceed with the
+		// latest graph, as it only needs the identity of the resources.
+		log.Error(revisionErr, "failed to resolve graph revision")
+		revision, _, _ = unstructured.NestedInt64(inst.Object, "status", "graphRevision")
+		rgd = c.rgd
+	}
+	runtimeObj, err := runtime.FromGraph(rgd, inst)
 	if err != nil {
 		log.Error(err, "failed to create runtime")
 		return err
@@ -139,6 +242,7 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (err error
 		c.reconcileConfig,
 		inst,
 	)
+	rcx.GraphRevision = revision
 
 	//--------------------------------------------------------------
 	// 4. Handle deletion: clean up children and status
@@ -152,7 +256,18 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (err error
 	}
 
 	//--------------------------------------------------------------
-	// 5. Ensure finalizer + management labels before mutating children
+	// 5. Delete expired instances, and leave finished one-shot
+	//    instances untouched until they expire
+	//--------------------------------------------------------------
+	if expired, err := c.reconcileExpiry(rcx); expired {
+		return err
+	}
+	if finished, err := c.reconcileFinished(rcx); finished {
+		return requeueAtExpiry(rcx, err)
+	}
+
+	//--------------------------------------------------------------
+	// 6. Ensure finalizer + management labels before mutating children
 	//--------------------------------------------------------------
 	if err := c.ensureManaged(rcx); err != nil {
 		rcx.Mark.InstanceNotManaged("finalizer/labeling failed: %v", err)
@@ -161,38 +276,105 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (err error
 	}
 
 	//--------------------------------------------------------------
-	// 6. Resolve Graph (CEL, dependencies); allow data-pending
+	// 7. Resolve Graph (CEL, dependencies); allow data-pending
 	//--------------------------------------------------------------
+	if revisionErr != nil {
+		rcx.Mark.GraphResolutionFailed("%v", revisionErr)
+		_ = c.updateStatus(rcx)
+		return revisionErr
+	}
 	rcx.Mark.GraphResolved()
 
 	//--------------------------------------------------------------
-	// 7. Reconcile resources (SSA + prune) and update runtime state
+	// 8. Reconcile resources (SSA + prune) and update runtime state,
+	//    and evaluate the completion of one-shot instances
 	//--------------------------------------------------------------
-	if err := c.reconcileResources(rcx); err != nil {
-		rcx.Mark.ResourcesNotReady("resource reconciliation failed: %v", err)
+	if err := c.reconcileResourcesAndCompletion(rcx); err != nil {
 		_ = c.updateStatus(rcx)
 		return err
 	}
-	// Only mark ResourcesReady if all resources reached terminal state.
-	// Resources with unsatisfied readyWhen are in WaitingForReadiness,
-	// which keeps StateManager.State as IN_PROGRESS.
-	switch rcx.StateManager.State {
-	case InstanceStateActive:
-		rcx.Mark.ResourcesReady()
-	case InstanceStateError:
-		if err := rcx.StateManager.ResourceErrors(); err != nil {
-			rcx.Mark.ResourcesNotReady("resource error: %v", err)
-		} else {
-			rcx.Mark.ResourcesNotReady("resource reconciliation error")
-		}
-	default:
-		rcx.Mark.ResourcesNotReady("awaiting resource readiness")
-	}
 
 	//--------------------------------------------------------------
-	// 8. Persist status/conditions
+	// 9. Persist status/conditions
 	//--------------------------------------------------------------
-	return c.updateStatus(rcx)
+	if err := c.updateStatus(rcx); err != nil {
+		return err
+	}
+	// Instances that just finished, or that have an expiry, are requeued
+	// when they expire.
+	_, err = c.reconcileFinished(rcx)
+	return requeueAtExpiry(rcx, err)
+}
+
+// reconcileResourcesAndCompletion reconciles the resources of the instance,
+// then evaluates the completion of one-shot instances. Completion is evaluated
+// even if resources are still pending: a failed resource may leave the
+// resources depending on it unresolved for good, and the instance must still
+// finish. The returned error is nil once the instance finished.
+func (c *Controller) reconcileResourcesAndCompletion(rcx *ReconcileContext) error {
+	resourcesErr := c.reconcileResources(rcx)
+	if resourcesErr != nil {
+		rcx.Mark.ResourcesNotReady("resource reconciliation failed: %v", resourcesErr)
+	} else {
+		// Only mark ResourcesReady if all resources reached terminal state.
+		// Resources with unsatisfied readyWhen are in WaitingForReadiness,
+		// which keeps StateManager.State as IN_PROGRESS.
+		switch rcx.StateManager.State {
+		case InstanceStateActive:
+			rcx.Mark.ResourcesReady()
+		case InstanceStateError:
+			if err := rcx.StateManager.ResourceErrors(); err != nil {
+				rcx.Mark.ResourcesNotReady("resource error: %v", err)
+			} else {
+				rcx.Mark.ResourcesNotReady("resource reconciliation error")
+			}
+		default:
+			rcx.Mark.ResourcesNotReady("awaiting resource readiness")
+		}
+	}
+
+	if rcx.Runtime.Instance().Spec.Completion != nil {
+		if err := c.reconcileCompletion(rcx); err != nil && resourcesErr == nil {
+			return err
+		}
+		if _, finished := completionTime(rcx.Instance); finished {
+			return nil
+		}
+	}
+	return resourcesErr
+}
+
+// Release drops the state kept for an instance that is deleted or no longer
+// reconciled by this replica.
+func (c *Controller) Release(instance types.NamespacedName) {
+	c.forgetHealth(instance)
+	if c.externalWatcher != nil {
+		c.externalWatcher.ForgetExternalSelectors(c.gvr, instance)
+	}
+}
+
+// graphFor returns the graph revision the instance must be reconciled with:
+// the revision it is pinned to, the revision the rollout picked for it, or
+// the latest one.
+func (c *Controller) graphFor(ctx context.Context, inst *unstructured.Unstructured) (int64, *graph.Graph, error) {
+	revision, err := metadata.PinnedGraphRevision(inst)
+	if err != nil {
+		return 0, nil, err
+	}
+	if revision == 0 && c.rollout != nil {
+		revision = c.rollout.Revision(inst)
+	}
+	if revision == 0 || revision == c.revision {
+		return c.revision, c.rgd, nil
+	}
+	if c.revisions == nil {
+		return 0, nil, fmt.Errorf("graph revision %d is not available", revision)
+	}
+	g, err := c.revisions.Graph(ctx, revision)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to resolve graph revision %d: %w", revision, err)
+	}
+	return revision, g, nil
 }
 
 func (c *Controller) ensureManaged(rcx *ReconcileContext) error {
@@ -203,7 +385,7 @@ func (c *Controller) ensureManaged(rcx *ReconcileContext) error {
 	if patched != nil {
 		rcx.Instance = patched
 		rcx.Runtime.Instance().SetObserved([]*unstructured.Unstructured{patched})
-		rcx.Mark = NewConditionsMarkerFor(rcx.Instance)
+		rcx.Mark = newConditionsMarker(rcx.Instance, rcx.conditionTypes)
 	}
 	rcx.Mark.InstanceManaged()
 	return nil
@@ -237,10 +419,7 @@ func (c *Controller) applyManagedFinalizerAndLabels(rcx *ReconcileContext) (*uns
 		Object: map[string]interface{}{
 			"apiVersion": obj.GetAPIVersion(),
 			"kind":       obj.GetKind(),
-			"metadata": map[string]interface{}{
-				"name":      obj.GetName(),
-				"namespace": obj.GetNamespace(),
-			},
+			"metadata":   instanceIdentityMetadata(obj),
 		},
 	}
 
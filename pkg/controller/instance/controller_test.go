@@ -0,0 +1,204 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+type fakeGraphRevisions map[int64]*graph.Graph
+
+func (f fakeGraphRevisions) Graph(_ context.Context, revision int64) (*graph.Graph, error) {
+	g, ok := f[revision]
+	if !ok {
+		return nil, fmt.Errorf("graph revision %d not found", revision)
+	}
+	return g, nil
+}
+
+type fakeRollout int64
+
+func (f fakeRollout) Revision(metav1.Object) int64 { return int64(f) }
+
+func TestControllerGraphFor(t *testing.T) {
+	latest := &graph.Graph{}
+	previous := &graph.Graph{}
+
+	tests := map[string]struct {
+		pin          string
+		revisions    GraphRevisions
+		rollout      Rollout
+		wantRevision int64
+		wantGraph    *graph.Graph
+		wantErr      bool
+	}{
+		"not pinned": {
+			wantRevision: 2,
+			wantGraph:    latest,
+		},
+		"pinned to the latest revision": {
+			pin:          "2",
+			wantRevision: 2,
+			wantGraph:    latest,
+		},
+		"pinned to a previous revision": {
+			pin:          "1",
+			revisions:    fakeGraphRevisions{1: previous},
+			wantRevision: 1,
+			wantGraph:    previous,
+		},
+		"rolled out to a previous revision": {
+			revisions:    fakeGraphRevisions{1: previous},
+			rollout:      fakeRollout(1),
+			wantRevision: 1,
+			wantGraph:    previous,
+		},
+		"rolled out to the latest revision": {
+			revisions:    fakeGraphRevisions{1: previous},
+			rollout:      fakeRollout(2),
+			wantRevision: 2,
+			wantGraph:    latest,
+		},
+		"pin takes precedence over the rollout": {
+			pin:          "2",
+			revisions:    fakeGraphRevisions{1: previous},
+			rollout:      fakeRollout(1),
+			wantRevision: 2,
+			wantGraph:    latest,
+		},
+		"pinned to a missing revision": {
+			pin:       "5",
+			revisions: fakeGraphRevisions{1: previous},
+			wantErr:   true,
+		},
+		"pinned without revisions": {
+			pin:     "1",
+			wantErr: true,
+		},
+		"invalid pin": {
+			pin:     "latest",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Controller{rgd: latest, revision: 2, revisions: tt.revisions, rollout: tt.rollout}
+			inst := &unstructured.Unstructured{Object: map[string]any{}}
+			if tt.pin != "" {
+				inst.SetLabels(map[string]string{metadata.GraphRevisionLabel: tt.pin})
+			}
+
+			revision, g, err := c.graphFor(context.Background(), inst)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if revision != tt.wantRevision {
+				t.Errorf("expected revision %d, got %d", tt.wantRevision, revision)
+			}
+			if g != tt.wantGraph {
+				t.Errorf("expected graph %p, got %p", tt.wantGraph, g)
+			}
+		})
+	}
+}
+
+func TestRecordReadyRevision(t *testing.T) {
+	tests := map[string]struct {
+		rollout   Rollout
+		ready     bool
+		label     string
+		wantPatch bool
+		wantLabel string
+	}{
+		"ready without rollout": {ready: true},
+		"ready with rollout": {
+			rollout:   fakeRollout(2),
+			ready:     true,
+			wantPatch: true,
+			wantLabel: "2",
+		},
+		"already labelled": {
+			rollout: fakeRollout(2),
+			ready:   true,
+			label:   "2",
+		},
+		"not ready anymore": {
+			rollout:   fakeRollout(2),
+			label:     "2",
+			wantPatch: true,
+		},
+		"rollout policy removed": {
+			ready:     true,
+			label:     "2",
+			wantPatch: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rcx := newCompletionContext(t, nil, "", "")
+			rcx.GraphRevision = 2
+			if tt.label != "" {
+				rcx.Instance.SetLabels(map[string]string{metadata.ReadyGraphRevisionLabel: tt.label})
+			}
+			rcx.Mark.InstanceManaged()
+			rcx.Mark.GraphResolved()
+			if tt.ready {
+				rcx.Mark.ResourcesReady()
+			} else {
+				rcx.Mark.ResourcesNotReady("awaiting resource readiness")
+			}
+
+			var patches []*unstructured.Unstructured
+			rcx.Client.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "*",
+				func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+					patch := &unstructured.Unstructured{}
+					require.NoError(t, patch.UnmarshalJSON(action.(k8stesting.PatchAction).GetPatch()))
+					patches = append(patches, patch)
+					return true, rcx.Instance, nil
+				})
+
+			c := &Controller{rollout: tt.rollout}
+			require.NoError(t, c.recordReadyRevision(rcx))
+			if !tt.wantPatch {
+				assert.Empty(t, patches)
+				return
+			}
+			require.Len(t, patches, 1)
+			assert.Equal(t, "export", patches[0].GetName())
+			assert.Equal(t, tt.wantLabel, patches[0].GetLabels()[metadata.ReadyGraphRevisionLabel])
+		})
+	}
+}
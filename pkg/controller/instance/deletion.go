@@ -31,6 +31,14 @@ func (c *Controller) reconcileDeletion(rcx *ReconcileContext) error {
 	rcx.StateManager.State = InstanceStateDeleting
 	rcx.Mark.ResourcesUnderDeletion("deleting resources")
 
+	done, err := c.runPreDeleteHooks(rcx)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return rcx.delayedRequeue(fmt.Errorf("waiting for pre-delete hooks to complete"))
+	}
+
 	deletionNode, err := c.planResourcesForDeletion(rcx)
 	if err != nil {
 		return err
@@ -44,6 +52,9 @@ func (c *Controller) reconcileDeletion(rcx *ReconcileContext) error {
 		return rcx.delayedRequeue(fmt.Errorf("deleting resource %s", deletionNode.Spec.Meta.ID))
 	}
 
+	// The history is garbage collected with the instance.
+	c.historyHashes.Delete(rcx.Instance.GetUID())
+	c.forgetRecreations(rcx.Instance.GetUID())
 	return c.removeFinalizer(rcx)
 }
 
@@ -94,7 +105,7 @@ func (c *Controller) planResourcesForDeletion(
 		// At this point, identity is resolvable and we can safely observe (GET/LIST)
 		// to find the next deletable node.
 		switch desc.Type {
-		case graph.NodeTypeExternal:
+		case graph.NodeTypeExternal, graph.NodeTypeExternalCollection:
 			rcx.StateManager.ResourceStates[rid] = &ResourceState{State: ResourceStateSkipped}
 			continue
 
@@ -107,7 +118,7 @@ func (c *Controller) planResourcesForDeletion(
 			//
 			// Differently from single resources, we do not do GETs per-item here because
 			// that would be inefficient and cause many API calls during deletion.
-			items, err := c.listCollectionItems(rcx, desc.GVR, rid)
+			items, err := c.listCollectionItems(rcx, desc)
 			if err != nil {
 				rcx.StateManager.ResourceStates[rid] = &ResourceState{State: ResourceStateError, Err: err}
 				return nil, fmt.Errorf("failed to list collection items for %s: %w", rid, err)
@@ -201,7 +212,7 @@ func (c *Controller) removeFinalizer(rcx *ReconcileContext) error {
 	}
 	rcx.Instance = patched
 	rcx.Runtime.Instance().SetObserved([]*unstructured.Unstructured{patched})
-	rcx.Mark = NewConditionsMarkerFor(rcx.Instance)
+	rcx.Mark = newConditionsMarker(rcx.Instance, rcx.conditionTypes)
 	rcx.Mark.ResourcesUnderDeletion("deleting resources")
 	return nil
 }
@@ -223,7 +234,7 @@ func (c *Controller) setUnmanaged(rcx *ReconcileContext, obj *unstructured.Unstr
 	}
 	rcx.Log.Info("Removing managed state", "name", obj.GetName(), "namespace", obj.GetNamespace())
 	instancePatch := &unstructured.Unstructured{}
-	instancePatch.SetUnstructuredContent(map[string]interface{}{"apiVersion": obj.GetAPIVersion(), "kind": obj.GetKind(), "metadata": map[string]interface{}{"name": obj.GetName(), "namespace": obj.GetNamespace()}})
+	instancePatch.SetUnstructuredContent(map[string]interface{}{"apiVersion": obj.GetAPIVersion(), "kind": obj.GetKind(), "metadata": instanceIdentityMetadata(obj)})
 	instancePatch.SetFinalizers(obj.GetFinalizers())
 	metadata.RemoveInstanceFinalizer(instancePatch)
 	updated, err := rcx.InstanceClient().Apply(rcx.Ctx, instancePatch.GetName(), instancePatch, metav1.ApplyOptions{FieldManager: FieldManagerForLabeler, Force: true})
@@ -0,0 +1,337 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	smdpath "sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v6/value"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph/fieldpath"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+const (
+	driftActionCorrected = "corrected"
+	driftActionReported  = "reported"
+
+	// maxReportedDrift is the number of drifted fields listed in the Drifted
+	// condition message.
+	maxReportedDrift = 5
+)
+
+// handleDrift detects the fields of the desired object that drifted on the
+// live object. Drifted fields are either left in the desired object, so that
+// apply corrects them, or removed from it and recorded in the resource state,
+// depending on the drift policy of the node.
+func (c *Controller) handleDrift(
+	rcx *ReconcileContext,
+	node *runtime.Node,
+	st *ResourceState,
+	desired, current *unstructured.Unstructured,
+) {
+	if current == nil {
+		return
+	}
+	policy := node.Spec.Drift
+	drifted, ignored, err := detectDrift(desired, current, policy.Ignore)
+	if err != nil {
+		rcx.Log.V(1).Info("failed to detect drift", "id", node.Spec.Meta.ID, "error", err)
+		return
+	}
+	// Ignored fields are neither corrected nor reported.
+	removeFields(desired.Object, ignored)
+	if len(drifted) == 0 {
+		return
+	}
+
+	paths := make([]string, 0, len(drifted))
+	for _, field := range drifted {
+		paths = append(paths, fieldpath.Build(field))
+	}
+
+	if !policy.Report {
+		driftTotal.WithLabelValues(rcx.GVR.String(), driftActionCorrected).Add(float64(len(drifted)))
+		rcx.Log.Info("correcting drift", "id", node.Spec.Meta.ID, "name", desired.GetName(), "fields", paths)
+		return
+	}
+
+	rcx.Log.V(1).Info("reporting drift", "id", node.Spec.Meta.ID, "name", desired.GetName(), "fields", paths)
+	removeFields(desired.Object, drifted)
+	for _, path := range paths {
+		st.Drift = append(st.Drift, fmt.Sprintf("%s %s", desired.GetName(), path))
+	}
+}
+
+// markDrift sets the Drifted condition of the instance from the drift
+// recorded in the resource states.
+func (c *Controller) markDrift(rcx *ReconcileContext) {
+	var drifted []string
+	for id, st := range rcx.StateManager.ResourceStates {
+		for _, field := range st.Drift {
+			drifted = append(drifted, id+"/"+field)
+		}
+	}
+	if len(drifted) == 0 {
+		rcx.Mark.NotDrifted()
+		return
+	}
+	sort.Strings(drifted)
+
+	msg := strings.Join(drifted[:min(len(drifted), maxReportedDrift)], ", ")
+	if len(drifted) > maxReportedDrift {
+		msg += fmt.Sprintf(" and %d more", len(drifted)-maxReportedDrift)
+	}
+	// Reported drift is left in place and detected again on every reconcile:
+	// only count it when the instance becomes drifted.
+	if rcx.Mark.Drifted("%d fields changed outside of kro: %s", len(drifted), msg) {
+		driftTotal.WithLabelValues(rcx.GVR.String(), driftActionReported).Add(float64(len(drifted)))
+	}
+}
+
+// detectDrift returns the fields that drifted on the current object, as
+// paths in the desired object. A field drifted when the desired object sets
+// it, another field manager owns it on the current object, and its current
+// value differs from the desired one. Drifted fields under one of the ignored
+// paths are returned separately.
+func detectDrift(
+	desired, current *unstructured.Unstructured,
+	ignore []string,
+) (drifted, ignored [][]fieldpath.Segment, err error) {
+	_, others, err := managedFieldSets(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	if others.Empty() {
+		return nil, nil, nil
+	}
+
+	ignoredPaths := make([][]fieldpath.Segment, 0, len(ignore))
+	for _, path := range ignore {
+		segments, err := fieldpath.Parse(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid ignored field path %q: %w", path, err)
+		}
+		ignoredPaths = append(ignoredPaths, segments)
+	}
+
+	others.Leaves().Iterate(func(path smdpath.Path) {
+		if err != nil {
+			return
+		}
+		want, segments, ok := resolveField(desired.Object, path)
+		if !ok {
+			return
+		}
+		got, _, _ := resolveField(current.Object, path)
+		var equal bool
+		if equal, err = jsonEqual(want, got); err != nil || equal {
+			return
+		}
+		if hasIgnoredPrefix(segments, ignoredPaths) {
+			ignored = append(ignored, segments)
+		} else {
+			drifted = append(drifted, segments)
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return drifted, ignored, nil
+}
+
+// resolveField returns the value at the managed fields path in obj, along
+// with the path of the value expressed with list indexes.
+func resolveField(obj any, path smdpath.Path) (any, []fieldpath.Segment, bool) {
+	segments := make([]fieldpath.Segment, 0, len(path))
+	for _, pe := range path {
+		switch {
+		case pe.FieldName != nil:
+			m, ok := obj.(map[string]any)
+			if !ok {
+				return nil, nil, false
+			}
+			if obj, ok = m[*pe.FieldName]; !ok {
+				return nil, nil, false
+			}
+			segments = append(segments, fieldpath.NewNamedSegment(*pe.FieldName))
+		default:
+			list, ok := obj.([]any)
+			if !ok {
+				return nil, nil, false
+			}
+			i := listIndex(list, pe)
+			if i < 0 {
+				return nil, nil, false
+			}
+			obj = list[i]
+			segments = append(segments, fieldpath.NewIndexedSegment(i))
+		}
+	}
+	return obj, segments, true
+}
+
+// listIndex returns the index of the list item selected by the path element,
+// or -1 if there is none.
+func listIndex(list []any, pe smdpath.PathElement) int {
+	switch {
+	case pe.Index != nil:
+		if *pe.Index < len(list) {
+			return *pe.Index
+		}
+	case pe.Value != nil:
+		for i, item := range list {
+			if value.Equals(value.NewValueInterface(item), *pe.Value) {
+				return i
+			}
+		}
+	case pe.Key != nil:
+	items:
+		for i, item := range list {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, key := range *pe.Key {
+				v, ok := m[key.Name]
+				if !ok || !value.Equals(value.NewValueInterface(v), key.Value) {
+					continue items
+				}
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// removeFields removes the fields at the given paths from obj. The paths are
+// removed in reverse order, so that removing a list item never shifts the
+// index of a list item left to remove.
+func removeFields(obj map[string]any, paths [][]fieldpath.Segment) {
+	paths = slices.Clone(paths)
+	slices.SortFunc(paths, func(a, b []fieldpath.Segment) int {
+		return compareSegments(b, a)
+	})
+	for _, path := range paths {
+		removeField(obj, path)
+	}
+}
+
+// removeField removes the field at the given path from obj. Paths ending
+// with a list index, e.g. an item of an associative list selected by its
+// keys, remove the item from the list.
+func removeField(obj map[string]any, path []fieldpath.Segment) {
+	if len(path) == 0 {
+		return
+	}
+	last := path[len(path)-1]
+	parent := fieldAt(obj, path[:len(path)-1])
+	if last.Index < 0 {
+		if m, ok := parent.(map[string]any); ok {
+			delete(m, last.Name)
+		}
+		return
+	}
+
+	list, ok := parent.([]any)
+	if !ok || last.Index >= len(list) || len(path) < 2 {
+		return
+	}
+	list = slices.Delete(list, last.Index, last.Index+1)
+	// The shorter list replaces the list in its own parent.
+	switch holder, key := fieldAt(obj, path[:len(path)-2]), path[len(path)-2]; h := holder.(type) {
+	case map[string]any:
+		h[key.Name] = list
+	case []any:
+		h[key.Index] = list
+	}
+}
+
+// fieldAt returns the value at the given path in obj, or nil if there is
+// none.
+func fieldAt(obj any, path []fieldpath.Segment) any {
+	for _, segment := range path {
+		if segment.Index >= 0 {
+			list, ok := obj.([]any)
+			if !ok || segment.Index >= len(list) {
+				return nil
+			}
+			obj = list[segment.Index]
+			continue
+		}
+		m, ok := obj.(map[string]any)
+		if !ok {
+			return nil
+		}
+		obj = m[segment.Name]
+	}
+	return obj
+}
+
+// compareSegments orders paths segment by segment, list indexes numerically.
+// Paths come after their prefixes.
+func compareSegments(a, b []fieldpath.Segment) int {
+	for i := range min(len(a), len(b)) {
+		if c := cmp.Compare(a[i].Index, b[i].Index); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a[i].Name, b[i].Name); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// hasIgnoredPrefix returns true if path is one of the ignored paths, or is
+// nested under one of them.
+func hasIgnoredPrefix(path []fieldpath.Segment, ignored [][]fieldpath.Segment) bool {
+	for _, prefix := range ignored {
+		if len(prefix) <= len(path) && segmentsEqual(prefix, path[:len(prefix)]) {
+			return true
+		}
+	}
+	return false
+}
+
+func segmentsEqual(a, b []fieldpath.Segment) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonEqual compares two unstructured values by their JSON encoding, so that
+// numbers of different Go types compare equal.
+func jsonEqual(a, b any) (bool, error) {
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bj, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aj, bj), nil
+}
@@ -0,0 +1,302 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubernetes-sigs/kro/pkg/controller/instance/applyset"
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/graph/fieldpath"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+func newDriftDeployment(replicas int64, image string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "my-app"},
+		"spec": map[string]any{
+			"replicas": replicas,
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{"name": "sidecar", "image": "envoy"},
+						map[string]any{"name": "app", "image": image},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func managedFields(manager, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:    manager,
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	const kubectlFields = `{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":` +
+		`{"k:{\"name\":\"app\"}":{".":{},"f:image":{}}}}}}}`
+
+	tests := []struct {
+		name    string
+		desired *unstructured.Unstructured
+		current *unstructured.Unstructured
+		fields  []metav1.ManagedFieldsEntry
+		ignore  []string
+		want    []string
+		ignored []string
+	}{
+		{
+			name:    "no other manager",
+			desired: newDriftDeployment(3, "nginx:1.27"),
+			current: newDriftDeployment(5, "nginx:1.28"),
+			fields:  []metav1.ManagedFieldsEntry{managedFields(applyset.FieldManager, kubectlFields)},
+		},
+		{
+			name:    "fields changed by another manager",
+			desired: newDriftDeployment(3, "nginx:1.27"),
+			current: newDriftDeployment(5, "nginx:1.28"),
+			fields:  []metav1.ManagedFieldsEntry{managedFields("kubectl-edit", kubectlFields)},
+			want:    []string{"spec.replicas", "spec.template.spec.containers[1].image"},
+		},
+		{
+			name:    "same values are not drift",
+			desired: newDriftDeployment(3, "nginx:1.27"),
+			current: newDriftDeployment(3, "nginx:1.27"),
+			fields:  []metav1.ManagedFieldsEntry{managedFields("kubectl-edit", kubectlFields)},
+		},
+		{
+			name:    "ignored fields",
+			desired: newDriftDeployment(3, "nginx:1.27"),
+			current: newDriftDeployment(5, "nginx:1.28"),
+			fields:  []metav1.ManagedFieldsEntry{managedFields("kubectl-edit", kubectlFields)},
+			ignore:  []string{"spec.template"},
+			want:    []string{"spec.replicas"},
+			ignored: []string{"spec.template.spec.containers[1].image"},
+		},
+		{
+			name:    "status subresource",
+			desired: newDriftDeployment(3, "nginx:1.27"),
+			current: newDriftDeployment(5, "nginx:1.27"),
+			fields: []metav1.ManagedFieldsEntry{func() metav1.ManagedFieldsEntry {
+				entry := managedFields("kube-controller-manager", `{"f:spec":{"f:replicas":{}}}`)
+				entry.Subresource = "status"
+				return entry
+			}()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.current.SetManagedFields(tt.fields)
+			drifted, ignored, err := detectDrift(tt.desired, tt.current, tt.ignore)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buildPaths(drifted); !slices.Equal(got, tt.want) {
+				t.Errorf("expected drift %v, got %v", tt.want, got)
+			}
+			if got := buildPaths(ignored); !slices.Equal(got, tt.ignored) {
+				t.Errorf("expected ignored drift %v, got %v", tt.ignored, got)
+			}
+		})
+	}
+}
+
+func buildPaths(paths [][]fieldpath.Segment) []string {
+	var built []string
+	for _, path := range paths {
+		built = append(built, fieldpath.Build(path))
+	}
+	slices.Sort(built)
+	return built
+}
+
+func TestRemoveFields(t *testing.T) {
+	obj := newDriftDeployment(3, "nginx:1.27")
+	var paths [][]fieldpath.Segment
+	for _, path := range []string{"spec.template.spec.containers[0]", "spec.replicas", "spec.template.spec.containers[1].image"} {
+		segments, err := fieldpath.Parse(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		paths = append(paths, segments)
+	}
+	removeFields(obj.Object, paths)
+
+	if _, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		t.Errorf("expected spec.replicas to be removed")
+	}
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	want := []any{map[string]any{"name": "app"}}
+	if !equality.Semantic.DeepEqual(containers, want) {
+		t.Errorf("expected containers %v, got %v", want, containers)
+	}
+}
+
+func TestHandleDrift_ContainerFields(t *testing.T) {
+	newDeployment := func(sidecarImage, appImage, a, b string) *unstructured.Unstructured {
+		obj := newDriftDeployment(3, appImage)
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		containers[0].(map[string]any)["image"] = sidecarImage
+		containers[1].(map[string]any)["env"] = []any{
+			map[string]any{"name": "A", "value": a},
+			map[string]any{"name": "B", "value": b},
+		}
+		_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+		return obj
+	}
+	// kubectl changed the image of both containers, and replaced both
+	// environment variables of the app container.
+	const kubectlFields = `{"f:spec":{"f:template":{"f:spec":{"f:containers":{` +
+		`"k:{\"name\":\"sidecar\"}":{"f:image":{}},` +
+		`"k:{\"name\":\"app\"}":{"f:image":{},"f:env":{"k:{\"name\":\"A\"}":{".":{}},"k:{\"name\":\"B\"}":{".":{}}}}` +
+		`}}}}}`
+
+	desired := newDeployment("envoy", "nginx:1.27", "1", "2")
+	current := newDeployment("envoy:v2", "nginx:1.28", "x", "y")
+	current.SetManagedFields([]metav1.ManagedFieldsEntry{managedFields("kubectl-edit", kubectlFields)})
+	node := &runtime.Node{Spec: &graph.Node{
+		Meta:  graph.NodeMeta{ID: "deployment"},
+		Drift: graph.DriftPolicy{Report: true},
+	}}
+	rcx := &ReconcileContext{Log: logr.Discard()}
+	st := &ResourceState{}
+
+	(&Controller{}).handleDrift(rcx, node, st, desired, current)
+
+	wantDrift := []string{
+		"my-app spec.template.spec.containers[0].image",
+		"my-app spec.template.spec.containers[1].env[0]",
+		"my-app spec.template.spec.containers[1].env[1]",
+		"my-app spec.template.spec.containers[1].image",
+	}
+	slices.Sort(st.Drift)
+	if !slices.Equal(st.Drift, wantDrift) {
+		t.Errorf("expected drift %v, got %v", wantDrift, st.Drift)
+	}
+	// Drifted fields are left out of the apply, the containers are kept.
+	containers, _, _ := unstructured.NestedSlice(desired.Object, "spec", "template", "spec", "containers")
+	want := []any{
+		map[string]any{"name": "sidecar"},
+		map[string]any{"name": "app", "env": []any{}},
+	}
+	if !equality.Semantic.DeepEqual(containers, want) {
+		t.Errorf("expected containers %v, got %v", want, containers)
+	}
+}
+
+func TestReleaseIgnoredFields(t *testing.T) {
+	const hpaFields = `{"f:spec":{"f:replicas":{}}}`
+	const kroFields = `{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":` +
+		`{"k:{\"name\":\"app\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`
+
+	tests := []struct {
+		name        string
+		current     *unstructured.Unstructured
+		fields      []metav1.ManagedFieldsEntry
+		wantRemoved bool
+	}{
+		{
+			name: "not created yet",
+		},
+		{
+			name:    "only managed by kro",
+			current: newDriftDeployment(3, "nginx:1.27"),
+			fields:  []metav1.ManagedFieldsEntry{managedFields(applyset.FieldManager, kroFields)},
+		},
+		{
+			name:    "managed by another controller",
+			current: newDriftDeployment(5, "nginx:1.27"),
+			fields: []metav1.ManagedFieldsEntry{
+				managedFields(applyset.FieldManager, kroFields),
+				func() metav1.ManagedFieldsEntry {
+					entry := managedFields("horizontal-pod-autoscaler", hpaFields)
+					entry.Subresource = "scale"
+					return entry
+				}(),
+			},
+			wantRemoved: true,
+		},
+		{
+			name:        "not managed by kro",
+			current:     newDriftDeployment(5, "nginx:1.27"),
+			wantRemoved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newDriftDeployment(3, "nginx:1.27")
+			if tt.current != nil {
+				tt.current.SetManagedFields(tt.fields)
+			}
+			node := &runtime.Node{Spec: &graph.Node{IgnoreFields: []string{"spec.replicas"}}}
+			rcx := &ReconcileContext{Log: logr.Discard()}
+
+			(&Controller{}).releaseIgnoredFields(rcx, node, desired, tt.current)
+
+			_, found, _ := unstructured.NestedInt64(desired.Object, "spec", "replicas")
+			if found == tt.wantRemoved {
+				t.Errorf("expected spec.replicas removed=%v, got %v", tt.wantRemoved, !found)
+			}
+			if _, found, _ := unstructured.NestedSlice(desired.Object, "spec", "template", "spec", "containers"); !found {
+				t.Errorf("expected other fields to be kept")
+			}
+		})
+	}
+}
+
+func TestMarkDrift_CountsReportedDriftOnce(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "kro.run", Version: "v1alpha1", Resource: "driftcounts"}
+	reported := driftTotal.WithLabelValues(gvr.String(), driftActionReported)
+	inst := &unstructured.Unstructured{Object: map[string]any{}}
+	rcx := &ReconcileContext{
+		GVR:          gvr,
+		Mark:         NewConditionsMarkerFor(inst),
+		StateManager: newStateManager(),
+	}
+	rcx.StateManager.ResourceStates["deployment"] = &ResourceState{
+		Drift: []string{"my-app spec.replicas", "my-app spec.template.spec.containers[0].image"},
+	}
+
+	// The drift is detected again on every reconcile, but only counted when
+	// the instance becomes drifted.
+	c := &Controller{}
+	c.markDrift(rcx)
+	c.markDrift(rcx)
+	assert.Equal(t, 2.0, testutil.ToFloat64(reported))
+
+	rcx.StateManager.ResourceStates["deployment"].Drift = nil
+	c.markDrift(rcx)
+	rcx.StateManager.ResourceStates["deployment"].Drift = []string{"my-app spec.replicas"}
+	c.markDrift(rcx)
+	assert.Equal(t, 3.0, testutil.ToFloat64(reported))
+}
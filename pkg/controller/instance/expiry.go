@@ -0,0 +1,89 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+	"github.com/kubernetes-sigs/kro/pkg/requeue"
+)
+
+// reconcileExpiry deletes the instance once the time set by its expiry
+// annotations passed, and returns true if it did. Invalid annotations are
+// reported in the InvalidExpiry condition, and the instance does not expire
+// until they are fixed.
+func (c *Controller) reconcileExpiry(rcx *ReconcileContext) (bool, error) {
+	inst := rcx.Instance
+	expiresAt, ok, err := metadata.ExpiresAt(inst)
+	if err != nil {
+		c.recordExpiry(inst, time.Time{})
+		// The condition is kept until the annotations are fixed: only report
+		// the error when it changes.
+		if rcx.Mark.InvalidExpiry("%v", err) {
+			rcx.Log.Error(err, "ignoring instance expiry")
+			if c.recorder != nil {
+				c.recorder.Eventf(inst, nil, corev1.EventTypeWarning, "InvalidExpiry", "Expire", err.Error())
+			}
+		}
+		return false, nil
+	}
+	rcx.Mark.ValidExpiry()
+	if !ok {
+		c.recordExpiry(inst, time.Time{})
+		return false, nil
+	}
+	if time.Now().Before(expiresAt) {
+		c.recordExpiry(inst, expiresAt)
+		return false, nil
+	}
+
+	uid := inst.GetUID()
+	err = rcx.InstanceClient().Delete(rcx.Ctx, inst.GetName(), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return true, fmt.Errorf("failed to delete expired instance: %w", err)
+	}
+	c.recordExpiry(inst, time.Time{})
+	expiryDeletionsTotal.WithLabelValues(c.gvr.String()).Inc()
+	rcx.Log.Info("deleted expired instance", "expiresAt", expiresAt)
+	if c.recorder != nil {
+		c.recorder.Eventf(inst, nil, corev1.EventTypeNormal, "Expired", "Delete",
+			"Deleting instance, it expired at %s", expiresAt.UTC().Format(time.RFC3339))
+	}
+	return true, nil
+}
+
+// requeueAtExpiry requeues the instance when it expires, unless err already
+// requeues it earlier or reports a failure, in which case err is returned.
+func requeueAtExpiry(rcx *ReconcileContext, err error) error {
+	expiresAt, ok, _ := metadata.ExpiresAt(rcx.Instance)
+	if !ok {
+		return err
+	}
+	remaining := time.Until(expiresAt)
+	var after *requeue.RequeueNeededAfter
+	if err != nil && (!errors.As(err, &after) || after.Duration() <= remaining) {
+		return err
+	}
+	return requeue.NeededAfter(fmt.Errorf("instance expires in %s", remaining.Round(time.Second)), remaining)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+	"github.com/kubernetes-sigs/kro/pkg/requeue"
+)
+
+func TestReconcileExpiry(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantExpired bool
+		wantEvent   bool
+		wantInvalid bool
+	}{
+		{name: "no expiry"},
+		{
+			name:        "not expired yet",
+			annotations: map[string]string{metadata.ExpiresAtAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339)},
+		},
+		{
+			name:        "expired",
+			annotations: map[string]string{metadata.ExpiresAtAnnotation: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+			wantExpired: true,
+			wantEvent:   true,
+		},
+		{
+			name:        "invalid annotation",
+			annotations: map[string]string{metadata.TTLAnnotation: "3d"},
+			wantEvent:   true,
+			wantInvalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcx := newCompletionContext(t, nil, "", "")
+			rcx.Instance.SetAnnotations(tt.annotations)
+			recorder := events.NewFakeRecorder(1)
+			c := &Controller{gvr: exportGVR, recorder: recorder}
+
+			expired, err := c.reconcileExpiry(rcx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExpired, expired)
+			assert.Equal(t, tt.wantEvent, len(recorder.Events) == 1)
+			assert.Equal(t, tt.wantInvalid, rcx.Mark.cs.Get(InvalidExpiry) != nil)
+
+			_, err = rcx.InstanceClient().Get(rcx.Ctx, "export", metav1.GetOptions{})
+			assert.Equal(t, tt.wantExpired, apierrors.IsNotFound(err))
+		})
+	}
+}
+
+func TestReconcileExpiryReportsInvalidAnnotationsOnce(t *testing.T) {
+	rcx := newCompletionContext(t, nil, "", "")
+	rcx.Instance.SetAnnotations(map[string]string{metadata.TTLAnnotation: "3d"})
+	recorder := events.NewFakeRecorder(2)
+	c := &Controller{gvr: exportGVR, recorder: recorder}
+
+	for range 2 {
+		expired, err := c.reconcileExpiry(rcx)
+		require.NoError(t, err)
+		assert.False(t, expired)
+	}
+	assert.Len(t, recorder.Events, 1)
+
+	// Fixing the annotations clears the condition.
+	rcx.Instance.SetAnnotations(map[string]string{metadata.TTLAnnotation: "72h"})
+	_, err := c.reconcileExpiry(rcx)
+	require.NoError(t, err)
+	assert.Nil(t, rcx.Mark.cs.Get(InvalidExpiry))
+}
+
+func TestRequeueAtExpiry(t *testing.T) {
+	failure := errors.New("failure")
+	soon := requeue.NeededAfter(errors.New("soon"), time.Minute)
+	later := requeue.NeededAfter(errors.New("later"), 2*time.Hour)
+
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		err       error
+		want      error
+	}{
+		{name: "no expiry"},
+		{name: "no expiry with an error", err: failure, want: failure},
+		{name: "expiry", expiresIn: time.Hour},
+		{name: "failure first", expiresIn: time.Hour, err: failure, want: failure},
+		{name: "earlier requeue", expiresIn: time.Hour, err: soon, want: soon},
+		{name: "later requeue", expiresIn: time.Hour, err: later},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcx := newCompletionContext(t, nil, "", "")
+			if tt.expiresIn != 0 {
+				rcx.Instance.SetAnnotations(map[string]string{
+					metadata.ExpiresAtAnnotation: time.Now().Add(tt.expiresIn).Format(time.RFC3339),
+				})
+			}
+
+			err := requeueAtExpiry(rcx, tt.err)
+			if tt.want != nil || tt.expiresIn == 0 {
+				assert.Equal(t, tt.want, err)
+				return
+			}
+			var after *requeue.RequeueNeededAfter
+			require.True(t, errors.As(err, &after), "expected a requeue, got %v", err)
+			assert.InDelta(t, tt.expiresIn.Seconds(), after.Duration().Seconds(), 5)
+		})
+	}
+}
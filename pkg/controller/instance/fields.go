@@ -0,0 +1,98 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"bytes"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	smdpath "sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+
+	"github.com/kubernetes-sigs/kro/pkg/controller/instance/applyset"
+	"github.com/kubernetes-sigs/kro/pkg/graph/fieldpath"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+// releaseIgnoredFields removes the ignored fields of the node from the desired
+// object, so that kro stops applying them, once the object exists and another
+// field manager manages them. As long as kro is the only manager of an
+// ignored field, the field is kept: leaving it out of the apply would delete
+// it.
+func (c *Controller) releaseIgnoredFields(
+	rcx *ReconcileContext,
+	node *runtime.Node,
+	desired, current *unstructured.Unstructured,
+) {
+	if current == nil || len(node.Spec.IgnoreFields) == 0 {
+		return
+	}
+	kro, others, err := managedFieldSets(current)
+	if err != nil {
+		rcx.Log.V(1).Info("failed to read managed fields", "id", node.Spec.Meta.ID, "error", err)
+		return
+	}
+
+	var released [][]fieldpath.Segment
+	for _, path := range node.Spec.IgnoreFields {
+		segments, err := fieldpath.Parse(path)
+		if err != nil {
+			continue
+		}
+		if managesUnder(kro, current, segments) && !managesUnder(others, current, segments) {
+			continue
+		}
+		released = append(released, segments)
+	}
+	removeFields(desired.Object, released)
+}
+
+// managedFieldSets returns the fields of obj managed by kro, and the fields
+// managed by every other field manager. Fields managed through the status
+// subresource are left out, kro never applies them.
+func managedFieldSets(obj *unstructured.Unstructured) (kro, others *smdpath.Set, err error) {
+	kro, others = &smdpath.Set{}, &smdpath.Set{}
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Subresource == "status" || entry.FieldsV1 == nil {
+			continue
+		}
+		set := &smdpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse managed fields of %s: %w", entry.Manager, err)
+		}
+		if entry.Manager == applyset.FieldManager {
+			kro = kro.Union(set)
+		} else {
+			others = others.Union(set)
+		}
+	}
+	return kro, others, nil
+}
+
+// managesUnder returns true if the set has a field of obj at the given path,
+// or nested under it.
+func managesUnder(set *smdpath.Set, obj *unstructured.Unstructured, prefix []fieldpath.Segment) bool {
+	prefixes := [][]fieldpath.Segment{prefix}
+	found := false
+	set.Leaves().Iterate(func(path smdpath.Path) {
+		if found {
+			return
+		}
+		if _, segments, ok := resolveField(obj.Object, path); ok && hasIgnoredPrefix(segments, prefixes) {
+			found = true
+		}
+	})
+	return found
+}
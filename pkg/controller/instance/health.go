@@ -0,0 +1,253 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes-sigs/kro/pkg/controller/instance/applyset"
+)
+
+const (
+	// readyTriggerCreate and readyTriggerUpdate tell whether the time to
+	// ready of an instance was measured from its creation or from a change
+	// of its generation.
+	readyTriggerCreate = "create"
+	readyTriggerUpdate = "update"
+)
+
+// health holds the health of the instances reconciled by this replica. It
+// outlives the controllers, which are replaced whenever their
+// ResourceGraphDefinition changes.
+var health = newHealthTracker()
+
+// instanceHealth is the health last reported for an instance.
+type instanceHealth struct {
+	state    string
+	children map[string]int
+	// generation is the latest generation of the instance seen.
+	generation int64
+	// trigger is set while the instance is on its way to ready after being
+	// created or changed, since the time in since. It is empty once the
+	// instance is ready.
+	trigger string
+	since   time.Time
+}
+
+// healthTracker keeps the instance and children gauges up to date. The
+// gauges only carry the ResourceGraphDefinition and state as labels, so the
+// health of each instance is tracked here to move it between states.
+type healthTracker struct {
+	mu sync.Mutex
+	// instances holds the health of instances by ResourceGraphDefinition.
+	instances map[string]map[types.NamespacedName]*instanceHealth
+	// expiries holds the expiry time of the instances that have one, by
+	// ResourceGraphDefinition.
+	expiries map[string]map[types.NamespacedName]time.Time
+}
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{
+		instances: make(map[string]map[types.NamespacedName]*instanceHealth),
+		expiries:  make(map[string]map[types.NamespacedName]time.Time),
+	}
+}
+
+// observe records the state of the instance and the states of its children,
+// and the time it took to become ready if it just did. prevState is the
+// state the instance reported before this reconciliation.
+func (t *healthTracker) observe(
+	rgd string,
+	inst *unstructured.Unstructured,
+	prevState, state string,
+	children map[string]int,
+	now time.Time,
+) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	nn := types.NamespacedName{Namespace: inst.GetNamespace(), Name: inst.GetName()}
+	if t.instances[rgd] == nil {
+		t.instances[rgd] = make(map[types.NamespacedName]*instanceHealth)
+	}
+	h, ok := t.instances[rgd][nn]
+	switch {
+	case !ok:
+		h = &instanceHealth{generation: inst.GetGeneration()}
+		t.instances[rgd][nn] = h
+		// Instances seen for the first time, e.g. after a restart, are only
+		// measured if they were just created. When they changed is unknown
+		// otherwise.
+		if inst.GetGeneration() <= 1 && prevState != InstanceStateActive {
+			h.trigger = readyTriggerCreate
+			h.since = inst.GetCreationTimestamp().Time
+		}
+	case h.generation != inst.GetGeneration():
+		h.generation = inst.GetGeneration()
+		h.trigger = readyTriggerUpdate
+		h.since = now
+	}
+
+	if state == InstanceStateActive && h.trigger != "" {
+		timeToReady.WithLabelValues(rgd, h.trigger).Observe(now.Sub(h.since).Seconds())
+		h.trigger = ""
+	}
+
+	if h.state != state {
+		if h.state != "" {
+			instancesByState.WithLabelValues(rgd, h.state).Dec()
+		}
+		instancesByState.WithLabelValues(rgd, state).Inc()
+		h.state = state
+	}
+	for s, n := range h.children {
+		if children[s] != n {
+			childrenByState.WithLabelValues(rgd, s).Add(float64(children[s] - n))
+		}
+	}
+	for s, n := range children {
+		if _, seen := h.children[s]; !seen {
+			childrenByState.WithLabelValues(rgd, s).Add(float64(n))
+		}
+	}
+	h.children = children
+}
+
+// observeExpiry records when the instance expires, or that it does not if
+// expiresAt is zero, and reports the earliest expiry of the instances of the
+// ResourceGraphDefinition.
+func (t *healthTracker) observeExpiry(rgd string, nn types.NamespacedName, expiresAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if expiresAt.IsZero() {
+		if _, ok := t.expiries[rgd][nn]; !ok {
+			return
+		}
+		delete(t.expiries[rgd], nn)
+	} else {
+		if t.expiries[rgd] == nil {
+			t.expiries[rgd] = make(map[types.NamespacedName]time.Time)
+		}
+		t.expiries[rgd][nn] = expiresAt
+	}
+	t.reportNextExpiryLocked(rgd)
+}
+
+// reportNextExpiryLocked sets the next expiry gauge of the
+// ResourceGraphDefinition to the earliest expiry of its instances, or removes
+// it if none expires. Must be called with t.mu held.
+func (t *healthTracker) reportNextExpiryLocked(rgd string) {
+	var next time.Time
+	for _, expiresAt := range t.expiries[rgd] {
+		if next.IsZero() || expiresAt.Before(next) {
+			next = expiresAt
+		}
+	}
+	if next.IsZero() {
+		delete(t.expiries, rgd)
+		nextExpiry.DeleteLabelValues(rgd)
+		return
+	}
+	nextExpiry.WithLabelValues(rgd).Set(float64(next.Unix()))
+}
+
+// forget removes the instance from the gauges.
+func (t *healthTracker) forget(rgd string, nn types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.expiries[rgd][nn]; ok {
+		delete(t.expiries[rgd], nn)
+		t.reportNextExpiryLocked(rgd)
+	}
+	h, ok := t.instances[rgd][nn]
+	if !ok {
+		return
+	}
+	if h.state != "" {
+		instancesByState.WithLabelValues(rgd, h.state).Dec()
+	}
+	for s, n := range h.children {
+		childrenByState.WithLabelValues(rgd, s).Sub(float64(n))
+	}
+	delete(t.instances[rgd], nn)
+}
+
+// forgetRGD drops every metric reported for the ResourceGraphDefinition.
+func (t *healthTracker) forgetRGD(rgd string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.instances, rgd)
+	delete(t.expiries, rgd)
+	labels := prometheus.Labels{"rgd": rgd}
+	nextExpiry.DeletePartialMatch(labels)
+	instancesByState.DeletePartialMatch(labels)
+	childrenByState.DeletePartialMatch(labels)
+	timeToReady.DeletePartialMatch(labels)
+	appliesTotal.DeletePartialMatch(labels)
+	noopAppliesTotal.DeletePartialMatch(labels)
+	prunesTotal.DeletePartialMatch(labels)
+}
+
+// ForgetHealthMetrics drops the instance health metrics of the
+// ResourceGraphDefinition. It is called once the ResourceGraphDefinition is
+// deleted.
+func ForgetHealthMetrics(rgd string) {
+	health.forgetRGD(rgd)
+}
+
+// recordHealth reports the state of the instance and of its resources once
+// its status is updated.
+func (c *Controller) recordHealth(rcx *ReconcileContext, state string) {
+	children := make(map[string]int)
+	for _, st := range rcx.StateManager.ResourceStates {
+		children[st.State]++
+	}
+	prevState, _, _ := unstructured.NestedString(rcx.Instance.Object, "status", "state")
+	health.observe(c.rgdName, rcx.Instance, prevState, state, children, time.Now())
+}
+
+// recordExpiry reports when the instance expires, or that it does not if
+// expiresAt is zero.
+func (c *Controller) recordExpiry(inst *unstructured.Unstructured, expiresAt time.Time) {
+	nn := types.NamespacedName{Namespace: inst.GetNamespace(), Name: inst.GetName()}
+	health.observeExpiry(c.rgdName, nn, expiresAt)
+}
+
+// forgetHealth removes an instance from the health metrics.
+func (c *Controller) forgetHealth(nn types.NamespacedName) {
+	health.forget(c.rgdName, nn)
+}
+
+// recordApplies counts the resources applied, and those the apply left
+// unchanged.
+func (c *Controller) recordApplies(result *applyset.ApplyResult) {
+	for _, item := range result.Applied {
+		if item.Error != nil {
+			continue
+		}
+		appliesTotal.WithLabelValues(c.rgdName).Inc()
+		if !item.Changed {
+			noopAppliesTotal.WithLabelValues(c.rgdName).Inc()
+		}
+	}
+}
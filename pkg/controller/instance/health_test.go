@@ -0,0 +1,136 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func gaugeValue(t *testing.T, vec *prometheus.GaugeVec, labels ...string) float64 {
+	t.Helper()
+	m := &dto.Metric{}
+	require.NoError(t, vec.WithLabelValues(labels...).Write(m))
+	return m.GetGauge().GetValue()
+}
+
+func timeToReadyCount(t *testing.T, rgd, trigger string) uint64 {
+	t.Helper()
+	m := &dto.Metric{}
+	require.NoError(t, timeToReady.WithLabelValues(rgd, trigger).(prometheus.Metric).Write(m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestHealthTracker(t *testing.T) {
+	const rgd = "health-test"
+	t.Cleanup(func() { ForgetHealthMetrics(rgd) })
+
+	created := time.Now().Add(-time.Minute)
+	inst := &unstructured.Unstructured{}
+	inst.SetNamespace("default")
+	inst.SetName("app")
+	inst.SetGeneration(1)
+	inst.SetCreationTimestamp(metav1.NewTime(created))
+
+	tracker := newHealthTracker()
+
+	// A new instance on its way to ready.
+	tracker.observe(rgd, inst, "", InstanceStateInProgress, map[string]int{ResourceStateSynced: 1, ResourceStateWaitingForReadiness: 1}, time.Now())
+	assert.Equal(t, 1.0, gaugeValue(t, instancesByState, rgd, InstanceStateInProgress))
+	assert.Equal(t, 1.0, gaugeValue(t, childrenByState, rgd, ResourceStateSynced))
+	assert.Equal(t, 1.0, gaugeValue(t, childrenByState, rgd, ResourceStateWaitingForReadiness))
+
+	// Once ready, the time since its creation is observed, once.
+	tracker.observe(rgd, inst, InstanceStateInProgress, InstanceStateActive, map[string]int{ResourceStateSynced: 2}, time.Now())
+	tracker.observe(rgd, inst, InstanceStateActive, InstanceStateActive, map[string]int{ResourceStateSynced: 2}, time.Now())
+	assert.Equal(t, 0.0, gaugeValue(t, instancesByState, rgd, InstanceStateInProgress))
+	assert.Equal(t, 1.0, gaugeValue(t, instancesByState, rgd, InstanceStateActive))
+	assert.Equal(t, 2.0, gaugeValue(t, childrenByState, rgd, ResourceStateSynced))
+	assert.Equal(t, 0.0, gaugeValue(t, childrenByState, rgd, ResourceStateWaitingForReadiness))
+	assert.Equal(t, uint64(1), timeToReadyCount(t, rgd, readyTriggerCreate))
+
+	// A generation change is measured again.
+	inst.SetGeneration(2)
+	tracker.observe(rgd, inst, InstanceStateActive, InstanceStateInProgress, map[string]int{ResourceStateSynced: 2}, time.Now())
+	tracker.observe(rgd, inst, InstanceStateInProgress, InstanceStateActive, map[string]int{ResourceStateSynced: 2}, time.Now())
+	assert.Equal(t, uint64(1), timeToReadyCount(t, rgd, readyTriggerUpdate))
+
+	// Deleted instances are removed from the gauges.
+	tracker.forget(rgd, types.NamespacedName{Namespace: "default", Name: "app"})
+	assert.Equal(t, 0.0, gaugeValue(t, instancesByState, rgd, InstanceStateActive))
+	assert.Equal(t, 0.0, gaugeValue(t, childrenByState, rgd, ResourceStateSynced))
+}
+
+func TestHealthTracker_ReadyInstanceAfterRestart(t *testing.T) {
+	const rgd = "health-restart-test"
+	t.Cleanup(func() { ForgetHealthMetrics(rgd) })
+
+	inst := &unstructured.Unstructured{}
+	inst.SetNamespace("default")
+	inst.SetName("app")
+	inst.SetGeneration(1)
+	inst.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-24 * time.Hour)))
+
+	// Instances already ready when first seen are not measured.
+	tracker := newHealthTracker()
+	tracker.observe(rgd, inst, InstanceStateActive, InstanceStateActive, nil, time.Now())
+	assert.Equal(t, 1.0, gaugeValue(t, instancesByState, rgd, InstanceStateActive))
+	assert.Equal(t, uint64(0), timeToReadyCount(t, rgd, readyTriggerCreate))
+}
+
+func TestHealthTracker_NextExpiry(t *testing.T) {
+	const rgd = "health-expiry-test"
+	t.Cleanup(func() { ForgetHealthMetrics(rgd) })
+
+	reported := func() bool {
+		t.Helper()
+		ch := make(chan prometheus.Metric, 16)
+		nextExpiry.Collect(ch)
+		close(ch)
+		for metric := range ch {
+			m := &dto.Metric{}
+			require.NoError(t, metric.Write(m))
+			if m.GetLabel()[0].GetValue() == rgd {
+				return true
+			}
+		}
+		return false
+	}
+
+	first := types.NamespacedName{Namespace: "default", Name: "first"}
+	second := types.NamespacedName{Namespace: "default", Name: "second"}
+	soon := time.Now().Add(time.Hour).Truncate(time.Second)
+	later := soon.Add(time.Hour)
+
+	// The earliest expiry is reported.
+	tracker := newHealthTracker()
+	tracker.observeExpiry(rgd, first, later)
+	tracker.observeExpiry(rgd, second, soon)
+	assert.Equal(t, float64(soon.Unix()), gaugeValue(t, nextExpiry, rgd))
+
+	// Instances that no longer expire, or are forgotten, are not.
+	tracker.observeExpiry(rgd, second, time.Time{})
+	assert.Equal(t, float64(later.Unix()), gaugeValue(t, nextExpiry, rgd))
+	tracker.forget(rgd, first)
+	assert.False(t, reported())
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes-sigs/kro/pkg/controller/instance/applyset"
+	"github.com/kubernetes-sigs/kro/pkg/history"
+)
+
+// recordHistory stores the applied resources in the history of the instance,
+// with the values of Secrets redacted, unless they are the same as the ones of
+// the latest entry. Entries beyond the configured limit are deleted, oldest
+// first.
+func (c *Controller) recordHistory(rcx *ReconcileContext, resources []applyset.Resource) error {
+	limit := c.reconcileConfig.HistoryLimit
+	if limit <= 0 {
+		return nil
+	}
+
+	inst := rcx.Instance
+	entry := &history.Entry{
+		GraphRevision:      rcx.GraphRevision,
+		InstanceGeneration: inst.GetGeneration(),
+	}
+	if spec, ok := inst.Object["spec"].(map[string]any); ok {
+		entry.Spec = runtime.DeepCopyJSON(spec)
+	}
+	for _, r := range resources {
+		if r.SkipApply || r.Object == nil {
+			continue
+		}
+		entry.Resources = append(entry.Resources, history.Redact(r.Object.Object))
+	}
+
+	hash, err := history.Hash(entry)
+	if err != nil {
+		return err
+	}
+	if last, ok := c.historyHashes.Load(inst.GetUID()); ok && last == hash {
+		return nil
+	}
+
+	client := c.client.Kubernetes().AppsV1().ControllerRevisions(history.Namespace(inst))
+	revisions, err := history.List(rcx.Ctx, c.client.Kubernetes(), inst)
+	if err != nil {
+		return err
+	}
+	next := int64(1)
+	if n := len(revisions); n > 0 {
+		latest := revisions[n-1]
+		if latest.Annotations[history.HashAnnotation] == hash {
+			c.historyHashes.Store(inst.GetUID(), hash)
+			return nil
+		}
+		next = latest.Revision + 1
+	}
+
+	cr, err := history.NewControllerRevision(inst, entry, next)
+	if err != nil {
+		return err
+	}
+	if _, err := client.Create(rcx.Ctx, cr, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create history entry %s: %w", cr.Name, err)
+	}
+	c.historyHashes.Store(inst.GetUID(), hash)
+	rcx.Log.V(1).Info("recorded instance history", "revision", next, "graphRevision", entry.GraphRevision)
+
+	for _, old := range revisions[:max(0, len(revisions)+1-limit)] {
+		if err := client.Delete(rcx.Ctx, old.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete history entry %s: %w", old.Name, err)
+		}
+	}
+	return nil
+}
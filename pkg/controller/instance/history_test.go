@@ -0,0 +1,167 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kroclient "github.com/kubernetes-sigs/kro/pkg/client"
+	"github.com/kubernetes-sigs/kro/pkg/controller/instance/applyset"
+	"github.com/kubernetes-sigs/kro/pkg/history"
+)
+
+type fakeClientSet struct {
+	kroclient.SetInterface
+	kubernetes kubernetes.Interface
+}
+
+func (f *fakeClientSet) Kubernetes() kubernetes.Interface { return f.kubernetes }
+
+func newHistoryResources(replicas int64) []applyset.Resource {
+	return []applyset.Resource{
+		{ID: "deployment", Object: &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": "my-app", "namespace": "default"},
+			"spec":       map[string]any{"replicas": replicas},
+		}}},
+		{ID: "skipped", SkipApply: true},
+	}
+}
+
+func TestControllerRecordHistory(t *testing.T) {
+	ctx := context.Background()
+	kube := fake.NewClientset()
+	c := &Controller{
+		client:          &fakeClientSet{kubernetes: kube},
+		reconcileConfig: ReconcileConfig{HistoryLimit: 2},
+	}
+
+	inst := &unstructured.Unstructured{Object: map[string]any{"spec": map[string]any{"replicas": int64(1)}}}
+	inst.SetAPIVersion("kro.run/v1alpha1")
+	inst.SetKind("WebApp")
+	inst.SetNamespace("default")
+	inst.SetName("my-app")
+	inst.SetUID("1234")
+	rcx := &ReconcileContext{Ctx: ctx, Log: logr.Discard(), Instance: inst, GraphRevision: 1}
+
+	record := func(replicas int64) {
+		t.Helper()
+		if err := c.recordHistory(rcx, newHistoryResources(replicas)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	revisions := func() []int64 {
+		t.Helper()
+		list, err := history.List(ctx, kube, inst)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var revisions []int64
+		for _, cr := range list {
+			revisions = append(revisions, cr.Revision)
+		}
+		return revisions
+	}
+
+	record(1)
+	record(1)
+	if got := revisions(); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected revisions [1], got %v", got)
+	}
+
+	// Entries are deduplicated against the cluster when the cache is cold.
+	c.historyHashes.Clear()
+	record(1)
+	if got := revisions(); len(got) != 1 {
+		t.Fatalf("expected a single revision, got %v", got)
+	}
+
+	record(2)
+	record(3)
+	if got := revisions(); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("expected revisions [2 3], got %v", got)
+	}
+
+	list, err := history.List(ctx, kube, inst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry, err := history.Decode(&list[1])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entry.Resources) != 1 || entry.GraphRevision != 1 {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestControllerRecordHistory_RedactsSecrets(t *testing.T) {
+	ctx := context.Background()
+	kube := fake.NewClientset()
+	c := &Controller{
+		client:          &fakeClientSet{kubernetes: kube},
+		reconcileConfig: ReconcileConfig{HistoryLimit: 2},
+	}
+
+	inst := &unstructured.Unstructured{Object: map[string]any{}}
+	inst.SetAPIVersion("kro.run/v1alpha1")
+	inst.SetKind("Database")
+	inst.SetNamespace("default")
+	inst.SetName("db")
+	inst.SetUID("5678")
+	rcx := &ReconcileContext{Ctx: ctx, Log: logr.Discard(), Instance: inst, GraphRevision: 1}
+
+	secret := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "db", "namespace": "default"},
+		"data":       map[string]any{"password": "c2VjcmV0"},
+		"stringData": map[string]any{"token": "plaintext"},
+	}}
+	if err := c.recordHistory(rcx, []applyset.Resource{{ID: "secret", Object: secret}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list, err := history.List(ctx, kube, inst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected a single revision, got %d", len(list))
+	}
+	entry, err := history.Decode(&list[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recorded := entry.Resources[0]
+	for field, key := range map[string]string{"data": "password", "stringData": "token"} {
+		value, _ := recorded[field].(map[string]any)[key].(string)
+		if !strings.HasPrefix(value, "redacted:") {
+			t.Errorf("expected %s.%s to be redacted, got %q", field, key, value)
+		}
+	}
+	// The applied object is not modified.
+	if got := secret.Object["data"].(map[string]any)["password"]; got != "c2VjcmV0" {
+		t.Errorf("applied secret was modified: %v", got)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubernetes-sigs/kro/pkg/controller/instance/applyset"
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+// preDeleteRun is the run of pre-delete hooks.
+const preDeleteRun = "deletion"
+
+// reconcileHook runs the hook of the node for the current run, and records
+// its completion. Hook resources are created outside of the applyset, so that
+// they are never pruned: they are deleted when the hook runs again, when it
+// completed if its cleanup policy says so, or with the instance.
+func (c *Controller) reconcileHook(
+	rcx *ReconcileContext,
+	node *runtime.Node,
+	st *ResourceState,
+	desired *unstructured.Unstructured,
+) error {
+	id := node.Spec.Meta.ID
+	run := hookRun(rcx, node)
+	rc := resourceClientFor(rcx, node.Spec.Meta, desired.GetNamespace())
+
+	current, err := c.getCurrentClusterState(rcx, node.Spec.Meta.GVR, desired.GetNamespace(), desired.GetName())
+	if err != nil {
+		st.State = ResourceStateError
+		st.Err = err
+		return err
+	}
+
+	// The resource of a previous run is deleted before the hook runs again.
+	if current != nil && current.GetLabels()[metadata.HookRunLabel] != run {
+		if current.GetDeletionTimestamp() == nil {
+			if err := deleteHookResource(rcx, node, current); err != nil {
+				st.State = ResourceStateError
+				st.Err = err
+				return err
+			}
+		}
+		st.State = ResourceStateDeleting
+		return nil
+	}
+
+	if rcx.HookRuns[id] == run {
+		node.SetHookDone()
+		st.State = ResourceStateSynced
+		return nil
+	}
+
+	if current == nil {
+		c.applyDecoratorLabels(rcx, desired, id, nil)
+		labels := desired.GetLabels()
+		labels[metadata.HookRunLabel] = run
+		desired.SetLabels(labels)
+
+		current, err = rc.Create(rcx.Ctx, desired, metav1.CreateOptions{FieldManager: applyset.FieldManager})
+		if err != nil {
+			st.State = ResourceStateError
+			st.Err = fmt.Errorf("failed to run hook %s: %w", id, err)
+			return st.Err
+		}
+		rcx.Log.Info("running hook", "id", id, "phase", node.Spec.Hook.Phase, "run", run)
+		c.recordHookEvent(rcx, current, "HookStarted", "Running %s hook %s", node.Spec.Hook.Phase, id)
+	}
+	node.SetObserved([]*unstructured.Unstructured{current})
+
+	if runtime.HasTrueCondition(current, "Failed") {
+		st.State = ResourceStateError
+		st.Err = fmt.Errorf("hook %s failed, delete %s %s to run it again",
+			id, node.Spec.Meta.GVR.Resource, current.GetName())
+		return nil
+	}
+	ready, err := node.IsReady()
+	if err != nil {
+		st.State = ResourceStateError
+		st.Err = err
+		return nil
+	}
+	if !ready {
+		st.State = ResourceStateWaitingForReadiness
+		return nil
+	}
+
+	rcx.HookRuns[id] = run
+	node.SetHookDone()
+	hooksCompletedTotal.WithLabelValues(rcx.GVR.String(), node.Spec.Hook.Phase.String()).Inc()
+	rcx.Log.Info("hook completed", "id", id, "phase", node.Spec.Hook.Phase, "run", run)
+	c.recordHookEvent(rcx, current, "HookCompleted", "%s hook %s completed", node.Spec.Hook.Phase, id)
+
+	if node.Spec.Hook.DeleteOnCompletion {
+		if err := deleteHookResource(rcx, node, current); err != nil {
+			rcx.Log.V(1).Info("failed to delete completed hook resource", "id", id, "error", err)
+		}
+	}
+	st.State = ResourceStateSynced
+	return nil
+}
+
+// runPreDeleteHooks runs the pre-delete hooks of the instance. It returns true
+// once all of them completed.
+func (c *Controller) runPreDeleteHooks(rcx *ReconcileContext) (bool, error) {
+	done := true
+	for _, node := range rcx.Runtime.Nodes() {
+		if !node.Spec.IsHook(graph.HookPhasePreDelete) {
+			continue
+		}
+		id := node.Spec.Meta.ID
+		st := &ResourceState{State: ResourceStateInProgress}
+		rcx.StateManager.ResourceStates[id] = st
+
+		ignored, err := node.IsIgnored()
+		if err != nil {
+			st.State = ResourceStateError
+			st.Err = err
+			return false, err
+		}
+		if ignored {
+			st.State = ResourceStateSkipped
+			continue
+		}
+		desired, err := node.GetDesired()
+		if err != nil {
+			st.State = ResourceStateError
+			st.Err = err
+			return false, err
+		}
+		if len(desired) == 0 {
+			st.State = ResourceStateSkipped
+			continue
+		}
+
+		if err := c.reconcileHook(rcx, node, st, desired[0]); err != nil {
+			return false, err
+		}
+		if st.State == ResourceStateError {
+			return false, st.Err
+		}
+		done = done && st.State == ResourceStateSynced
+	}
+	return done, nil
+}
+
+// hookRun returns the current run of the hook of the node. Pre-delete hooks
+// run once, when the instance is deleted. Other hooks run once per generation
+// of the instance and revision of its graph.
+func hookRun(rcx *ReconcileContext, node *runtime.Node) string {
+	if node.Spec.IsHook(graph.HookPhasePreDelete) {
+		return preDeleteRun
+	}
+	return fmt.Sprintf("%d-%d", rcx.GraphRevision, rcx.Instance.GetGeneration())
+}
+
+// hookRunsFrom returns the hook runs recorded in the status of the instance.
+func hookRunsFrom(instance *unstructured.Unstructured) map[string]string {
+	runs, _, _ := unstructured.NestedStringMap(instance.Object, "status", "hooks")
+	if runs == nil {
+		runs = make(map[string]string)
+	}
+	return runs
+}
+
+// deleteHookResource deletes the resource of a hook, along with its
+// dependents, such as the pods of a Job.
+func deleteHookResource(rcx *ReconcileContext, node *runtime.Node, obj *unstructured.Unstructured) error {
+	rc := resourceClientFor(rcx, node.Spec.Meta, obj.GetNamespace())
+	propagation := metav1.DeletePropagationBackground
+	err := rc.Delete(rcx.Ctx, obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete hook resource %s: %w", obj.GetName(), err)
+	}
+	return nil
+}
+
+func (c *Controller) recordHookEvent(
+	rcx *ReconcileContext,
+	obj *unstructured.Unstructured,
+	reason, note string,
+	args ...any,
+) {
+	if c.recorder != nil {
+		c.recorder.Eventf(rcx.Instance, obj, corev1.EventTypeNormal, reason, "Hook", note, args...)
+	}
+}
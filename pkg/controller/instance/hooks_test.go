@@ -0,0 +1,174 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+func newHookNode(hook *graph.Hook) *runtime.Node {
+	return &runtime.Node{Spec: &graph.Node{
+		Meta: graph.NodeMeta{ID: "migrate", GVR: jobGVR, Namespaced: true},
+		Hook: hook,
+	}}
+}
+
+func newHookJob(run, condition string) *unstructured.Unstructured {
+	job := newRecreateJob("migrate:v1")
+	job.SetLabels(map[string]string{metadata.HookRunLabel: run})
+	if condition != "" {
+		job.Object["status"] = map[string]any{
+			"conditions": []any{map[string]any{"type": condition, "status": "True"}},
+		}
+	}
+	return job
+}
+
+func TestReconcileHook(t *testing.T) {
+	const run = "1-2"
+
+	tests := []struct {
+		name         string
+		hook         *graph.Hook
+		current      *unstructured.Unstructured
+		runs         map[string]string
+		wantState    ResourceState
+		wantDone     bool
+		wantExists   bool
+		wantRunLabel string
+	}{
+		{
+			name:         "not started",
+			hook:         &graph.Hook{Phase: graph.HookPhasePreCreate},
+			wantState:    ResourceState{State: ResourceStateWaitingForReadiness},
+			wantExists:   true,
+			wantRunLabel: run,
+		},
+		{
+			name:         "running",
+			hook:         &graph.Hook{Phase: graph.HookPhasePreCreate},
+			current:      newHookJob(run, ""),
+			wantState:    ResourceState{State: ResourceStateWaitingForReadiness},
+			wantExists:   true,
+			wantRunLabel: run,
+		},
+		{
+			name:         "completed",
+			hook:         &graph.Hook{Phase: graph.HookPhasePreCreate},
+			current:      newHookJob(run, "Complete"),
+			wantState:    ResourceState{State: ResourceStateSynced},
+			wantDone:     true,
+			wantExists:   true,
+			wantRunLabel: run,
+		},
+		{
+			name:      "completed and deleted on completion",
+			hook:      &graph.Hook{Phase: graph.HookPhasePreCreate, DeleteOnCompletion: true},
+			current:   newHookJob(run, "Complete"),
+			wantState: ResourceState{State: ResourceStateSynced},
+			wantDone:  true,
+		},
+		{
+			name:      "already completed for this run",
+			hook:      &graph.Hook{Phase: graph.HookPhasePreCreate, DeleteOnCompletion: true},
+			runs:      map[string]string{"migrate": run},
+			wantState: ResourceState{State: ResourceStateSynced},
+			wantDone:  true,
+		},
+		{
+			name:         "failed",
+			hook:         &graph.Hook{Phase: graph.HookPhasePreCreate},
+			current:      newHookJob(run, "Failed"),
+			wantState:    ResourceState{State: ResourceStateError},
+			wantExists:   true,
+			wantRunLabel: run,
+		},
+		{
+			name:      "previous run",
+			hook:      &graph.Hook{Phase: graph.HookPhasePreCreate},
+			current:   newHookJob("1-1", "Complete"),
+			runs:      map[string]string{"migrate": "1-1"},
+			wantState: ResourceState{State: ResourceStateDeleting},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []k8sruntime.Object
+			if tt.current != nil {
+				objs = append(objs, tt.current)
+			}
+			rcx := newRecreateContext(objs...)
+			rcx.Instance.SetGeneration(2)
+			rcx.GraphRevision = 1
+			rcx.Labeler = metadata.NewKROMetaLabeler()
+			rcx.HookRuns = tt.runs
+			if rcx.HookRuns == nil {
+				rcx.HookRuns = make(map[string]string)
+			}
+			c := &Controller{recorder: events.NewFakeRecorder(2)}
+			node := newHookNode(tt.hook)
+			st := &ResourceState{State: ResourceStateInProgress}
+
+			if err := c.reconcileHook(rcx, node, st, newRecreateJob("migrate:v1")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if st.State != tt.wantState.State {
+				t.Errorf("expected state %s, got %s (%v)", tt.wantState.State, st.State, st.Err)
+			}
+			if got := rcx.HookRuns["migrate"] == run; got != tt.wantDone {
+				t.Errorf("expected run recorded=%v, got %v", tt.wantDone, got)
+			}
+			ready, err := node.IsReady()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ready != tt.wantDone {
+				t.Errorf("expected ready=%v, got %v", tt.wantDone, ready)
+			}
+
+			obj, err := rcx.Client.Resource(jobGVR).Namespace("default").Get(rcx.Ctx, "migrate", metav1.GetOptions{})
+			if exists := !apierrors.IsNotFound(err); exists != tt.wantExists {
+				t.Fatalf("expected exists=%v, got %v", tt.wantExists, exists)
+			}
+			if tt.wantExists && obj.GetLabels()[metadata.HookRunLabel] != tt.wantRunLabel {
+				t.Errorf("expected run label %q, got %q", tt.wantRunLabel, obj.GetLabels()[metadata.HookRunLabel])
+			}
+		})
+	}
+}
+
+func TestHookRun(t *testing.T) {
+	rcx := newRecreateContext()
+	rcx.Instance.SetGeneration(4)
+	rcx.GraphRevision = 3
+
+	if got := hookRun(rcx, newHookNode(&graph.Hook{Phase: graph.HookPhasePostReady})); got != "3-4" {
+		t.Errorf("expected run 3-4, got %q", got)
+	}
+	if got := hookRun(rcx, newHookNode(&graph.Hook{Phase: graph.HookPhasePreDelete})); got != preDeleteRun {
+		t.Errorf("expected run %q, got %q", preDeleteRun, got)
+	}
+}
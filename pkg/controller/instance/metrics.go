@@ -0,0 +1,161 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		driftTotal,
+		recreationsTotal,
+		hooksCompletedTotal,
+		completionsTotal,
+		expirationsTotal,
+		expiryDeletionsTotal,
+		nextExpiry,
+		instancesByState,
+		childrenByState,
+		timeToReady,
+		appliesTotal,
+		noopAppliesTotal,
+		prunesTotal,
+	)
+}
+
+var (
+	// driftTotal counts the drifted fields detected on the resources of
+	// instances, by instance GVR and by what was done about them. Reported
+	// fields are counted when the instance becomes drifted, not on every
+	// reconcile detecting them again.
+	driftTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "instance_drift_detected_total",
+			Help: "Total number of drifted fields detected on instance resources per GVR and action",
+		},
+		[]string{"gvr", "action"},
+	)
+	// recreationsTotal counts the resources deleted to be created again, by
+	// instance GVR and by why they were recreated.
+	recreationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "instance_resource_recreations_total",
+			Help: "Total number of instance resources recreated per GVR and reason",
+		},
+		[]string{"gvr", "reason"},
+	)
+	// hooksCompletedTotal counts the hook runs that completed, by instance
+	// GVR and by hook phase.
+	hooksCompletedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "instance_hooks_completed_total",
+			Help: "Total number of completed hook runs per GVR and phase",
+		},
+		[]string{"gvr", "phase"},
+	)
+	// completionsTotal counts the one-shot instances that finished, by GVR
+	// and by outcome.
+	completionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "instance_completions_total",
+			Help: "Total number of finished one-shot instances per GVR and outcome",
+		},
+		[]string{"gvr", "outcome"},
+	)
+	// expirationsTotal counts the finished one-shot instances deleted once
+	// their time to live expired, by GVR.
+	expirationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "instance_finished_expirations_total",
+			Help: "Total number of finished one-shot instances deleted after their TTL per GVR",
+		},
+		[]string{"gvr"},
+	)
+	// expiryDeletionsTotal counts the instances deleted once the time set by
+	// their expiry annotations passed, by GVR.
+	expiryDeletionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "instance_expiry_annotation_deletions_total",
+			Help: "Total number of instances deleted once the time set by their expiry annotations passed per GVR",
+		},
+		[]string{"gvr"},
+	)
+	// nextExpiry is the time the next instance of a ResourceGraphDefinition
+	// expires, among the instances with expiry annotations.
+	nextExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "instance_next_expiry_timestamp_seconds",
+			Help: "Unix time at which the next instance with expiry annotations expires per ResourceGraphDefinition",
+		},
+		[]string{"rgd"},
+	)
+	// instancesByState is the number of instances by
+	// ResourceGraphDefinition and state.
+	instancesByState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "instance_state",
+			Help: "Number of instances per ResourceGraphDefinition and state",
+		},
+		[]string{"rgd", "state"},
+	)
+	// childrenByState is the number of resources of instances by
+	// ResourceGraphDefinition and state.
+	childrenByState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "instance_resource_state",
+			Help: "Number of instance resources per ResourceGraphDefinition and state",
+		},
+		[]string{"rgd", "state"},
+	)
+	// timeToReady is the time instances take to become ready after they are
+	// created or their generation changes.
+	timeToReady = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "instance_time_to_ready_seconds",
+			Help:    "Time for instances to become ready after creation or a generation change per ResourceGraphDefinition and trigger",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		},
+		[]string{"rgd", "trigger"},
+	)
+	// appliesTotal counts the resources applied with server-side apply, by
+	// ResourceGraphDefinition.
+	appliesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "instance_resource_applies_total",
+			Help: "Total number of instance resources applied per ResourceGraphDefinition",
+		},
+		[]string{"rgd"},
+	)
+	// noopAppliesTotal counts the applies that left resources unchanged, by
+	// ResourceGraphDefinition.
+	noopAppliesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "instance_resource_noop_applies_total",
+			Help: "Total number of instance resource applies that changed nothing per ResourceGraphDefinition",
+		},
+		[]string{"rgd"},
+	)
+	// prunesTotal counts the resources pruned from instances, by
+	// ResourceGraphDefinition.
+	prunesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "instance_resource_prunes_total",
+			Help: "Total number of instance resources pruned per ResourceGraphDefinition",
+		},
+		[]string{"rgd"},
+	)
+)
@@ -0,0 +1,225 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+const (
+	recreateReasonChanged   = "DesiredStateChanged"
+	recreateReasonImmutable = "ImmutableFieldChanged"
+
+	// recreateInterval is the minimum time between two recreations of the same
+	// resource. It stops kro from deleting a resource over and over when its
+	// desired state keeps changing, or when it cannot be updated even after
+	// being recreated.
+	recreateInterval = time.Minute
+)
+
+// immutableErrorMessages are the messages the API server uses to reject
+// updates changing immutable fields.
+var immutableErrorMessages = []string{
+	"field is immutable",
+	"may not change once set",
+	"updates to statefulset spec for fields other than",
+}
+
+// recreationKey identifies a resource of an instance in the recreation rate
+// limiter.
+type recreationKey struct {
+	instance  types.UID
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+}
+
+// prepareRecreate handles the update policy of the node before the desired
+// object is applied. It returns true if the desired object must not be applied
+// yet, because the current object is being deleted to be recreated.
+//
+// With the Recreate policy, the hash of the desired object is recorded on the
+// resource, and the current object is deleted when the hash changes. It must
+// be called before the desired object is decorated or trimmed, so that the
+// hash only covers the template.
+func (c *Controller) prepareRecreate(
+	rcx *ReconcileContext,
+	node *runtime.Node,
+	st *ResourceState,
+	desired, current *unstructured.Unstructured,
+) (bool, error) {
+	policy := node.Spec.UpdatePolicy
+	if !policy.Recreates() {
+		return false, nil
+	}
+	if current != nil && current.GetDeletionTimestamp() != nil {
+		st.State = ResourceStateDeleting
+		return true, nil
+	}
+	if policy != graph.UpdatePolicyRecreate {
+		return false, nil
+	}
+
+	hash, err := desiredHash(desired)
+	if err != nil {
+		return false, err
+	}
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[metadata.DesiredHashAnnotation] = hash
+	desired.SetAnnotations(annotations)
+
+	// Resources created before the policy was set have no hash to compare
+	// with; they are updated in place once.
+	if current == nil {
+		return false, nil
+	}
+	last, ok := current.GetAnnotations()[metadata.DesiredHashAnnotation]
+	if !ok || last == hash {
+		return false, nil
+	}
+	if err := c.recreate(rcx, node, current, recreateReasonChanged, "desired state changed"); err != nil {
+		st.State = ResourceStateError
+		st.Err = err
+		return false, err
+	}
+	st.State = ResourceStateDeleting
+	return true, nil
+}
+
+// recreateOnApplyError deletes the object the apply of which failed, if the
+// update policy of the node recreates objects on such errors. It returns true
+// if the object is being recreated, and the rate limiting error if it was
+// recreated too recently.
+func (c *Controller) recreateOnApplyError(
+	rcx *ReconcileContext,
+	node *runtime.Node,
+	obj *unstructured.Unstructured,
+	applyErr error,
+) (bool, error) {
+	if !node.Spec.UpdatePolicy.Recreates() || obj == nil || !isImmutableFieldError(applyErr) {
+		return false, nil
+	}
+	if err := c.recreate(rcx, node, obj, recreateReasonImmutable, "immutable field changed"); err != nil {
+		return false, fmt.Errorf("%w: %w", applyErr, err)
+	}
+	return true, nil
+}
+
+// recreate deletes obj so that it is created again by a later reconciliation,
+// and reports the recreation in an event and in the Recreated condition of
+// the instance. Dependents are deleted first, so that the new object does not
+// adopt the ones of the old object.
+func (c *Controller) recreate(
+	rcx *ReconcileContext,
+	node *runtime.Node,
+	obj *unstructured.Unstructured,
+	reason, why string,
+) error {
+	gvr := node.Spec.Meta.GVR
+	key := recreationKey{
+		instance:  rcx.Instance.GetUID(),
+		gvr:       gvr,
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+	if last, ok := c.recreations.Load(key); ok {
+		if since := time.Since(last.(time.Time)); since < recreateInterval {
+			return fmt.Errorf("%s %s was recreated %s ago, waiting %s before recreating it again",
+				gvr.Resource, obj.GetName(), since.Round(time.Second), (recreateInterval - since).Round(time.Second))
+		}
+	}
+
+	ri := rcx.Client.Resource(gvr).Namespace(obj.GetNamespace())
+	propagation := metav1.DeletePropagationForeground
+	err := ri.Delete(rcx.Ctx, obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s %s to recreate it: %w", gvr.Resource, obj.GetName(), err)
+	}
+	c.recreations.Store(key, time.Now())
+
+	recreationsTotal.WithLabelValues(rcx.GVR.String(), reason).Inc()
+	rcx.Log.Info("recreating resource", "id", node.Spec.Meta.ID, "name", obj.GetName(), "reason", why)
+	rcx.Mark.Recreated(reason, "recreating %s %s of resource %s: %s", gvr.Resource, obj.GetName(), node.Spec.Meta.ID, why)
+	if c.recorder != nil {
+		c.recorder.Eventf(rcx.Instance, obj, corev1.EventTypeNormal, reason, "Recreate",
+			"Recreating %s %s of resource %s: %s", gvr.Resource, obj.GetName(), node.Spec.Meta.ID, why)
+	}
+	return nil
+}
+
+// forgetRecreations drops the recreation times recorded for the instance.
+func (c *Controller) forgetRecreations(instance types.UID) {
+	c.recreations.Range(func(key, _ any) bool {
+		if key.(recreationKey).instance == instance {
+			c.recreations.Delete(key)
+		}
+		return true
+	})
+}
+
+// recreatingResource returns the ID of a resource waiting for its previous
+// object to be deleted, or an empty string if there is none.
+func recreatingResource(rcx *ReconcileContext) string {
+	for id, st := range rcx.StateManager.ResourceStates {
+		if st.State == ResourceStateDeleting {
+			return id
+		}
+	}
+	return ""
+}
+
+// isImmutableFieldError returns true if err is the API server rejecting an
+// update because it changes an immutable field.
+func isImmutableFieldError(err error) bool {
+	if !apierrors.IsInvalid(err) {
+		return false
+	}
+	msg := err.Error()
+	for _, m := range immutableErrorMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// desiredHash returns the hash of the desired object.
+func desiredHash(obj *unstructured.Unstructured) (string, error) {
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal desired object: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
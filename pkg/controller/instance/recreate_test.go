@@ -0,0 +1,265 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+var jobGVR = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+func newRecreateJob(image string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   map[string]any{"name": "migrate", "namespace": "default"},
+		"spec": map[string]any{
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{map[string]any{"name": "migrate", "image": image}},
+				},
+			},
+		},
+	}}
+}
+
+func newRecreateContext(objs ...k8sruntime.Object) *ReconcileContext {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		k8sruntime.NewScheme(),
+		map[schema.GroupVersionResource]string{jobGVR: "JobList"},
+		objs...,
+	)
+	inst := &unstructured.Unstructured{}
+	inst.SetAPIVersion("kro.run/v1alpha1")
+	inst.SetKind("Migration")
+	inst.SetNamespace("default")
+	inst.SetName("migration")
+	inst.SetUID("1234")
+	return &ReconcileContext{
+		Ctx:      context.Background(),
+		Log:      logr.Discard(),
+		Client:   client,
+		Instance: inst,
+		Mark:     NewConditionsMarkerFor(inst),
+	}
+}
+
+func newRecreateNode(policy graph.UpdatePolicy) *runtime.Node {
+	return &runtime.Node{Spec: &graph.Node{
+		Meta:         graph.NodeMeta{ID: "job", GVR: jobGVR, Namespaced: true},
+		UpdatePolicy: policy,
+	}}
+}
+
+func TestPrepareRecreate(t *testing.T) {
+	desired := newRecreateJob("migrate:v1")
+	hash, err := desiredHash(desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	withHash := func(obj *unstructured.Unstructured, hash string) *unstructured.Unstructured {
+		obj.SetAnnotations(map[string]string{metadata.DesiredHashAnnotation: hash})
+		return obj
+	}
+	deleting := withHash(newRecreateJob("migrate:v0"), "old")
+	now := metav1.Now()
+	deleting.SetDeletionTimestamp(&now)
+
+	tests := []struct {
+		name          string
+		policy        graph.UpdatePolicy
+		current       *unstructured.Unstructured
+		wantRecreated bool
+		wantWaiting   bool
+		wantHash      bool
+	}{
+		{
+			name:    "update policy",
+			policy:  graph.UpdatePolicyUpdate,
+			current: withHash(newRecreateJob("migrate:v0"), "old"),
+		},
+		{
+			name:     "not created yet",
+			policy:   graph.UpdatePolicyRecreate,
+			wantHash: true,
+		},
+		{
+			name:     "created before the policy was set",
+			policy:   graph.UpdatePolicyRecreate,
+			current:  newRecreateJob("migrate:v0"),
+			wantHash: true,
+		},
+		{
+			name:     "unchanged",
+			policy:   graph.UpdatePolicyRecreate,
+			current:  withHash(newRecreateJob("migrate:v1"), hash),
+			wantHash: true,
+		},
+		{
+			name:          "changed",
+			policy:        graph.UpdatePolicyRecreate,
+			current:       withHash(newRecreateJob("migrate:v0"), "old"),
+			wantRecreated: true,
+			wantWaiting:   true,
+			wantHash:      true,
+		},
+		{
+			name:    "changed with the immutable error policy",
+			policy:  graph.UpdatePolicyRecreateOnImmutableError,
+			current: withHash(newRecreateJob("migrate:v0"), "old"),
+		},
+		{
+			name:        "being deleted",
+			policy:      graph.UpdatePolicyRecreateOnImmutableError,
+			current:     deleting,
+			wantWaiting: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []k8sruntime.Object
+			if tt.current != nil {
+				objs = append(objs, tt.current)
+			}
+			rcx := newRecreateContext(objs...)
+			recorder := events.NewFakeRecorder(1)
+			c := &Controller{recorder: recorder}
+			st := &ResourceState{State: ResourceStateInProgress}
+			desired := newRecreateJob("migrate:v1")
+
+			waiting, err := c.prepareRecreate(rcx, newRecreateNode(tt.policy), st, desired, tt.current)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if waiting != tt.wantWaiting {
+				t.Errorf("expected waiting=%v, got %v", tt.wantWaiting, waiting)
+			}
+			if tt.wantWaiting && st.State != ResourceStateDeleting {
+				t.Errorf("expected state %s, got %s", ResourceStateDeleting, st.State)
+			}
+			if got := desired.GetAnnotations()[metadata.DesiredHashAnnotation]; (got == hash) != tt.wantHash {
+				t.Errorf("expected desired hash annotation=%v, got %q", tt.wantHash, got)
+			}
+
+			_, err = rcx.Client.Resource(jobGVR).Namespace("default").Get(rcx.Ctx, "migrate", metav1.GetOptions{})
+			if deleted := tt.current != nil && apierrors.IsNotFound(err); deleted != tt.wantRecreated {
+				t.Errorf("expected deleted=%v, got %v", tt.wantRecreated, deleted)
+			}
+			if got := len(recorder.Events) == 1; got != tt.wantRecreated {
+				t.Errorf("expected event=%v, got %v", tt.wantRecreated, got)
+			}
+			cond := rcx.Mark.cs.Get(Recreated)
+			if got := cond != nil; got != tt.wantRecreated {
+				t.Errorf("expected Recreated condition=%v, got %v", tt.wantRecreated, got)
+			}
+		})
+	}
+}
+
+func TestRecreateRateLimit(t *testing.T) {
+	current := newRecreateJob("migrate:v0")
+	rcx := newRecreateContext(current)
+	c := &Controller{}
+	node := newRecreateNode(graph.UpdatePolicyRecreate)
+
+	if err := c.recreate(rcx, node, current, recreateReasonChanged, "desired state changed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := c.recreate(rcx, node, current, recreateReasonChanged, "desired state changed")
+	if err == nil || !strings.Contains(err.Error(), "waiting") {
+		t.Fatalf("expected the second recreation to be rate limited, got %v", err)
+	}
+
+	c.forgetRecreations(rcx.Instance.GetUID())
+	if err := c.recreate(rcx, node, current, recreateReasonChanged, "desired state changed"); err != nil {
+		t.Fatalf("expected recreation to be allowed once forgotten, got %v", err)
+	}
+}
+
+func TestReconcileResourcesClearsRecreated(t *testing.T) {
+	rcx := newCompletionContext(t, nil, "", "")
+	rcx.Client.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "*",
+		func(k8stesting.Action) (bool, k8sruntime.Object, error) {
+			return true, rcx.Instance, nil
+		})
+	rcx.Mark.Recreated(recreateReasonChanged, "recreating jobs migrate of resource job: desired state changed")
+
+	if err := (&Controller{}).reconcileResources(rcx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cond := rcx.Mark.cs.Get(Recreated); cond != nil {
+		t.Errorf("expected the Recreated condition to be cleared once the resources settled, got %v", cond)
+	}
+}
+
+func TestIsImmutableFieldError(t *testing.T) {
+	invalid := func(errs ...*field.Error) error {
+		return apierrors.NewInvalid(schema.GroupKind{Group: "batch", Kind: "Job"}, "migrate", field.ErrorList(errs))
+	}
+	path := field.NewPath("spec", "template")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "immutable field",
+			err:  fmt.Errorf("apply failed: %w", invalid(field.Invalid(path, "", "field is immutable"))),
+			want: true,
+		},
+		{
+			name: "statefulset spec",
+			err: invalid(field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other "+
+				"than 'replicas', 'ordinals', 'template', 'updateStrategy' are forbidden")),
+			want: true,
+		},
+		{
+			name: "invalid value",
+			err:  invalid(field.Invalid(path, "", "must be a valid pod template")),
+		},
+		{
+			name: "not an invalid error",
+			err:  apierrors.NewBadRequest("field is immutable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isImmutableFieldError(tt.err); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
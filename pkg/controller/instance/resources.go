@@ -16,18 +16,23 @@ package instance
 
 import (
 	"fmt"
+	"slices"
 
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 
 	"github.com/kubernetes-sigs/kro/pkg/controller/instance/applyset"
 	"github.com/kubernetes-sigs/kro/pkg/graph"
 	"github.com/kubernetes-sigs/kro/pkg/metadata"
 	"github.com/kubernetes-sigs/kro/pkg/runtime"
+	"github.com/kubernetes-sigs/kro/pkg/tracing"
 )
 
 func (c *Controller) reconcileResources(rcx *ReconcileContext) error {
@@ -42,7 +47,9 @@ func (c *Controller) reconcileResources(rcx *ReconcileContext) error {
 	if err != nil {
 		return err
 	}
-	prune := lastUnresolved == ""
+	// Resources being recreated are left out of the apply until their
+	// previous object is gone, so pruning is unsafe as well.
+	prune := lastUnresolved == "" && recreatingResource(rcx) == ""
 
 	// ---------------------------------------------------------
 	// 2. Project applyset metadata and patch parent
@@ -63,6 +70,7 @@ func (c *Controller) reconcileResources(rcx *ReconcileContext) error {
 	if err != nil {
 		return rcx.delayedRequeue(fmt.Errorf("apply failed: %w", err))
 	}
+	c.recordApplies(result)
 
 	// clusterMutated tracks any cluster-side change from apply and/or prune.
 	// NOTE: it must start from apply results and only ever be OR-ed with
@@ -101,14 +109,27 @@ func (c *Controller) reconcileResources(rcx *ReconcileContext) error {
 	// This ensures StateManager.State reflects current resource states
 	// (including WaitingForReadiness) before the controller checks it.
 	rcx.StateManager.Update()
+	c.markDrift(rcx)
+
+	// Record what was applied once the whole graph resolved.
+	if prune && result.Errors() == nil {
+		if err := c.recordHistory(rcx, resources); err != nil {
+			rcx.Log.Error(err, "failed to record instance history")
+		}
+	}
 
 	if lastUnresolved != "" {
 		return rcx.delayedRequeue(fmt.Errorf("waiting for unresolved resource %q", lastUnresolved))
 	}
+	if id := recreatingResource(rcx); id != "" {
+		return rcx.delayedRequeue(fmt.Errorf("waiting for resource %q to be deleted before recreating it", id))
+	}
 	if clusterMutated {
 		return rcx.delayedRequeue(fmt.Errorf("cluster mutated"))
 	}
 
+	// The resources settled without being recreated.
+	rcx.Mark.NotRecreated()
 	return nil
 }
 
@@ -149,6 +170,7 @@ func (c *Controller) pruneOrphans(
 	if err != nil {
 		return false, rcx.delayedRequeue(fmt.Errorf("prune failed: %w", err))
 	}
+	prunesTotal.WithLabelValues(c.rgdName).Add(float64(len(pruneResult.Pruned)))
 
 	// Prune succeeded (errors return directly), safe to shrink metadata
 	if err := c.patchInstanceWithApplySetMetadata(rcx, batchMeta); err != nil {
@@ -170,8 +192,10 @@ func (c *Controller) createApplySet(rcx *ReconcileContext) *applyset.ApplySet {
 func (c *Controller) prepareResource(
 	rcx *ReconcileContext,
 	node *runtime.Node,
-) ([]applyset.Resource, string, error) {
+) (resources []applyset.Resource, unresolved string, err error) {
 	id := node.Spec.Meta.ID
+	_, span := tracing.Start(rcx.Ctx, "prepareResource", attribute.String("id", id))
+	defer func() { tracing.End(span, err) }()
 	rcx.Log.V(3).Info("Preparing resource", "id", id)
 
 	st := &ResourceState{State: ResourceStateInProgress}
@@ -191,6 +215,11 @@ func (c *Controller) prepareResource(
 			SkipApply: true,
 		}}, "", nil
 	}
+	// Pre-delete hooks only run when the instance is deleted.
+	if node.Spec.IsHook(graph.HookPhasePreDelete) {
+		st.State = ResourceStateSkipped
+		return nil, "", nil
+	}
 
 	desired, err := node.GetDesired()
 	if err != nil {
@@ -211,12 +240,29 @@ func (c *Controller) prepareResource(
 		return nil, "", nil
 	}
 
+	if !node.Spec.Meta.Type.IsExternal() {
+		if err := c.checkWatchedNamespaces(rcx, node, desired); err != nil {
+			st.State = ResourceStateError
+			st.Err = err
+			return nil, "", err
+		}
+	}
+
+	if node.Spec.Hook != nil {
+		return nil, "", c.reconcileHook(rcx, node, st, desired[0])
+	}
+
 	switch node.Spec.Meta.Type {
 	case graph.NodeTypeExternal:
 		if err := c.handleExternalRef(rcx, node, st, desired); err != nil {
 			return nil, "", err
 		}
 		return nil, "", nil
+	case graph.NodeTypeExternalCollection:
+		if err := c.handleExternalCollection(rcx, node, st, desired); err != nil {
+			return nil, "", err
+		}
+		return nil, "", nil
 	case graph.NodeTypeCollection:
 		resources, err := c.prepareCollectionResource(rcx, node, st, desired)
 		if err != nil {
@@ -266,8 +312,14 @@ func (c *Controller) prepareRegularResource(
 		node.SetObserved([]*unstructured.Unstructured{current})
 	}
 
+	if recreating, err := c.prepareRecreate(rcx, node, st, desired, current); err != nil || recreating {
+		return nil, err
+	}
+
 	// Apply decorator labels to desired object
 	c.applyDecoratorLabels(rcx, desired, id, nil)
+	c.releaseIgnoredFields(rcx, node, desired, current)
+	c.handleDrift(rcx, node, st, desired, current)
 
 	resource := applyset.Resource{
 		ID:      id,
@@ -286,7 +338,6 @@ func (c *Controller) prepareCollectionResource(
 ) ([]applyset.Resource, error) {
 	id := node.Spec.Meta.ID
 	desc := node.Spec.Meta
-	gvr := desc.GVR
 
 	collectionSize := len(expandedResources)
 
@@ -297,7 +348,7 @@ func (c *Controller) prepareCollectionResource(
 	}
 
 	// LIST all existing collection items with single call (more efficient than N GETs)
-	existingItems, err := c.listCollectionItems(rcx, gvr, id)
+	existingItems, err := c.listCollectionItems(rcx, desc)
 	if err != nil {
 		st.State = ResourceStateError
 		st.Err = fmt.Errorf("failed to list collection items: %w", err)
@@ -318,14 +369,24 @@ func (c *Controller) prepareCollectionResource(
 	// Build resources list for apply
 	resources := make([]applyset.Resource, 0, collectionSize)
 	for i, expandedResource := range expandedResources {
-		// Apply decorator labels with collection info
-		collectionInfo := &CollectionInfo{Index: i, Size: collectionSize}
-		c.applyDecoratorLabels(rcx, expandedResource, id, collectionInfo)
-
 		// Look up current revision from LIST results
 		key := expandedResource.GetNamespace() + "/" + expandedResource.GetName()
 		current := existingByKey[key]
 
+		recreating, err := c.prepareRecreate(rcx, node, st, expandedResource, current)
+		if err != nil {
+			return nil, err
+		}
+		if recreating {
+			continue
+		}
+
+		// Apply decorator labels with collection info
+		collectionInfo := &CollectionInfo{Index: i, Size: collectionSize}
+		c.applyDecoratorLabels(rcx, expandedResource, id, collectionInfo)
+		c.releaseIgnoredFields(rcx, node, expandedResource, current)
+		c.handleDrift(rcx, node, st, expandedResource, current)
+
 		expandedID := fmt.Sprintf("%s-%d", id, i)
 		resources = append(resources, applyset.Resource{
 			ID:      expandedID,
@@ -341,31 +402,67 @@ func (c *Controller) prepareCollectionResource(
 // Uses a single LIST with label selector instead of N individual GETs.
 func (c *Controller) listCollectionItems(
 	rcx *ReconcileContext,
-	gvr schema.GroupVersionResource,
-	nodeID string,
+	desc graph.NodeMeta,
 ) ([]*unstructured.Unstructured, error) {
 	// Filter by both instance UID and node ID for precise matching
 	instanceUID := string(rcx.Instance.GetUID())
-	selector := fmt.Sprintf("%s=%s,%s=%s",
-		metadata.InstanceIDLabel, instanceUID,
-		metadata.NodeIDLabel, nodeID,
-	)
+	opts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s",
+			metadata.InstanceIDLabel, instanceUID,
+			metadata.NodeIDLabel, desc.ID,
+		),
+	}
 
-	// List across all namespaces - collection items may span namespaces
-	list, err := rcx.Client.Resource(gvr).List(rcx.Ctx, metav1.ListOptions{
-		LabelSelector: selector,
-	})
-	if err != nil {
-		return nil, err
+	// List across all namespaces - collection items may span namespaces.
+	// When kro only watches some namespaces, it may not be allowed to list
+	// across all of them, and items can only live in watched namespaces.
+	namespaces := []string{metav1.NamespaceAll}
+	if watched := c.watchedNamespaces(); desc.Namespaced && watched != nil {
+		namespaces = watched
 	}
 
-	items := make([]*unstructured.Unstructured, len(list.Items))
-	for i := range list.Items {
-		items[i] = &list.Items[i]
+	var items []*unstructured.Unstructured
+	for _, ns := range namespaces {
+		list, err := resourceClientFor(rcx, desc, ns).List(rcx.Ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			items = append(items, &list.Items[i])
+		}
 	}
 	return items, nil
 }
 
+// watchedNamespaces returns the namespaces watched by kro, or nil if it
+// watches every namespace.
+func (c *Controller) watchedNamespaces() []string {
+	if c.namespaces == nil {
+		return nil
+	}
+	return c.namespaces.WatchedNamespaces()
+}
+
+// checkWatchedNamespaces returns an error if a namespaced resource of the node
+// lives in a namespace kro does not watch: kro would not see its changes, and
+// may not be allowed to manage it.
+func (c *Controller) checkWatchedNamespaces(
+	rcx *ReconcileContext,
+	node *runtime.Node,
+	desired []*unstructured.Unstructured,
+) error {
+	watched := c.watchedNamespaces()
+	if watched == nil || !node.Spec.Meta.Namespaced {
+		return nil
+	}
+	for _, obj := range desired {
+		if ns := rcx.getResourceNamespace(obj); !slices.Contains(watched, ns) {
+			return fmt.Errorf("resource %s: namespace %q is not watched by kro", node.Spec.Meta.ID, ns)
+		}
+	}
+	return nil
+}
+
 // CollectionInfo holds collection item metadata for decorator.
 type CollectionInfo struct {
 	Index int
@@ -415,10 +512,7 @@ func (c *Controller) patchInstanceWithApplySetMetadata(rcx *ReconcileContext, me
 		Object: map[string]interface{}{
 			"apiVersion": inst.GetAPIVersion(),
 			"kind":       inst.GetKind(),
-			"metadata": map[string]interface{}{
-				"name":      inst.GetName(),
-				"namespace": inst.GetNamespace(),
-			},
+			"metadata":   instanceIdentityMetadata(inst),
 		},
 	}
 	patchObj.SetLabels(meta.Labels())
@@ -452,6 +546,14 @@ func (c *Controller) handleExternalRef(
 	// External refs are read-only here: fetch and push into runtime for dependency/readiness.
 	actual, err := c.readExternalRef(rcx, id, desired)
 	if err != nil {
+		if errors.IsNotFound(err) && node.Spec.Meta.Optional {
+			// Optional refs resolve to their default instead of blocking dependents.
+			rcx.Log.V(1).Info("Optional external reference not found, using default", "resourceID", id)
+			node.SetMissing()
+			st.State = ResourceStateSynced
+			st.Err = nil
+			return nil
+		}
 		if errors.IsNotFound(err) {
 			st.State = ResourceStateWaitingForReadiness
 			st.Err = fmt.Errorf("waiting for external reference %q: %w", id, err)
@@ -526,6 +628,89 @@ func (c *Controller) readExternalRef(
 	return obj, nil
 }
 
+func (c *Controller) handleExternalCollection(
+	rcx *ReconcileContext,
+	node *runtime.Node,
+	st *ResourceState,
+	desiredList []*unstructured.Unstructured,
+) error {
+	if len(desiredList) == 0 {
+		st.State = ResourceStateSkipped
+		return nil
+	}
+
+	// External collections are read-only: list the selected objects and push
+	// them into runtime. An empty list is a valid result.
+	items, err := c.listExternalRef(rcx, node, desiredList[0])
+	if err != nil {
+		st.State = ResourceStateError
+		st.Err = err
+		return err
+	}
+
+	node.SetObserved(items)
+	setStateFromReadiness(node, st)
+	return nil
+}
+
+// listExternalRef lists the objects selected by an external collection, and
+// records the selector so that changes to the selected set trigger a new
+// reconciliation of the instance.
+func (c *Controller) listExternalRef(
+	rcx *ReconcileContext,
+	node *runtime.Node,
+	desired *unstructured.Unstructured,
+) ([]*unstructured.Unstructured, error) {
+	id := node.Spec.Meta.ID
+	gvr := node.Spec.Meta.GVR
+
+	rawSelector, _, err := unstructured.NestedMap(desired.Object, graph.ExternalSelectorPath)
+	if err != nil {
+		return nil, fmt.Errorf("externalRef %s: invalid selector: %w", id, err)
+	}
+	labelSelector := &metav1.LabelSelector{}
+	if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(rawSelector, labelSelector); err != nil {
+		return nil, fmt.Errorf("externalRef %s: invalid selector: %w", id, err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("externalRef %s: invalid selector: %w", id, err)
+	}
+
+	var namespace string
+	ri := c.client.Dynamic().Resource(gvr).(dynamic.ResourceInterface)
+	if node.Spec.Meta.Namespaced {
+		namespace = rcx.getResourceNamespace(desired)
+		ri = c.client.Dynamic().Resource(gvr).Namespace(namespace)
+	}
+
+	// Record the selector before listing, so that no change is missed.
+	if c.externalWatcher != nil {
+		instanceKey := types.NamespacedName{Namespace: rcx.Instance.GetNamespace(), Name: rcx.Instance.GetName()}
+		c.externalWatcher.WatchExternalSelector(c.gvr, instanceKey, id, gvr, namespace, selector)
+	}
+
+	list, err := ri.List(rcx.Ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("externalRef: LIST %s in %q with selector %q: %w", gvr, namespace, selector, err)
+	}
+
+	// Never nil: an empty list means nothing matched, not that we didn't read.
+	items := make([]*unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		items = append(items, &list.Items[i])
+	}
+
+	rcx.Log.V(1).Info("External collection resolved",
+		"resourceID", id,
+		"gvr", gvr.String(),
+		"namespace", namespace,
+		"selector", selector.String(),
+		"count", len(items),
+	)
+	return items, nil
+}
+
 func (c *Controller) processApplyResults(
 	rcx *ReconcileContext,
 	result *applyset.ApplyResult,
@@ -551,7 +736,8 @@ func (c *Controller) processApplyResults(
 
 		if resourceState.State == ResourceStateError ||
 			resourceState.State == ResourceStateSkipped ||
-			resourceState.State == ResourceStateWaitingForReadiness {
+			resourceState.State == ResourceStateWaitingForReadiness ||
+			resourceState.State == ResourceStateDeleting {
 			continue
 		}
 
@@ -560,15 +746,23 @@ func (c *Controller) processApplyResults(
 			if err := c.updateCollectionFromApplyResults(rcx, node, resourceState, byID); err != nil {
 				return err
 			}
-		case graph.NodeTypeExternal:
+		case graph.NodeTypeExternal, graph.NodeTypeExternalCollection:
 			// External refs handled before applyset.
 			continue
 		case graph.NodeTypeResource:
 			if item, ok := byID[resourceID]; ok {
 				if item.Error != nil {
+					recreating, err := c.recreateOnApplyError(rcx, node, item.Desired, item.Error)
+					if recreating {
+						resourceState.State = ResourceStateDeleting
+						continue
+					}
+					if err == nil {
+						err = item.Error
+					}
 					resourceState.State = ResourceStateError
-					resourceState.Err = item.Error
-					rcx.Log.V(1).Info("apply error", "id", resourceID, "error", item.Error)
+					resourceState.Err = err
+					rcx.Log.V(1).Info("apply error", "id", resourceID, "error", err)
 					continue
 				}
 				if item.Observed != nil {
@@ -590,7 +784,7 @@ func (c *Controller) processApplyResults(
 }
 
 func (c *Controller) updateCollectionFromApplyResults(
-	_ *ReconcileContext,
+	rcx *ReconcileContext,
 	node *runtime.Node,
 	resourceState *ResourceState,
 	byID map[string]applyset.ApplyResultItem,
@@ -623,8 +817,16 @@ func (c *Controller) updateCollectionFromApplyResults(
 		expandedID := fmt.Sprintf("%s-%d", resourceID, i)
 		if item, ok := byID[expandedID]; ok {
 			if item.Error != nil {
+				recreating, err := c.recreateOnApplyError(rcx, node, item.Desired, item.Error)
+				if recreating {
+					resourceState.State = ResourceStateDeleting
+					return nil
+				}
+				if err == nil {
+					err = item.Error
+				}
 				resourceState.State = ResourceStateError
-				resourceState.Err = fmt.Errorf("collection item %d: %w", i, item.Error)
+				resourceState.Err = fmt.Errorf("collection item %d: %w", i, err)
 				return nil
 			}
 			if item.Observed != nil {
@@ -0,0 +1,150 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+	"github.com/kubernetes-sigs/kro/pkg/runtime"
+)
+
+type fakeNamespaceScope []string
+
+func (f fakeNamespaceScope) WatchedNamespaces() []string {
+	return f
+}
+
+func newCollectionJob(namespace, name, nodeID string) *unstructured.Unstructured {
+	job := newRecreateJob("migrate:v1")
+	job.SetNamespace(namespace)
+	job.SetName(name)
+	job.SetLabels(map[string]string{
+		metadata.InstanceIDLabel: "1234",
+		metadata.NodeIDLabel:     nodeID,
+	})
+	return job
+}
+
+func TestListCollectionItems(t *testing.T) {
+	objs := []k8sruntime.Object{
+		newCollectionJob("default", "a", "jobs"),
+		newCollectionJob("team-a", "b", "jobs"),
+		newCollectionJob("team-b", "c", "jobs"),
+		newCollectionJob("default", "d", "other"),
+	}
+	desc := graph.NodeMeta{ID: "jobs", GVR: jobGVR, Namespaced: true}
+
+	tests := []struct {
+		name       string
+		namespaces NamespaceScope
+		want       []string
+	}{
+		{
+			name: "every namespace",
+			want: []string{"default/a", "team-a/b", "team-b/c"},
+		},
+		{
+			name:       "watched namespaces",
+			namespaces: fakeNamespaceScope{"default", "team-a"},
+			want:       []string{"default/a", "team-a/b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcx := newRecreateContext(objs...)
+			c := &Controller{namespaces: tt.namespaces}
+
+			items, err := c.listCollectionItems(rcx, desc)
+			require.NoError(t, err)
+			got := sets.New[string]()
+			for _, item := range items {
+				got.Insert(item.GetNamespace() + "/" + item.GetName())
+			}
+			assert.Equal(t, sets.New(tt.want...), got)
+		})
+	}
+}
+
+func TestCheckWatchedNamespaces(t *testing.T) {
+	inNamespace := func(namespace string) *unstructured.Unstructured {
+		job := newRecreateJob("migrate:v1")
+		job.SetNamespace(namespace)
+		return job
+	}
+
+	tests := []struct {
+		name       string
+		namespaces NamespaceScope
+		namespaced bool
+		desired    []*unstructured.Unstructured
+		wantErr    bool
+	}{
+		{
+			name:       "every namespace",
+			namespaced: true,
+			desired:    []*unstructured.Unstructured{inNamespace("team-b")},
+		},
+		{
+			name:       "watched namespace",
+			namespaces: fakeNamespaceScope{"default"},
+			namespaced: true,
+			desired:    []*unstructured.Unstructured{inNamespace("default")},
+		},
+		{
+			name:       "defaults to the instance namespace",
+			namespaces: fakeNamespaceScope{"default"},
+			namespaced: true,
+			desired:    []*unstructured.Unstructured{inNamespace("")},
+		},
+		{
+			name:       "unwatched namespace",
+			namespaces: fakeNamespaceScope{"default"},
+			namespaced: true,
+			desired:    []*unstructured.Unstructured{inNamespace("default"), inNamespace("team-b")},
+			wantErr:    true,
+		},
+		{
+			name:       "cluster scoped resource",
+			namespaces: fakeNamespaceScope{"default"},
+			desired:    []*unstructured.Unstructured{inNamespace("")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcx := newRecreateContext()
+			c := &Controller{namespaces: tt.namespaces}
+			node := &runtime.Node{Spec: &graph.Node{
+				Meta: graph.NodeMeta{ID: "job", GVR: jobGVR, Namespaced: tt.namespaced},
+			}}
+
+			err := c.checkWatchedNamespaces(rcx, node, tt.desired)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, `namespace "team-b" is not watched by kro`)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
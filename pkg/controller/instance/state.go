@@ -22,11 +22,18 @@ const (
 	InstanceStateActive     = "ACTIVE"
 	InstanceStateDeleting   = "DELETING"
 	InstanceStateError      = "ERROR"
+	// InstanceStateSucceeded is the terminal state of one-shot instances the
+	// success expressions of which were met. Failed one-shot instances end in
+	// InstanceStateFailed.
+	InstanceStateSucceeded = "SUCCEEDED"
 )
 
 type ResourceState struct {
 	State string
 	Err   error
+	// Drift lists the fields of the resource that changed outside of kro and
+	// were left untouched.
+	Drift []string
 }
 
 // StateManager tracks instance and resource states during reconciliation.
@@ -17,6 +17,9 @@ package instance
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -24,7 +27,9 @@ import (
 
 	"github.com/kubernetes-sigs/kro/api/v1alpha1"
 	"github.com/kubernetes-sigs/kro/pkg/apis"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
 	"github.com/kubernetes-sigs/kro/pkg/requeue"
+	"github.com/kubernetes-sigs/kro/pkg/tracing"
 )
 
 const (
@@ -32,13 +37,38 @@ const (
 	InstanceManaged = "InstanceManaged"
 	GraphResolved   = "GraphResolved"
 	ResourcesReady  = "ResourcesReady"
+	// Drifted is not a dependent condition: drift reported on resources does
+	// not make the instance unready.
+	Drifted = "Drifted"
+	// Recreated is not a dependent condition either. It reports the latest
+	// resource deleted and created again because of its update policy, until
+	// the resources are reconciled without being recreated.
+	Recreated = "Recreated"
+	// Completed reports whether one-shot instances succeeded or failed.
+	Completed = "Completed"
+	// InvalidExpiry is not a dependent condition. It reports expiry
+	// annotations that can't be parsed.
+	InvalidExpiry = "InvalidExpiry"
 )
 
+// reservedStatusFields are the status fields set by kro. Status fields of the
+// same name defined in the schema are ignored.
+var reservedStatusFields = []string{"conditions", "state", "graphRevision", "hooks", "completionTime", "expiresAt"}
+
 var condSet = apis.NewReadyConditions(InstanceManaged, GraphResolved, ResourcesReady)
 
+// completionCondSet holds the conditions of one-shot instances. Their root
+// condition is Succeeded, and their resources being ready does not matter
+// once the completion expressions are met.
+var completionCondSet = apis.NewSucceededConditions(InstanceManaged, GraphResolved, Completed)
+
 func NewConditionsMarkerFor(obj *unstructured.Unstructured) *ConditionsMarker {
+	return newConditionsMarker(obj, condSet)
+}
+
+func newConditionsMarker(obj *unstructured.Unstructured, ct apis.ConditionTypes) *ConditionsMarker {
 	return &ConditionsMarker{
-		cs: condSet.For(&unstructuredWrapper{obj}),
+		cs: ct.For(&unstructuredWrapper{obj}),
 	}
 }
 
@@ -114,12 +144,71 @@ func (m *ConditionsMarker) ResourcesNotReady(msg string, args ...any) {
 	m.cs.SetFalse(ResourcesReady, "NotReady", fmt.Sprintf(msg, args...))
 }
 
+// Drifted signals resources of the instance were changed outside of kro, and
+// the changes were left untouched. It returns true if the instance was not
+// drifted before.
+func (m *ConditionsMarker) Drifted(msg string, args ...any) bool {
+	drifted := m.cs.IsTrue(Drifted)
+	m.cs.SetTrueWithReason(Drifted, "DriftDetected", fmt.Sprintf(msg, args...))
+	return !drifted
+}
+
+// NotDrifted removes the Drifted condition.
+func (m *ConditionsMarker) NotDrifted() {
+	_ = m.cs.Clear(Drifted)
+}
+
+// Recreated signals a resource of the instance was deleted to be created
+// again.
+func (m *ConditionsMarker) Recreated(reason, msg string, args ...any) {
+	m.cs.SetTrueWithReason(Recreated, reason, fmt.Sprintf(msg, args...))
+}
+
+// NotRecreated removes the Recreated condition.
+func (m *ConditionsMarker) NotRecreated() {
+	_ = m.cs.Clear(Recreated)
+}
+
+// Running signals a one-shot instance has not finished yet.
+func (m *ConditionsMarker) Running() {
+	m.cs.SetUnknownWithReason(Completed, "Running", "completion expressions are not met yet")
+}
+
+// Succeeded signals the success expressions of a one-shot instance are met.
+func (m *ConditionsMarker) Succeeded() {
+	m.cs.SetTrueWithReason(Completed, "Succeeded", "success expressions are met")
+}
+
+// Failed signals a failure expression of a one-shot instance is met.
+func (m *ConditionsMarker) Failed(expr string) {
+	m.cs.SetFalse(Completed, "Failed", fmt.Sprintf("failure expression %q is met", expr))
+}
+
+// CompletionUnknown signals the completion expressions of a one-shot instance
+// could not be evaluated.
+func (m *ConditionsMarker) CompletionUnknown(msg string, args ...any) {
+	m.cs.SetUnknownWithReason(Completed, "EvaluationFailed", fmt.Sprintf(msg, args...))
+}
+
+// InvalidExpiry signals the expiry annotations of the instance are invalid,
+// and returns true if the condition changed.
+func (m *ConditionsMarker) InvalidExpiry(msg string, args ...any) bool {
+	return m.cs.SetTrueWithReason(InvalidExpiry, "InvalidAnnotation", fmt.Sprintf(msg, args...))
+}
+
+// ValidExpiry removes the InvalidExpiry condition.
+func (m *ConditionsMarker) ValidExpiry() {
+	_ = m.cs.Clear(InvalidExpiry)
+}
+
 // ResourcesUnderDeletion signals the controller is currently deleting resources.
 func (m *ConditionsMarker) ResourcesUnderDeletion(msg string, args ...any) {
 	m.cs.SetUnknownWithReason(ResourcesReady, "UnderDeletion", fmt.Sprintf(msg, args...))
 }
 
-func (c *Controller) updateStatus(rcx *ReconcileContext) error {
+func (c *Controller) updateStatus(rcx *ReconcileContext) (err error) {
+	_, span := tracing.Start(rcx.Ctx, "updateStatus")
+	defer func() { tracing.End(span, err) }()
 	rcx.updateInstanceState()
 	status := rcx.initialStatus()
 
@@ -130,7 +219,7 @@ func (c *Controller) updateStatus(rcx *ReconcileContext) error {
 	}
 	if resolved, found, _ := unstructured.NestedMap(desired[0].Object, "status"); found {
 		for k, v := range resolved {
-			if k == "conditions" || k == "state" {
+			if slices.Contains(reservedStatusFields, k) {
 				continue
 			}
 			status[k] = v
@@ -140,7 +229,7 @@ func (c *Controller) updateStatus(rcx *ReconcileContext) error {
 	inst := rcx.Instance.DeepCopy()
 	inst.Object["status"] = status
 
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		cur, err := c.client.Dynamic().
 			Resource(c.gvr).
 			Namespace(inst.GetNamespace()).
@@ -155,12 +244,53 @@ func (c *Controller) updateStatus(rcx *ReconcileContext) error {
 			UpdateStatus(rcx.Ctx, cur, metav1.UpdateOptions{})
 		return err
 	})
+	if err != nil {
+		return err
+	}
+	c.recordHealth(rcx, status["state"].(string))
+	return c.recordReadyRevision(rcx)
+}
+
+// recordReadyRevision labels the instance with the graph revision it is Ready
+// with, for the rollout of its ResourceGraphDefinition to follow from the
+// informer cache. The label is removed once the instance is not Ready, or no
+// rollout policy applies anymore.
+func (c *Controller) recordReadyRevision(rcx *ReconcileContext) error {
+	inst := rcx.Instance
+	if inst.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	want := ""
+	if c.rollout != nil && rcx.GraphRevision > 0 && rcx.Mark.cs.IsTrue(Ready) {
+		want = strconv.FormatInt(rcx.GraphRevision, 10)
+	}
+	if inst.GetLabels()[metadata.ReadyGraphRevisionLabel] == want {
+		return nil
+	}
+
+	patch := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": inst.GetAPIVersion(),
+		"kind":       inst.GetKind(),
+		"metadata":   instanceIdentityMetadata(inst),
+	}}
+	if want != "" {
+		patch.SetLabels(map[string]string{metadata.ReadyGraphRevisionLabel: want})
+	}
+	_, err := rcx.InstanceClient().Apply(rcx.Ctx, inst.GetName(), patch, metav1.ApplyOptions{
+		FieldManager: FieldManagerForRollout,
+		Force:        true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed applying ready graph revision label: %w", err)
+	}
+	return nil
 }
 
 func (rcx *ReconcileContext) initialStatus() map[string]interface{} {
 	inst := rcx.Instance
 
-	conds := condSet.For(&unstructuredWrapper{inst}).List()
+	cs := rcx.conditionTypes.For(&unstructuredWrapper{inst})
+	conds := cs.List()
 
 	b, err := json.Marshal(conds)
 	if err != nil {
@@ -177,11 +307,34 @@ func (rcx *ReconcileContext) initialStatus() map[string]interface{} {
 	status := map[string]interface{}{
 		"conditions": arr,
 	}
-	if condSet.For(&unstructuredWrapper{inst}).IsRootReady() {
+	completionTime, finished, _ := unstructured.NestedString(inst.Object, "status", "completionTime")
+	switch {
+	case finished && rcx.StateManager.State != InstanceStateDeleting:
+		status["state"] = InstanceStateFailed
+		if cs.IsTrue(Completed) {
+			status["state"] = InstanceStateSucceeded
+		}
+	case cs.IsRootReady():
 		status["state"] = InstanceStateActive
-	} else {
+	default:
 		status["state"] = rcx.StateManager.State
 	}
+	if finished {
+		status["completionTime"] = completionTime
+	}
+	if expiresAt, ok, _ := metadata.ExpiresAt(inst); ok {
+		status["expiresAt"] = expiresAt.UTC().Format(time.RFC3339)
+	}
+	if rcx.GraphRevision > 0 {
+		status["graphRevision"] = rcx.GraphRevision
+	}
+	if len(rcx.HookRuns) > 0 {
+		hooks := make(map[string]interface{}, len(rcx.HookRuns))
+		for id, run := range rcx.HookRuns {
+			hooks[id] = run
+		}
+		status["hooks"] = hooks
+	}
 	return status
 }
 
@@ -17,25 +17,34 @@ package resourcegraphdefinition
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlrtcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/kubernetes-sigs/kro/api/v1alpha1"
 	kroclient "github.com/kubernetes-sigs/kro/pkg/client"
+	"github.com/kubernetes-sigs/kro/pkg/conversion"
 	"github.com/kubernetes-sigs/kro/pkg/dynamiccontroller"
 	"github.com/kubernetes-sigs/kro/pkg/graph"
 	"github.com/kubernetes-sigs/kro/pkg/metadata"
+	"github.com/kubernetes-sigs/kro/pkg/tracing"
 )
 
 // ResourceGraphDefinitionReconciler reconciles a ResourceGraphDefinition object
@@ -47,6 +56,8 @@ type ResourceGraphDefinitionReconciler struct {
 	client.Client
 
 	instanceLogger logr.Logger
+	// eventRecorder records the events of instances.
+	eventRecorder events.EventRecorder
 
 	clientSet  kroclient.SetInterface
 	crdManager kroclient.CRDClient
@@ -55,6 +66,37 @@ type ResourceGraphDefinitionReconciler struct {
 	rgBuilder               *graph.Builder
 	dynamicController       *dynamiccontroller.DynamicController
 	maxConcurrentReconciles int
+
+	// conversionWebhook serves conversions for multi-version instance CRDs.
+	// If nil, ResourceGraphDefinitions declaring more than one version are
+	// rejected.
+	conversionWebhook *conversion.Webhook
+
+	// sharded is true when instances are sharded across replicas. The
+	// controller then runs on every replica to register the microcontrollers,
+	// but only the leader writes ResourceGraphDefinitions, CRDs and graph
+	// revisions.
+	sharded bool
+	// elected is closed once this replica is the leader. It is only set when
+	// sharded.
+	elected <-chan struct{}
+	// events enqueues ResourceGraphDefinitions regardless of their changes,
+	// see EnqueueAll.
+	events chan event.GenericEvent
+
+	// configSource holds the configuration overriding how the instances of
+	// each ResourceGraphDefinition are reconciled. If nil, the defaults apply.
+	configSource ConfigSource
+
+	// revisions caches the compiled graphs of the revisions of each
+	// ResourceGraphDefinition, keyed by ResourceGraphDefinition name.
+	revisionsMu sync.Mutex
+	revisions   map[string]*graphRevisions
+	// rollouts holds the rollout plan followed by the instance controller of
+	// each ResourceGraphDefinition declaring a rollout policy, keyed by
+	// ResourceGraphDefinition name.
+	rolloutsMu sync.Mutex
+	rollouts   map[string]*rolloutPlan
 }
 
 func NewResourceGraphDefinitionReconciler(
@@ -63,6 +105,9 @@ func NewResourceGraphDefinitionReconciler(
 	dynamicController *dynamiccontroller.DynamicController,
 	builder *graph.Builder,
 	maxConcurrentReconciles int,
+	conversionWebhook *conversion.Webhook,
+	sharded bool,
+	configSource ConfigSource,
 ) *ResourceGraphDefinitionReconciler {
 	crdWrapper := clientSet.CRD(kroclient.CRDWrapperConfig{})
 
@@ -74,6 +119,12 @@ func NewResourceGraphDefinitionReconciler(
 		metadataLabeler:         metadata.NewKROMetaLabeler(),
 		rgBuilder:               builder,
 		maxConcurrentReconciles: maxConcurrentReconciles,
+		conversionWebhook:       conversionWebhook,
+		sharded:                 sharded,
+		configSource:            configSource,
+		events:                  make(chan event.GenericEvent),
+		revisions:               make(map[string]*graphRevisions),
+		rollouts:                make(map[string]*rolloutPlan),
 	}
 }
 
@@ -82,6 +133,7 @@ func (r *ResourceGraphDefinitionReconciler) SetupWithManager(mgr ctrl.Manager) e
 	r.Client = mgr.GetClient()
 	r.clientSet.SetRESTMapper(mgr.GetRESTMapper())
 	r.instanceLogger = mgr.GetLogger()
+	r.eventRecorder = mgr.GetEventRecorder("kro")
 
 	logConstructor := func(req *reconcile.Request) logr.Logger {
 		log := mgr.GetLogger().WithName("rgd-controller").WithValues(
@@ -95,16 +147,27 @@ func (r *ResourceGraphDefinitionReconciler) SetupWithManager(mgr ctrl.Manager) e
 		return log
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	options := ctrlrtcontroller.Options{
+		LogConstructor:          logConstructor,
+		MaxConcurrentReconciles: r.maxConcurrentReconciles,
+	}
+	b := ctrl.NewControllerManagedBy(mgr)
+	if r.sharded {
+		// Every replica reconciles the instances it owns, so every replica
+		// needs the microcontrollers. Only the leader writes.
+		options.NeedLeaderElection = ptr.To(false)
+		r.elected = mgr.Elected()
+		if err := mgr.Add(manager.RunnableFunc(r.enqueueOnElection)); err != nil {
+			return fmt.Errorf("failed to add election runnable: %w", err)
+		}
+	}
+	b = b.WatchesRawSource(source.Channel(r.events, &handler.EnqueueRequestForObject{}))
+
+	return b.
 		Named("ResourceGraphDefinition").
 		For(&v1alpha1.ResourceGraphDefinition{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
-		WithOptions(
-			ctrlrtcontroller.Options{
-				LogConstructor:          logConstructor,
-				MaxConcurrentReconciles: r.maxConcurrentReconciles,
-			},
-		).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, allowBreakingChangesChanged)).
+		WithOptions(options).
 		WatchesMetadata(
 			&extv1.CustomResourceDefinition{},
 			handler.EnqueueRequestsFromMapFunc(r.findRGDsForCRD),
@@ -123,6 +186,58 @@ func (r *ResourceGraphDefinitionReconciler) SetupWithManager(mgr ctrl.Manager) e
 		Complete(reconcile.AsReconciler[*v1alpha1.ResourceGraphDefinition](mgr.GetClient(), r))
 }
 
+// allowBreakingChangesChanged admits the updates opting in or out of breaking
+// changes, which do not bump the generation but unblock the CRD update.
+var allowBreakingChangesChanged = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld == nil || e.ObjectNew == nil {
+			return false
+		}
+		return metadata.AllowsBreakingChanges(e.ObjectOld) != metadata.AllowsBreakingChanges(e.ObjectNew)
+	},
+}
+
+// isLeader returns true if this replica may write ResourceGraphDefinitions,
+// their CRDs and graph revisions. Without sharding, the controller only runs
+// on the leader.
+func (r *ResourceGraphDefinitionReconciler) isLeader() bool {
+	if r.elected == nil {
+		return true
+	}
+	select {
+	case <-r.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// enqueueOnElection enqueues every ResourceGraphDefinition once this replica is
+// elected, so that the new leader performs the writes it skipped as a follower.
+func (r *ResourceGraphDefinitionReconciler) enqueueOnElection(ctx context.Context) error {
+	return r.EnqueueAll(ctx)
+}
+
+// EnqueueAll enqueues every ResourceGraphDefinition, for instance to apply a
+// new configuration. It blocks until the controller started, or ctx is done.
+func (r *ResourceGraphDefinitionReconciler) EnqueueAll(ctx context.Context) error {
+	rgds := &v1alpha1.ResourceGraphDefinitionList{}
+	if err := r.List(ctx, rgds); err != nil {
+		return fmt.Errorf("failed to list resource graph definitions: %w", err)
+	}
+	for i := range rgds.Items {
+		select {
+		case r.events <- event.GenericEvent{Object: &rgds.Items[i]}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
 // findRGDsForCRD returns a list of reconcile requests for the ResourceGraphDefinition
 // that owns the given CRD. It is used to trigger reconciliation when a CRD is updated.
 func (r *ResourceGraphDefinitionReconciler) findRGDsForCRD(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -154,26 +269,56 @@ func (r *ResourceGraphDefinitionReconciler) findRGDsForCRD(ctx context.Context,
 func (r *ResourceGraphDefinitionReconciler) Reconcile(
 	ctx context.Context,
 	o *v1alpha1.ResourceGraphDefinition,
-) (ctrl.Result, error) {
+) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ResourceGraphDefinition.Reconcile", attribute.String("name", o.Name))
+	defer func() { tracing.End(span, err) }()
+	ctx = ctrl.LoggerInto(ctx, tracing.LoggerWithTrace(ctx, ctrl.LoggerFrom(ctx)))
+
+	leader := r.isLeader()
 	if !o.DeletionTimestamp.IsZero() {
 		if err := r.cleanupResourceGraphDefinition(ctx, o); err != nil {
 			return ctrl.Result{}, err
 		}
+		if !leader {
+			return ctrl.Result{}, nil
+		}
 		if err := r.setUnmanaged(ctx, o); err != nil {
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
 	}
 
-	if err := r.setManaged(ctx, o); err != nil {
-		return ctrl.Result{}, err
+	if leader {
+		if err := r.setManaged(ctx, o); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The graph, CRD and microcontroller of a generation being rolled out are
+	// already reconciled: periodic requeues only advance the rollout, without
+	// enqueueing every instance again.
+	if r.rolloutInProgress(o) {
+		gvr := metadata.GetResourceGraphDefinitionInstanceGVR(o.Spec.Schema.Group, o.Spec.Schema.APIVersion, o.Spec.Schema.Kind)
+		rolloutErr := r.progressRollout(ctx, o, gvr, r.rolloutPlanFor(o.Name))
+		if !leader {
+			return rolloutResult(o), rolloutErr
+		}
+		if err := r.updateStatus(ctx, o, o.Status.TopologicalOrder, o.Status.Resources); err != nil {
+			rolloutErr = errors.Join(rolloutErr, err)
+		}
+		return rolloutResult(o), rolloutErr
 	}
 
-	topologicalOrder, resourcesInformation, reconcileErr := r.reconcileResourceGraphDefinition(ctx, o)
+	topologicalOrder, resourcesInformation, reconcileErr := r.reconcileResourceGraphDefinition(ctx, o, leader)
+	if !leader {
+		// Followers only register the microcontroller; the leader reports
+		// the status.
+		return rolloutResult(o), reconcileErr
+	}
 
 	if err := r.updateStatus(ctx, o, topologicalOrder, resourcesInformation); err != nil {
 		reconcileErr = errors.Join(reconcileErr, err)
 	}
 
-	return ctrl.Result{}, reconcileErr
+	return rolloutResult(o), reconcileErr
 }
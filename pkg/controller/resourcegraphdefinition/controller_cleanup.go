@@ -24,13 +24,14 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	instancectrl "github.com/kubernetes-sigs/kro/pkg/controller/instance"
 	"github.com/kubernetes-sigs/kro/pkg/metadata"
 )
 
 // cleanupResourceGraphDefinition handles the deletion of a ResourceGraphDefinition by shutting down its associated
 // microcontroller and cleaning up the CRD if enabled. It executes cleanup operations in order:
 // 1. Shuts down the microcontroller
-// 2. Deletes the associated CRD (if CRD deletion is enabled)
+// 2. Deletes the associated CRD (if CRD deletion is enabled and this replica is the leader)
 func (r *ResourceGraphDefinitionReconciler) cleanupResourceGraphDefinition(ctx context.Context, rgd *v1alpha1.ResourceGraphDefinition) error {
 	ctrl.LoggerFrom(ctx).V(1).Info("cleaning up resource graph definition", "name", rgd.Name)
 
@@ -40,7 +41,14 @@ func (r *ResourceGraphDefinitionReconciler) cleanupResourceGraphDefinition(ctx c
 		return fmt.Errorf("failed to shutdown microcontroller: %w", err)
 	}
 
+	r.forgetGraphRevisions(rgd.Name)
+	r.forgetRolloutPlan(rgd.Name)
+	instancectrl.ForgetHealthMetrics(rgd.Name)
+
 	// cleanup CRD
+	if !r.isLeader() {
+		return nil
+	}
 	crdName := extractCRDName(rgd.Spec.Schema.Group, rgd.Spec.Schema.Kind)
 	if err := r.cleanupResourceGraphDefinitionCRD(ctx, crdName); err != nil {
 		return fmt.Errorf("failed to cleanup CRD %s: %w", crdName, err)
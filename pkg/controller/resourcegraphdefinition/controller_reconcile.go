@@ -16,18 +16,24 @@ package resourcegraphdefinition
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"slices"
 	"time"
 
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kubernetes-sigs/kro/api/v1alpha1"
 	instancectrl "github.com/kubernetes-sigs/kro/pkg/controller/instance"
+	"github.com/kubernetes-sigs/kro/pkg/dynamiccontroller"
 	"github.com/kubernetes-sigs/kro/pkg/graph"
+	kcrd "github.com/kubernetes-sigs/kro/pkg/graph/crd"
+	"github.com/kubernetes-sigs/kro/pkg/history"
 	"github.com/kubernetes-sigs/kro/pkg/metadata"
 )
 
@@ -35,9 +41,13 @@ import (
 // 1. Processing the resource graph
 // 2. Ensuring CRDs are present
 // 3. Setting up and starting the microcontroller
+//
+// Followers, when instances are sharded, do not write the CRD and the graph
+// revision: they wait for the leader to create them.
 func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinition(
 	ctx context.Context,
 	rgd *v1alpha1.ResourceGraphDefinition,
+	leader bool,
 ) ([]string, []v1alpha1.ResourceInformation, error) {
 	log := ctrl.LoggerFrom(ctx)
 	mark := NewConditionsMarkerFor(rgd)
@@ -61,14 +71,43 @@ func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinition(
 	crd := processedRGD.CRD
 	graphExecLabeler.ApplyLabels(&crd.ObjectMeta)
 
-	// Ensure CRD exists and is up to date
-	log.V(1).Info("reconciling resource graph definition CRD")
-	if err := r.reconcileResourceGraphDefinitionCRD(ctx, crd); err != nil {
+	// Multi-version CRDs are converted by kro's conversion webhook. Register the
+	// converter before touching the CRD so the API server can convert instances
+	// as soon as the new versions are served.
+	if err := r.reconcileResourceGraphDefinitionConversion(rgd, processedRGD, crd); err != nil {
 		mark.KindUnready(err.Error())
 		return processedRGD.TopologicalOrder, resourcesInfo, err
 	}
+
+	if leader {
+		// Refuse to patch the CRD with a schema that breaks existing instances,
+		// unless the RGD explicitly opted into breaking changes.
+		log.V(1).Info("checking resource graph definition CRD compatibility")
+		if err := r.checkResourceGraphDefinitionCRDCompatibility(ctx, rgd, crd); err != nil {
+			var breakingErr *breakingChangesError
+			if errors.As(err, &breakingErr) {
+				mark.KindBreakingChanges(err.Error())
+				// There is no point in retrying until the spec or the annotation
+				// changes, both of which trigger a reconcile.
+				return processedRGD.TopologicalOrder, resourcesInfo, reconcile.TerminalError(err)
+			}
+			mark.KindUnready(err.Error())
+			return processedRGD.TopologicalOrder, resourcesInfo, err
+		}
+
+		// Ensure CRD exists and is up to date
+		log.V(1).Info("reconciling resource graph definition CRD")
+		if err := r.reconcileResourceGraphDefinitionCRD(ctx, crd); err != nil {
+			mark.KindUnready(err.Error())
+			return processedRGD.TopologicalOrder, resourcesInfo, err
+		}
+	}
 	if crd, err = r.crdManager.Get(ctx, crd.Name); err != nil {
 		mark.KindUnready(err.Error())
+		if !leader {
+			// Retry until the leader created the CRD.
+			return processedRGD.TopologicalOrder, resourcesInfo, newCRDError(err)
+		}
 	} else {
 		mark.KindReady(crd.Status.AcceptedNames.Kind)
 	}
@@ -76,7 +115,37 @@ func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinition(
 	// TODO: the context that is passed here is tied to the reconciliation of the rgd, we might need to make
 	// a new context with our own cancel function here to allow us to cleanly term the dynamic controller
 	// rather than have it ignore this context and use the background context.
-	if err := r.reconcileResourceGraphDefinitionMicroController(ctx, processedRGD, graphExecLabeler); err != nil {
+	// Snapshot the generation before instances are reconciled with it, so that
+	// instances can record and pin the revision they were reconciled with.
+	if leader {
+		log.V(1).Info("reconciling resource graph definition graph revision")
+		if err := r.reconcileGraphRevision(ctx, rgd, processedRGD); err != nil {
+			mark.ControllerFailedToStart(err.Error())
+			return processedRGD.TopologicalOrder, resourcesInfo, err
+		}
+	}
+	revisions := r.graphRevisionsFor(rgd.Name)
+	revisions.add(rgd.Generation, processedRGD)
+
+	// Decide which instances the generation is rolled out to before the
+	// microcontroller starts reconciling them.
+	log.V(1).Info("reconciling resource graph definition rollout")
+	rollout, err := r.reconcileRollout(ctx, rgd)
+	if err != nil {
+		mark.ControllerFailedToStart(err.Error())
+		return processedRGD.TopologicalOrder, resourcesInfo, err
+	}
+
+	// Pruning only bounds the history, the generation is served regardless.
+	log.V(1).Info("pruning resource graph definition graph revisions")
+	if err := r.pruneGraphRevisions(ctx, rgd, leader); err != nil {
+		log.Error(err, "failed to prune graph revisions")
+	}
+
+	if err := r.reconcileResourceGraphDefinitionMicroController(
+		ctx, processedRGD, graphExecLabeler, rgd.Generation, revisions, rollout, rgd.Spec.PriorityClass,
+		r.instanceTuning(rgd),
+	); err != nil {
 		mark.ControllerFailedToStart(err.Error())
 		return processedRGD.TopologicalOrder, resourcesInfo, err
 	}
@@ -103,6 +172,10 @@ func (r *ResourceGraphDefinitionReconciler) setupLabeler(rgd *v1alpha1.ResourceG
 func (r *ResourceGraphDefinitionReconciler) setupMicroController(
 	processedRGD *graph.Graph,
 	labeler metadata.Labeler,
+	revision int64,
+	revisions instancectrl.GraphRevisions,
+	rollout instancectrl.Rollout,
+	requeueDuration time.Duration,
 ) *instancectrl.Controller {
 	gvr := processedRGD.Instance.Meta.GVR
 	instanceLogger := r.instanceLogger.WithName(fmt.Sprintf("%s-controller", gvr.Resource)).WithValues(
@@ -111,24 +184,39 @@ func (r *ResourceGraphDefinitionReconciler) setupMicroController(
 		"controllerKind", processedRGD.CRD.Spec.Names.Kind,
 	)
 
+	// Avoid handing a typed nil to the instance controller.
+	var externalWatcher instancectrl.ExternalWatcher
+	var namespaces instancectrl.NamespaceScope
+	if r.dynamicController != nil {
+		externalWatcher = r.dynamicController
+		namespaces = r.dynamicController
+	}
+
 	return instancectrl.NewController(
 		instanceLogger,
 		instancectrl.ReconcileConfig{
-			DefaultRequeueDuration:    3 * time.Second,
+			DefaultRequeueDuration:    requeueDuration,
 			DeletionGraceTimeDuration: 30 * time.Second,
 			DeletionPolicy:            "Delete",
+			HistoryLimit:              history.DefaultLimit,
 		},
 		gvr,
 		processedRGD,
 		r.clientSet,
 		labeler,
+		externalWatcher,
+		namespaces,
+		revision,
+		revisions,
+		rollout,
+		r.eventRecorder,
 	)
 }
 
 // reconcileResourceGraphDefinitionGraph processes the resource graph definition to build a dependency graph
 // and extract resource information
-func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinitionGraph(_ context.Context, rgd *v1alpha1.ResourceGraphDefinition) (*graph.Graph, []v1alpha1.ResourceInformation, error) {
-	processedRGD, err := r.rgBuilder.NewResourceGraphDefinition(rgd)
+func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinitionGraph(ctx context.Context, rgd *v1alpha1.ResourceGraphDefinition) (*graph.Graph, []v1alpha1.ResourceInformation, error) {
+	processedRGD, err := r.rgBuilder.NewResourceGraphDefinitionContext(ctx, rgd)
 	if err != nil {
 		return nil, nil, newGraphError(err)
 	}
@@ -156,6 +244,41 @@ func buildResourceInfo(name string, deps []string) v1alpha1.ResourceInformation
 	}
 }
 
+// checkResourceGraphDefinitionCRDCompatibility compares the desired CRD with the
+// one currently in the cluster, and returns a breakingChangesError if the update
+// would break existing instances or clients.
+func (r *ResourceGraphDefinitionReconciler) checkResourceGraphDefinitionCRDCompatibility(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+	crd *v1.CustomResourceDefinition,
+) error {
+	existing, err := r.crdManager.Get(ctx, crd.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return newCRDError(fmt.Errorf("failed to get existing CRD: %w", err))
+	}
+	// CRDs owned by someone else are rejected by CRDWrapper.Ensure.
+	if kroOwned, nameMatch, _ := metadata.CompareRGDOwnership(existing.ObjectMeta, crd.ObjectMeta); !kroOwned || !nameMatch {
+		return nil
+	}
+
+	incompatibilities := kcrd.CheckCompatibility(existing, crd)
+	if len(incompatibilities) == 0 {
+		return nil
+	}
+	if metadata.AllowsBreakingChanges(rgd) {
+		ctrl.LoggerFrom(ctx).Info("applying breaking CRD changes",
+			"crd", crd.Name,
+			"annotation", metadata.AllowBreakingChangesAnnotation,
+			"incompatibilities", incompatibilities.String(),
+		)
+		return nil
+	}
+	return &breakingChangesError{incompatibilities: incompatibilities}
+}
+
 // reconcileResourceGraphDefinitionCRD ensures the CRD is present and up to date in the cluster
 func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinitionCRD(ctx context.Context, crd *v1.CustomResourceDefinition) error {
 	if err := r.crdManager.Ensure(ctx, *crd); err != nil {
@@ -164,22 +287,54 @@ func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinitionCRD(
 	return nil
 }
 
+// reconcileResourceGraphDefinitionConversion points the CRD at the conversion
+// webhook if the graph has more than one version. Converters are registered
+// with the webhook of every replica by the WebhookReconciler.
+func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinitionConversion(
+	rgd *v1alpha1.ResourceGraphDefinition,
+	processedRGD *graph.Graph,
+	crd *v1.CustomResourceDefinition,
+) error {
+	if processedRGD.Converter == nil {
+		return nil
+	}
+
+	if r.conversionWebhook == nil {
+		return newCRDError(fmt.Errorf(
+			"schema declares %d additional versions but the conversion webhook is disabled",
+			len(rgd.Spec.Schema.Versions),
+		))
+	}
+	crd.Spec.Conversion.Webhook.ClientConfig = r.conversionWebhook.ClientConfig()
+	return nil
+}
+
 // reconcileResourceGraphDefinitionMicroController starts the microcontroller for handling the resources
 func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinitionMicroController(
 	ctx context.Context,
 	processedRGD *graph.Graph,
 	graphExecLabeler metadata.Labeler,
+	revision int64,
+	revisions instancectrl.GraphRevisions,
+	rollout instancectrl.Rollout,
+	priorityClass v1alpha1.PriorityClass,
+	tuning instanceTuning,
 ) error {
 	// If we want to react to changes to resources, we need to watch for them
 	// and trigger reconciliations of the instances whenever these resources change.
 	resourceGVRsToWatch := r.getResourceGVRsToWatchForRGD(processedRGD)
 
 	// Setup and start microcontroller
-	controller := r.setupMicroController(processedRGD, graphExecLabeler)
+	controller := r.setupMicroController(
+		processedRGD, graphExecLabeler, revision, revisions, rollout, tuning.requeueDuration,
+	)
 
 	ctrl.LoggerFrom(ctx).V(1).Info("reconciling resource graph definition micro controller")
 	gvr := processedRGD.Instance.Meta.GVR
 
+	r.dynamicController.SetPriority(gvr, queuePriority(priorityClass))
+	r.dynamicController.SetTuning(gvr, tuning.Tuning)
+	r.dynamicController.SetReleaseHandler(gvr, controller.Release)
 	err := r.dynamicController.Register(ctx, gvr, controller.Reconcile, resourceGVRsToWatch...)
 	if err != nil {
 		return newMicroControllerError(err)
@@ -187,6 +342,32 @@ func (r *ResourceGraphDefinitionReconciler) reconcileResourceGraphDefinitionMicr
 	return nil
 }
 
+// queuePriority returns the dynamic controller queue priority of a priority
+// class.
+func queuePriority(class v1alpha1.PriorityClass) dynamiccontroller.Priority {
+	switch class {
+	case v1alpha1.PriorityClassLow:
+		return dynamiccontroller.PriorityLow
+	case v1alpha1.PriorityClassHigh:
+		return dynamiccontroller.PriorityHigh
+	default:
+		return dynamiccontroller.PriorityNormal
+	}
+}
+
+// breakingChangesError is returned when the desired CRD is incompatible with
+// the existing one.
+type breakingChangesError struct {
+	incompatibilities kcrd.Incompatibilities
+}
+
+func (e *breakingChangesError) Error() string {
+	return fmt.Sprintf(
+		"CRD update blocked, %d breaking changes detected (set the %s=true annotation to apply them anyway): %s",
+		len(e.incompatibilities), metadata.AllowBreakingChangesAnnotation, e.incompatibilities.String(),
+	)
+}
+
 // Error types for the resourcegraphdefinition controller
 type (
 	graphError           struct{ err error }
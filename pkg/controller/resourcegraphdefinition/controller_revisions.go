@@ -0,0 +1,347 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraphdefinition
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	instancectrl "github.com/kubernetes-sigs/kro/pkg/controller/instance"
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+// graphRevisionHistoryLimit is the number of most recent GraphRevisions kept
+// for each ResourceGraphDefinition. Older revisions are deleted once no instance
+// is pinned to or reconciled with them.
+const graphRevisionHistoryLimit = 10
+
+// graphBuilder compiles ResourceGraphDefinitions into graphs.
+type graphBuilder interface {
+	NewResourceGraphDefinition(rgd *v1alpha1.ResourceGraphDefinition) (*graph.Graph, error)
+}
+
+// graphRevisions compiles and caches the graphs of the revisions of a
+// ResourceGraphDefinition. Graphs of previous revisions are compiled from
+// their GraphRevision snapshot the first time a pinned instance needs them.
+type graphRevisions struct {
+	rgdName string
+	client  client.Reader
+	builder graphBuilder
+
+	mu     sync.Mutex
+	graphs map[int64]*graph.Graph
+	// compiling deduplicates the compilation of revisions that are not
+	// cached, which happens outside of mu.
+	compiling singleflight.Group
+}
+
+var _ instancectrl.GraphRevisions = (*graphRevisions)(nil)
+
+func newGraphRevisions(rgdName string, c client.Reader, builder graphBuilder) *graphRevisions {
+	return &graphRevisions{
+		rgdName: rgdName,
+		client:  c,
+		builder: builder,
+		graphs:  make(map[int64]*graph.Graph),
+	}
+}
+
+// add caches the graph compiled for the given revision.
+func (g *graphRevisions) add(revision int64, compiled *graph.Graph) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.graphs[revision] = compiled
+}
+
+// retain evicts the cached graphs of the revisions keep returns false for.
+func (g *graphRevisions) retain(keep func(revision int64) bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for revision := range g.graphs {
+		if !keep(revision) {
+			delete(g.graphs, revision)
+		}
+	}
+}
+
+// Graph implements instancectrl.GraphRevisions. Revisions that are not cached
+// are compiled once, without blocking the instances of cached revisions.
+func (g *graphRevisions) Graph(ctx context.Context, revision int64) (*graph.Graph, error) {
+	g.mu.Lock()
+	compiled, ok := g.graphs[revision]
+	g.mu.Unlock()
+	if ok {
+		return compiled, nil
+	}
+
+	v, err, _ := g.compiling.Do(strconv.FormatInt(revision, 10), func() (any, error) {
+		compiled, err := g.compile(ctx, revision)
+		if err != nil {
+			return nil, err
+		}
+		g.add(revision, compiled)
+		return compiled, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*graph.Graph), nil
+}
+
+// compile compiles the graph of a revision from its GraphRevision snapshot.
+func (g *graphRevisions) compile(ctx context.Context, revision int64) (*graph.Graph, error) {
+	name := metadata.GraphRevisionName(g.rgdName, revision)
+	gr := &v1alpha1.GraphRevision{}
+	if err := g.client.Get(ctx, types.NamespacedName{Name: name}, gr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("graph revision %s not found", name)
+		}
+		return nil, fmt.Errorf("failed to get graph revision %s: %w", name, err)
+	}
+	hash, err := graphRevisionHash(&gr.Spec.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if hash != gr.Spec.Hash {
+		return nil, fmt.Errorf("graph revision %s snapshot does not match its hash", name)
+	}
+
+	compiled, err := g.builder.NewResourceGraphDefinition(&v1alpha1.ResourceGraphDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: g.rgdName},
+		Spec:       gr.Spec.Snapshot,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile graph revision %s: %w", name, err)
+	}
+	return compiled, nil
+}
+
+// graphRevisionsFor returns the graph revisions cache of the named
+// ResourceGraphDefinition, creating it if needed.
+func (r *ResourceGraphDefinitionReconciler) graphRevisionsFor(rgdName string) *graphRevisions {
+	r.revisionsMu.Lock()
+	defer r.revisionsMu.Unlock()
+	revisions, ok := r.revisions[rgdName]
+	if !ok {
+		revisions = newGraphRevisions(rgdName, r.Client, r.rgBuilder)
+		r.revisions[rgdName] = revisions
+	}
+	return revisions
+}
+
+// forgetGraphRevisions drops the graph revisions cache of the named
+// ResourceGraphDefinition.
+func (r *ResourceGraphDefinitionReconciler) forgetGraphRevisions(rgdName string) {
+	r.revisionsMu.Lock()
+	defer r.revisionsMu.Unlock()
+	delete(r.revisions, rgdName)
+}
+
+// TopologicalOrders returns the topological order of the compiled graphs of
+// each ResourceGraphDefinition, by revision.
+func (r *ResourceGraphDefinitionReconciler) TopologicalOrders() map[string]map[int64][]string {
+	r.revisionsMu.Lock()
+	defer r.revisionsMu.Unlock()
+
+	orders := make(map[string]map[int64][]string, len(r.revisions))
+	for name, revisions := range r.revisions {
+		orders[name] = revisions.topologicalOrders()
+	}
+	return orders
+}
+
+// topologicalOrders returns the topological order of each cached graph.
+func (g *graphRevisions) topologicalOrders() map[int64][]string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	orders := make(map[int64][]string, len(g.graphs))
+	for revision, compiled := range g.graphs {
+		orders[revision] = compiled.TopologicalOrder
+	}
+	return orders
+}
+
+// reconcileGraphRevision ensures the GraphRevision of the current generation
+// of the ResourceGraphDefinition exists. Revisions are immutable: an existing
+// revision is only replaced if it was left behind by a deleted
+// ResourceGraphDefinition of the same name.
+func (r *ResourceGraphDefinitionReconciler) reconcileGraphRevision(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+	processedRGD *graph.Graph,
+) error {
+	hash, err := graphRevisionHash(&rgd.Spec)
+	if err != nil {
+		return err
+	}
+	name := metadata.GraphRevisionName(rgd.Name, rgd.Generation)
+
+	existing := &v1alpha1.GraphRevision{}
+	err = r.Get(ctx, types.NamespacedName{Name: name}, existing)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("failed to get graph revision %s: %w", name, err)
+	case !metav1.IsControlledBy(existing, rgd):
+		ctrl.LoggerFrom(ctx).Info("deleting stale graph revision", "graphRevision", name)
+		if err := r.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete stale graph revision %s: %w", name, err)
+		}
+		return fmt.Errorf("graph revision %s belongs to a deleted ResourceGraphDefinition", name)
+	case existing.Spec.Hash != hash:
+		return fmt.Errorf("graph revision %s does not match generation %d of the ResourceGraphDefinition", name, rgd.Generation)
+	default:
+		return nil
+	}
+
+	ctrl.LoggerFrom(ctx).V(1).Info("creating graph revision", "graphRevision", name)
+	gr := &v1alpha1.GraphRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				metadata.ResourceGraphDefinitionNameLabel: rgd.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(rgd, v1alpha1.GroupVersion.WithKind("ResourceGraphDefinition")),
+			},
+		},
+		Spec: v1alpha1.GraphRevisionSpec{
+			ResourceGraphDefinitionName: rgd.Name,
+			Revision:                    rgd.Generation,
+			Hash:                        hash,
+			TopologicalOrder:            processedRGD.TopologicalOrder,
+			Snapshot:                    *rgd.Spec.DeepCopy(),
+		},
+	}
+	if err := r.Create(ctx, gr); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create graph revision %s: %w", name, err)
+	}
+	return nil
+}
+
+// pruneGraphRevisions deletes the GraphRevisions of the ResourceGraphDefinition
+// beyond the graphRevisionHistoryLimit most recent ones, unless they are being
+// rolled out or instances are pinned to or reconciled with them, and evicts the
+// compiled graphs of the revisions that no longer exist. Only the leader
+// deletes revisions.
+func (r *ResourceGraphDefinitionReconciler) pruneGraphRevisions(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+	leader bool,
+) error {
+	list := &v1alpha1.GraphRevisionList{}
+	if err := r.List(ctx, list, client.MatchingLabels{metadata.ResourceGraphDefinitionNameLabel: rgd.Name}); err != nil {
+		return fmt.Errorf("failed to list graph revisions: %w", err)
+	}
+	var revisions []*v1alpha1.GraphRevision
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], rgd) {
+			revisions = append(revisions, &list.Items[i])
+		}
+	}
+	// Most recent first.
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Spec.Revision > revisions[j].Spec.Revision
+	})
+
+	existing := make(map[int64]bool, len(revisions))
+	for _, gr := range revisions {
+		existing[gr.Spec.Revision] = true
+	}
+
+	if leader && len(revisions) > graphRevisionHistoryLimit {
+		used, err := r.usedGraphRevisions(ctx, rgd)
+		if err != nil {
+			return err
+		}
+		for _, gr := range revisions[graphRevisionHistoryLimit:] {
+			if used[gr.Spec.Revision] {
+				continue
+			}
+			ctrl.LoggerFrom(ctx).V(1).Info("deleting graph revision", "graphRevision", gr.Name)
+			if err := r.Delete(ctx, gr); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete graph revision %s: %w", gr.Name, err)
+			}
+			delete(existing, gr.Spec.Revision)
+		}
+	}
+
+	// The revision of the current generation may not be listed yet.
+	r.graphRevisionsFor(rgd.Name).retain(func(revision int64) bool {
+		return existing[revision] || revision == rgd.Generation
+	})
+	return nil
+}
+
+// usedGraphRevisions returns the revisions of the ResourceGraphDefinition that
+// must be kept: the revisions of the rollout, and the revisions instances are
+// pinned to or were last reconciled with.
+func (r *ResourceGraphDefinitionReconciler) usedGraphRevisions(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+) (map[int64]bool, error) {
+	used := map[int64]bool{rgd.Generation: true}
+	if status := rgd.Status.Rollout; status != nil {
+		used[status.Revision] = true
+		used[status.StableRevision] = true
+	}
+
+	gvr := metadata.GetResourceGraphDefinitionInstanceGVR(rgd.Spec.Schema.Group, rgd.Spec.Schema.APIVersion, rgd.Spec.Schema.Kind)
+	items, err := r.listInstances(ctx, rgd, gvr)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		// Instances with an invalid pin are not reconciled: keep every
+		// revision until the label is fixed.
+		pinned, err := metadata.PinnedGraphRevision(&items[i])
+		if err != nil {
+			return nil, fmt.Errorf("instance %s: %w", instanceKey(&items[i]), err)
+		}
+		used[pinned] = true
+		if observed, ok, _ := unstructured.NestedInt64(items[i].Object, "status", "graphRevision"); ok {
+			used[observed] = true
+		}
+	}
+	return used, nil
+}
+
+// graphRevisionHash returns the hash identifying a ResourceGraphDefinition
+// spec.
+func graphRevisionHash(spec *v1alpha1.ResourceGraphDefinitionSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal resource graph definition spec: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
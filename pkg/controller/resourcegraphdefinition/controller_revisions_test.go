@@ -0,0 +1,165 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraphdefinition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	kroclientfake "github.com/kubernetes-sigs/kro/pkg/client/fake"
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+type fakeGraphBuilder struct {
+	calls int
+}
+
+func (b *fakeGraphBuilder) NewResourceGraphDefinition(rgd *v1alpha1.ResourceGraphDefinition) (*graph.Graph, error) {
+	b.calls++
+	return &graph.Graph{TopologicalOrder: []string{rgd.Spec.Schema.Kind}}, nil
+}
+
+func newGraphRevision(t *testing.T, revision int64, kind string) *v1alpha1.GraphRevision {
+	spec := v1alpha1.ResourceGraphDefinitionSpec{
+		Schema: &v1alpha1.Schema{Kind: kind, APIVersion: "v1alpha1"},
+	}
+	hash, err := graphRevisionHash(&spec)
+	require.NoError(t, err)
+	return &v1alpha1.GraphRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: metadata.GraphRevisionName("webapp", revision)},
+		Spec: v1alpha1.GraphRevisionSpec{
+			ResourceGraphDefinitionName: "webapp",
+			Revision:                    revision,
+			Hash:                        hash,
+			Snapshot:                    spec,
+		},
+	}
+}
+
+func TestGraphRevisions_Graph(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	tampered := newGraphRevision(t, 3, "WebApp")
+	tampered.Spec.Snapshot.Schema.Kind = "Other"
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newGraphRevision(t, 1, "WebApp"), tampered).
+		Build()
+	builder := &fakeGraphBuilder{}
+	revisions := newGraphRevisions("webapp", c, builder)
+
+	latest := &graph.Graph{}
+	revisions.add(2, latest)
+
+	g, err := revisions.Graph(ctx, 2)
+	require.NoError(t, err)
+	assert.Same(t, latest, g)
+	assert.Equal(t, 0, builder.calls)
+
+	g, err = revisions.Graph(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"WebApp"}, g.TopologicalOrder)
+	_, err = revisions.Graph(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, builder.calls, "compiled revisions should be cached")
+
+	_, err = revisions.Graph(ctx, 3)
+	assert.ErrorContains(t, err, "does not match its hash")
+
+	_, err = revisions.Graph(ctx, 4)
+	assert.ErrorContains(t, err, "graph revision webapp-4 not found")
+}
+
+func TestPruneGraphRevisions(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	rgd := &v1alpha1.ResourceGraphDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapp", UID: "rgd-uid", Generation: 14},
+		Spec: v1alpha1.ResourceGraphDefinitionSpec{
+			Schema: &v1alpha1.Schema{Group: "kro.run", Kind: "WebApp", APIVersion: "v1alpha1"},
+		},
+	}
+	var objs []client.Object
+	for revision := int64(1); revision <= 14; revision++ {
+		gr := newGraphRevision(t, revision, "WebApp")
+		gr.Labels = map[string]string{metadata.ResourceGraphDefinitionNameLabel: "webapp"}
+		gr.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(rgd, v1alpha1.GroupVersion.WithKind("ResourceGraphDefinition")),
+		}
+		objs = append(objs, gr)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	gvr := metadata.GetResourceGraphDefinitionInstanceGVR("kro.run", "v1alpha1", "WebApp")
+	newInstance := func(name string) *unstructured.Unstructured {
+		inst := &unstructured.Unstructured{}
+		inst.SetAPIVersion("kro.run/v1alpha1")
+		inst.SetKind("WebApp")
+		inst.SetNamespace("default")
+		inst.SetName(name)
+		return inst
+	}
+	pinned := newInstance("pinned")
+	pinned.SetLabels(map[string]string{metadata.GraphRevisionLabel: "2"})
+	reconciled := newInstance("reconciled")
+	require.NoError(t, unstructured.SetNestedField(reconciled.Object, int64(3), "status", "graphRevision"))
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "WebAppList"},
+		pinned, reconciled,
+	)
+
+	r := &ResourceGraphDefinitionReconciler{
+		Client:    c,
+		clientSet: kroclientfake.NewFakeSet(dynamicClient),
+		revisions: make(map[string]*graphRevisions),
+	}
+	cached := r.graphRevisionsFor("webapp")
+	for _, revision := range []int64{1, 2, 4, 14} {
+		cached.add(revision, &graph.Graph{})
+	}
+
+	// Followers only evict the graphs of revisions that no longer exist.
+	require.NoError(t, r.pruneGraphRevisions(ctx, rgd, false))
+	assert.Len(t, cached.topologicalOrders(), 4)
+
+	require.NoError(t, r.pruneGraphRevisions(ctx, rgd, true))
+
+	list := &v1alpha1.GraphRevisionList{}
+	require.NoError(t, c.List(ctx, list))
+	var remaining []int64
+	for _, gr := range list.Items {
+		remaining = append(remaining, gr.Spec.Revision)
+	}
+	assert.ElementsMatch(t, []int64{2, 3, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}, remaining)
+	assert.Equal(t, map[int64][]string{2: nil, 14: nil}, cached.topologicalOrders())
+}
@@ -0,0 +1,455 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraphdefinition
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	instancectrl "github.com/kubernetes-sigs/kro/pkg/controller/instance"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+// rolloutPollInterval is how often the progress of a rollout is checked, from
+// the instances cached by the dynamic controller.
+const rolloutPollInterval = 10 * time.Second
+
+// Defaults of the rollout policy, applied by the CRD to new objects.
+const (
+	defaultRolloutBatchPercentage         = 25
+	defaultRolloutProgressDeadlineSeconds = 600
+)
+
+// maxReportedInstances caps the number of instances named in rollout
+// failure messages.
+const maxReportedInstances = 5
+
+// rolloutPlan tells the instance controller which revision each instance is
+// reconciled with, from the rollout status of the ResourceGraphDefinition.
+type rolloutPlan struct {
+	mu sync.RWMutex
+
+	revision       int64
+	stableRevision int64
+	phase          v1alpha1.RolloutPhase
+	startTime      time.Time
+	updated        map[types.NamespacedName]struct{}
+}
+
+var _ instancectrl.Rollout = (*rolloutPlan)(nil)
+
+// Revision implements instancectrl.Rollout.
+func (p *rolloutPlan) Revision(inst metav1.Object) int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	switch p.phase {
+	case v1alpha1.RolloutPhaseProgressing, v1alpha1.RolloutPhaseHalted:
+		// Instances created since the rollout started never ran the stable
+		// revision, there is nothing to protect.
+		if !inst.GetCreationTimestamp().Time.Before(p.startTime) {
+			return p.revision
+		}
+		if _, ok := p.updated[instanceKey(inst)]; ok {
+			return p.revision
+		}
+		return p.stableRevision
+	case v1alpha1.RolloutPhaseRolledBack:
+		return p.stableRevision
+	default:
+		return p.revision
+	}
+}
+
+func (p *rolloutPlan) set(status *v1alpha1.RolloutStatus, updated map[types.NamespacedName]struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.revision = status.Revision
+	p.stableRevision = status.StableRevision
+	p.phase = status.Phase
+	p.startTime = time.Time{}
+	if status.StartTime != nil {
+		p.startTime = status.StartTime.Time
+	}
+	p.updated = updated
+}
+
+// rolloutPlanFor returns the rollout plan of the named
+// ResourceGraphDefinition, creating it if needed.
+func (r *ResourceGraphDefinitionReconciler) rolloutPlanFor(rgdName string) *rolloutPlan {
+	r.rolloutsMu.Lock()
+	defer r.rolloutsMu.Unlock()
+	plan, ok := r.rollouts[rgdName]
+	if !ok {
+		plan = &rolloutPlan{}
+		r.rollouts[rgdName] = plan
+	}
+	return plan
+}
+
+// forgetRolloutPlan drops the rollout plan of the named
+// ResourceGraphDefinition.
+func (r *ResourceGraphDefinitionReconciler) forgetRolloutPlan(rgdName string) {
+	r.rolloutsMu.Lock()
+	defer r.rolloutsMu.Unlock()
+	delete(r.rollouts, rgdName)
+}
+
+// rolloutInProgress returns true if the generation of the
+// ResourceGraphDefinition is being rolled out by a running microcontroller,
+// in which case reconciliations only need to advance the rollout.
+func (r *ResourceGraphDefinitionReconciler) rolloutInProgress(rgd *v1alpha1.ResourceGraphDefinition) bool {
+	status := rgd.Status.Rollout
+	if rgd.Spec.Rollout == nil || status == nil ||
+		status.Phase != v1alpha1.RolloutPhaseProgressing || status.Revision != rgd.Generation {
+		return false
+	}
+	if !rgdConditionTypes.For(rgd).IsTrue(ControllerReady) {
+		return false
+	}
+	r.rolloutsMu.Lock()
+	defer r.rolloutsMu.Unlock()
+	plan, ok := r.rollouts[rgd.Name]
+	if !ok {
+		return false
+	}
+	plan.mu.RLock()
+	defer plan.mu.RUnlock()
+	return plan.revision == rgd.Generation
+}
+
+// rolloutResult requeues the ResourceGraphDefinition while its rollout is in
+// progress.
+func rolloutResult(rgd *v1alpha1.ResourceGraphDefinition) ctrl.Result {
+	if rgd.Status.Rollout != nil && rgd.Status.Rollout.Phase == v1alpha1.RolloutPhaseProgressing {
+		return ctrl.Result{RequeueAfter: rolloutPollInterval}
+	}
+	return ctrl.Result{}
+}
+
+// reconcileRollout starts the rollout of a new generation of the
+// ResourceGraphDefinition, advances the rollout in progress, and updates
+// rgd.Status.Rollout. It returns the rollout the instance controller must
+// follow, or nil if the ResourceGraphDefinition declares no rollout policy.
+func (r *ResourceGraphDefinitionReconciler) reconcileRollout(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+) (instancectrl.Rollout, error) {
+	if rgd.Spec.Rollout == nil {
+		rgd.Status.Rollout = nil
+		r.forgetRolloutPlan(rgd.Name)
+		return nil, nil
+	}
+
+	status := startRollout(rgd.Status.Rollout, rgd.Generation, time.Now())
+	rgd.Status.Rollout = status
+
+	plan := r.rolloutPlanFor(rgd.Name)
+	if status.Phase != v1alpha1.RolloutPhaseProgressing && status.Phase != v1alpha1.RolloutPhaseHalted {
+		plan.set(status, nil)
+		return plan, nil
+	}
+
+	gvr := metadata.GetResourceGraphDefinitionInstanceGVR(rgd.Spec.Schema.Group, rgd.Spec.Schema.APIVersion, rgd.Spec.Schema.Kind)
+	if err := r.progressRollout(ctx, rgd, gvr, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// progressRollout advances the rollout in progress from the state of the
+// instances, and enqueues the instances that moved to another revision.
+func (r *ResourceGraphDefinitionReconciler) progressRollout(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+	gvr schema.GroupVersionResource,
+	plan *rolloutPlan,
+) error {
+	items, err := r.rolloutItems(ctx, rgd, gvr)
+	if err != nil {
+		return err
+	}
+	instances, err := newRolloutInstances(rgd.Spec.Rollout, items, rgd.Status.Rollout.Revision)
+	if err != nil {
+		return err
+	}
+
+	plan.mu.RLock()
+	before := plan.updated
+	plan.mu.RUnlock()
+
+	status := rgd.Status.Rollout
+	updated := advanceRollout(rgd.Spec.Rollout, status, instances, time.Now())
+	plan.set(status, updated)
+
+	var moved []types.NamespacedName
+	for _, inst := range instances {
+		_, wasUpdated := before[inst.key]
+		_, isUpdated := updated[inst.key]
+		if wasUpdated != isUpdated || (status.Phase == v1alpha1.RolloutPhaseRolledBack && isUpdated) {
+			moved = append(moved, inst.key)
+		}
+	}
+	if len(moved) > 0 && r.dynamicController != nil {
+		ctrl.LoggerFrom(ctx).V(1).Info("rollout moved instances", "revision", status.Revision, "count", len(moved))
+		r.dynamicController.Enqueue(gvr, moved...)
+	}
+	return nil
+}
+
+// rolloutItems returns the metadata of the instances a rollout follows, from
+// the cache of the dynamic controller. Instances are only cached once the
+// microcontroller is registered and its informer synced, e.g. not right after
+// a restart, in which case they are listed.
+func (r *ResourceGraphDefinitionReconciler) rolloutItems(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+	gvr schema.GroupVersionResource,
+) ([]metav1.Object, error) {
+	if r.dynamicController != nil {
+		if items, err := r.dynamicController.CachedInstances(gvr); err == nil {
+			return items, nil
+		}
+	}
+	list, err := r.listInstances(ctx, rgd, gvr)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]metav1.Object, len(list))
+	for i := range list {
+		items[i] = &list[i]
+	}
+	return items, nil
+}
+
+// listInstances lists the instances served by kro. When kro only watches some
+// namespaces, instances are listed in each of them.
+func (r *ResourceGraphDefinitionReconciler) listInstances(
+	ctx context.Context,
+	rgd *v1alpha1.ResourceGraphDefinition,
+	gvr schema.GroupVersionResource,
+) ([]unstructured.Unstructured, error) {
+	var namespaces []string
+	if r.dynamicController != nil && !rgd.Spec.Schema.IsClusterScoped() {
+		namespaces = r.dynamicController.WatchedNamespaces()
+	}
+	if namespaces == nil {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	var items []unstructured.Unstructured
+	for _, ns := range namespaces {
+		list, err := r.clientSet.Dynamic().Resource(gvr).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances: %w", err)
+		}
+		items = append(items, list.Items...)
+	}
+	return items, nil
+}
+
+// startRollout returns the rollout status of the given generation: a new
+// rollout from the last completely rolled out revision if the generation
+// changed, or the current status otherwise. The first generation observed
+// with a rollout policy is considered rolled out.
+func startRollout(current *v1alpha1.RolloutStatus, generation int64, now time.Time) *v1alpha1.RolloutStatus {
+	if current == nil {
+		return &v1alpha1.RolloutStatus{
+			Revision:       generation,
+			StableRevision: generation,
+			Phase:          v1alpha1.RolloutPhaseCompleted,
+		}
+	}
+	if current.Revision == generation {
+		return current.DeepCopy()
+	}
+
+	stable := current.StableRevision
+	if current.Phase == v1alpha1.RolloutPhaseCompleted {
+		stable = current.Revision
+	}
+	start := metav1.NewTime(now.Truncate(time.Second))
+	return &v1alpha1.RolloutStatus{
+		Revision:       generation,
+		StableRevision: stable,
+		Phase:          v1alpha1.RolloutPhaseProgressing,
+		StartTime:      &start,
+		BatchStartTime: &start,
+	}
+}
+
+// rolloutInstance is the state of an instance relevant to a rollout.
+type rolloutInstance struct {
+	key     types.NamespacedName
+	created time.Time
+	canary  bool
+	// ready is true if the instance is Ready with the revision being rolled
+	// out, as labelled by the instance controller.
+	ready bool
+}
+
+func newRolloutInstances(
+	policy *v1alpha1.RolloutPolicy,
+	items []metav1.Object,
+	revision int64,
+) ([]rolloutInstance, error) {
+	canaries := labels.Nothing()
+	if policy.Canary != nil {
+		selector, err := metav1.LabelSelectorAsSelector(policy.Canary)
+		if err != nil {
+			return nil, fmt.Errorf("invalid canary selector: %w", err)
+		}
+		canaries = selector
+	}
+
+	instances := make([]rolloutInstance, 0, len(items))
+	for _, item := range items {
+		if item.GetDeletionTimestamp() != nil {
+			continue
+		}
+		instances = append(instances, rolloutInstance{
+			key:     instanceKey(item),
+			created: item.GetCreationTimestamp().Time,
+			canary:  canaries.Matches(labels.Set(item.GetLabels())),
+			ready:   metadata.ReadyGraphRevision(item) == revision,
+		})
+	}
+	return instances, nil
+}
+
+// advanceRollout advances the rollout by as many batches as are complete,
+// halts or rolls it back if the current batch missed its deadline, and
+// returns the instances the rollout reached.
+//
+// Batches are deterministic, so that the rollout can resume from its status
+// alone: the canaries first, then every batch adds batchPercentage of the
+// other instances, oldest first. Instances created after the rollout started
+// are always part of it.
+func advanceRollout(
+	policy *v1alpha1.RolloutPolicy,
+	status *v1alpha1.RolloutStatus,
+	instances []rolloutInstance,
+	now time.Time,
+) map[types.NamespacedName]struct{} {
+	start := time.Time{}
+	if status.StartTime != nil {
+		start = status.StartTime.Time
+	}
+	percentage := policy.BatchPercentage
+	if percentage <= 0 {
+		percentage = defaultRolloutBatchPercentage
+	}
+	deadlineSeconds := policy.ProgressDeadlineSeconds
+	if deadlineSeconds <= 0 {
+		deadlineSeconds = defaultRolloutProgressDeadlineSeconds
+	}
+
+	var eligible []rolloutInstance
+	for _, inst := range instances {
+		if !inst.canary && inst.created.Before(start) {
+			eligible = append(eligible, inst)
+		}
+	}
+	sort.Slice(eligible, func(i, j int) bool {
+		if !eligible[i].created.Equal(eligible[j].created) {
+			return eligible[i].created.Before(eligible[j].created)
+		}
+		return eligible[i].key.String() < eligible[j].key.String()
+	})
+
+	updatedFor := func(batch int32) map[types.NamespacedName]struct{} {
+		updated := make(map[types.NamespacedName]struct{}, len(instances))
+		for _, inst := range instances {
+			if inst.canary || !inst.created.Before(start) {
+				updated[inst.key] = struct{}{}
+			}
+		}
+		n := int(math.Ceil(float64(batch) * float64(percentage) * float64(len(eligible)) / 100))
+		for _, inst := range eligible[:min(n, len(eligible))] {
+			updated[inst.key] = struct{}{}
+		}
+		return updated
+	}
+
+	updated := updatedFor(status.Batch)
+	for status.Phase == v1alpha1.RolloutPhaseProgressing {
+		var unready []string
+		for _, inst := range instances {
+			if _, ok := updated[inst.key]; ok && !inst.ready {
+				unready = append(unready, inst.key.String())
+			}
+		}
+
+		if len(unready) == 0 {
+			if len(updated) == len(instances) {
+				status.Phase = v1alpha1.RolloutPhaseCompleted
+				status.Message = ""
+				break
+			}
+			status.Batch++
+			batchStart := metav1.NewTime(now.Truncate(time.Second))
+			status.BatchStartTime = &batchStart
+			updated = updatedFor(status.Batch)
+			continue
+		}
+
+		deadline := time.Duration(deadlineSeconds) * time.Second
+		if status.BatchStartTime != nil && now.Sub(status.BatchStartTime.Time) > deadline {
+			status.Message = rolloutFailureMessage(status, unready, deadline)
+			status.Phase = v1alpha1.RolloutPhaseHalted
+			if policy.OnFailure == v1alpha1.RolloutFailurePolicyRollback {
+				status.Phase = v1alpha1.RolloutPhaseRolledBack
+			}
+		}
+		break
+	}
+
+	status.TotalInstances = int32(len(instances))
+	status.UpdatedInstances = int32(len(updated))
+	status.ReadyInstances = 0
+	for _, inst := range instances {
+		if _, ok := updated[inst.key]; ok && inst.ready {
+			status.ReadyInstances++
+		}
+	}
+	return updated
+}
+
+func rolloutFailureMessage(status *v1alpha1.RolloutStatus, unready []string, deadline time.Duration) string {
+	sort.Strings(unready)
+	names := strings.Join(unready[:min(len(unready), maxReportedInstances)], ", ")
+	if len(unready) > maxReportedInstances {
+		names += fmt.Sprintf(" and %d more", len(unready)-maxReportedInstances)
+	}
+	return fmt.Sprintf("batch %d failed: instances not Ready with revision %d after %s: %s",
+		status.Batch, status.Revision, deadline, names)
+}
+
+func instanceKey(inst metav1.Object) types.NamespacedName {
+	return types.NamespacedName{Namespace: inst.GetNamespace(), Name: inst.GetName()}
+}
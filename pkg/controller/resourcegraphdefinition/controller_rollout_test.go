@@ -0,0 +1,220 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraphdefinition
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+var rolloutStart = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestStartRollout(t *testing.T) {
+	t.Run("first generation is rolled out", func(t *testing.T) {
+		status := startRollout(nil, 3, rolloutStart)
+		assert.Equal(t, v1alpha1.RolloutPhaseCompleted, status.Phase)
+		assert.Equal(t, int64(3), status.Revision)
+		assert.Equal(t, int64(3), status.StableRevision)
+	})
+
+	t.Run("same generation keeps its status", func(t *testing.T) {
+		current := &v1alpha1.RolloutStatus{Revision: 3, StableRevision: 2, Phase: v1alpha1.RolloutPhaseHalted, Batch: 2}
+		assert.Equal(t, current, startRollout(current, 3, rolloutStart))
+	})
+
+	t.Run("new generation starts from the completed revision", func(t *testing.T) {
+		current := &v1alpha1.RolloutStatus{Revision: 3, StableRevision: 2, Phase: v1alpha1.RolloutPhaseCompleted}
+		status := startRollout(current, 4, rolloutStart)
+		assert.Equal(t, v1alpha1.RolloutPhaseProgressing, status.Phase)
+		assert.Equal(t, int64(4), status.Revision)
+		assert.Equal(t, int64(3), status.StableRevision)
+		assert.Equal(t, int32(0), status.Batch)
+		assert.True(t, status.StartTime.Time.Equal(rolloutStart))
+	})
+
+	t.Run("new generation replaces an unfinished rollout", func(t *testing.T) {
+		current := &v1alpha1.RolloutStatus{Revision: 3, StableRevision: 2, Phase: v1alpha1.RolloutPhaseRolledBack}
+		status := startRollout(current, 4, rolloutStart)
+		assert.Equal(t, int64(2), status.StableRevision)
+	})
+}
+
+func newTestRolloutInstances(n int, ready func(i int) bool) []rolloutInstance {
+	instances := make([]rolloutInstance, 0, n)
+	for i := range n {
+		instances = append(instances, rolloutInstance{
+			key:     types.NamespacedName{Namespace: "default", Name: fmt.Sprintf("app-%d", i)},
+			created: rolloutStart.Add(-time.Duration(n-i) * time.Hour),
+			ready:   ready(i),
+		})
+	}
+	return instances
+}
+
+func newProgressingStatus(batch int32, batchStart time.Time) *v1alpha1.RolloutStatus {
+	start := metav1.NewTime(rolloutStart)
+	batchStartTime := metav1.NewTime(batchStart)
+	return &v1alpha1.RolloutStatus{
+		Revision:       2,
+		StableRevision: 1,
+		Phase:          v1alpha1.RolloutPhaseProgressing,
+		Batch:          batch,
+		StartTime:      &start,
+		BatchStartTime: &batchStartTime,
+	}
+}
+
+func TestAdvanceRollout(t *testing.T) {
+	policy := &v1alpha1.RolloutPolicy{BatchPercentage: 50, ProgressDeadlineSeconds: 60}
+	now := rolloutStart.Add(30 * time.Second)
+
+	t.Run("empty canary batch advances to the first batch", func(t *testing.T) {
+		status := newProgressingStatus(0, rolloutStart)
+		updated := advanceRollout(policy, status, newTestRolloutInstances(4, func(int) bool { return false }), now)
+
+		assert.Equal(t, v1alpha1.RolloutPhaseProgressing, status.Phase)
+		assert.Equal(t, int32(1), status.Batch)
+		assert.Len(t, updated, 2)
+		assert.Contains(t, updated, types.NamespacedName{Namespace: "default", Name: "app-0"})
+		assert.Contains(t, updated, types.NamespacedName{Namespace: "default", Name: "app-1"})
+		assert.Equal(t, int32(4), status.TotalInstances)
+		assert.Equal(t, int32(2), status.UpdatedInstances)
+		assert.Equal(t, int32(0), status.ReadyInstances)
+	})
+
+	t.Run("canaries go first", func(t *testing.T) {
+		instances := newTestRolloutInstances(4, func(int) bool { return false })
+		instances[3].canary = true
+		status := newProgressingStatus(0, rolloutStart)
+		updated := advanceRollout(policy, status, instances, now)
+
+		assert.Equal(t, int32(0), status.Batch)
+		assert.Len(t, updated, 1)
+		assert.Contains(t, updated, instances[3].key)
+	})
+
+	t.Run("ready batch advances", func(t *testing.T) {
+		status := newProgressingStatus(1, rolloutStart)
+		updated := advanceRollout(policy, status, newTestRolloutInstances(4, func(i int) bool { return i < 2 }), now)
+
+		assert.Equal(t, int32(2), status.Batch)
+		assert.Len(t, updated, 4)
+		assert.Equal(t, int32(2), status.ReadyInstances)
+	})
+
+	t.Run("rollout completes once every instance is ready", func(t *testing.T) {
+		status := newProgressingStatus(2, rolloutStart)
+		advanceRollout(policy, status, newTestRolloutInstances(4, func(int) bool { return true }), now)
+
+		assert.Equal(t, v1alpha1.RolloutPhaseCompleted, status.Phase)
+		assert.Equal(t, int32(4), status.ReadyInstances)
+	})
+
+	t.Run("instances created after the start are part of the rollout", func(t *testing.T) {
+		instances := newTestRolloutInstances(4, func(int) bool { return false })
+		instances[2].created = rolloutStart.Add(time.Second)
+		status := newProgressingStatus(1, rolloutStart)
+		updated := advanceRollout(policy, status, instances, now)
+
+		// Two of the three older instances, rounded up, plus the new one.
+		assert.Len(t, updated, 3)
+		assert.Contains(t, updated, instances[2].key)
+		assert.NotContains(t, updated, instances[3].key)
+	})
+
+	t.Run("batch past its deadline halts", func(t *testing.T) {
+		status := newProgressingStatus(1, rolloutStart)
+		updated := advanceRollout(policy, status, newTestRolloutInstances(4, func(i int) bool { return i == 0 }),
+			rolloutStart.Add(2*time.Minute))
+
+		assert.Equal(t, v1alpha1.RolloutPhaseHalted, status.Phase)
+		assert.Len(t, updated, 2)
+		assert.Contains(t, status.Message, "batch 1 failed")
+		assert.Contains(t, status.Message, "default/app-1")
+	})
+
+	t.Run("batch past its deadline rolls back", func(t *testing.T) {
+		rollback := policy.DeepCopy()
+		rollback.OnFailure = v1alpha1.RolloutFailurePolicyRollback
+		status := newProgressingStatus(1, rolloutStart)
+		advanceRollout(rollback, status, newTestRolloutInstances(4, func(int) bool { return false }),
+			rolloutStart.Add(2*time.Minute))
+
+		assert.Equal(t, v1alpha1.RolloutPhaseRolledBack, status.Phase)
+	})
+}
+
+func TestRolloutPlan_Revision(t *testing.T) {
+	newInstance := func(name string, created time.Time) *unstructured.Unstructured {
+		inst := &unstructured.Unstructured{}
+		inst.SetNamespace("default")
+		inst.SetName(name)
+		inst.SetCreationTimestamp(metav1.NewTime(created))
+		return inst
+	}
+	updated := newInstance("updated", rolloutStart.Add(-time.Hour))
+	pending := newInstance("pending", rolloutStart.Add(-time.Hour))
+	created := newInstance("created", rolloutStart.Add(time.Minute))
+
+	plan := &rolloutPlan{}
+	status := newProgressingStatus(1, rolloutStart)
+	plan.set(status, map[types.NamespacedName]struct{}{instanceKey(updated): {}})
+
+	assert.Equal(t, int64(2), plan.Revision(updated))
+	assert.Equal(t, int64(1), plan.Revision(pending))
+	assert.Equal(t, int64(2), plan.Revision(created))
+
+	status.Phase = v1alpha1.RolloutPhaseRolledBack
+	plan.set(status, nil)
+	assert.Equal(t, int64(1), plan.Revision(updated))
+	assert.Equal(t, int64(1), plan.Revision(created))
+
+	status.Phase = v1alpha1.RolloutPhaseCompleted
+	plan.set(status, nil)
+	assert.Equal(t, int64(2), plan.Revision(pending))
+}
+
+func TestNewRolloutInstances(t *testing.T) {
+	created := metav1.NewTime(time.Now())
+	deleted := metav1.NewTime(time.Now())
+	policy := &v1alpha1.RolloutPolicy{Canary: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}}
+	items := []metav1.Object{
+		&metav1.ObjectMeta{Namespace: "default", Name: "ready", CreationTimestamp: created,
+			Labels: map[string]string{metadata.ReadyGraphRevisionLabel: "2"}},
+		&metav1.ObjectMeta{Namespace: "default", Name: "stable",
+			Labels: map[string]string{metadata.ReadyGraphRevisionLabel: "1"}},
+		&metav1.ObjectMeta{Namespace: "default", Name: "canary",
+			Labels: map[string]string{"canary": "true"}},
+		&metav1.ObjectMeta{Namespace: "default", Name: "deleted", DeletionTimestamp: &deleted},
+	}
+
+	instances, err := newRolloutInstances(policy, items, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []rolloutInstance{
+		{key: types.NamespacedName{Namespace: "default", Name: "ready"}, created: created.Time, ready: true},
+		{key: types.NamespacedName{Namespace: "default", Name: "stable"}},
+		{key: types.NamespacedName{Namespace: "default", Name: "canary"}, canary: true},
+	}, instances)
+}
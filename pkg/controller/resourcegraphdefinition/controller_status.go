@@ -60,6 +60,7 @@ func (r *ResourceGraphDefinitionReconciler) updateStatus(
 		dc.Status.State = o.Status.State
 		dc.Status.TopologicalOrder = topologicalOrder
 		dc.Status.Resources = resources
+		dc.Status.Rollout = o.Status.Rollout
 
 		log.V(1).Info("updating resource graph definition status",
 			"state", dc.Status.State,
@@ -152,6 +153,12 @@ func (m *ConditionsMarker) KindUnready(msg string) {
 	m.cs.SetFalse(KindReady, "Failed", msg)
 }
 
+// KindBreakingChanges signals the CustomResourceDefinition update was blocked because it
+// would break existing instances or clients.
+func (m *ConditionsMarker) KindBreakingChanges(msg string) {
+	m.cs.SetFalse(KindReady, "BreakingChanges", msg)
+}
+
 // TODO: it would be nice to know if the Kind was not accepted at all OR if a CRD exists.
 
 // KindReady signals the CustomResourceDefinition has been synced and is ready.
@@ -0,0 +1,99 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraphdefinition
+
+import (
+	"time"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/config"
+	"github.com/kubernetes-sigs/kro/pkg/dynamiccontroller"
+)
+
+// defaultRequeueDuration is the interval at which instances waiting for
+// their resources to become ready are reconciled again, unless configured.
+const defaultRequeueDuration = 3 * time.Second
+
+// ConfigSource returns the current configuration of the controller.
+type ConfigSource interface {
+	Current() *config.KroControllerConfiguration
+}
+
+// instanceTuning describes how the instances of a ResourceGraphDefinition are
+// reconciled.
+type instanceTuning struct {
+	dynamiccontroller.Tuning
+	// requeueDuration is the interval at which instances waiting for their
+	// resources to become ready are reconciled again.
+	requeueDuration time.Duration
+}
+
+// instanceTuning returns how the instances of a ResourceGraphDefinition are
+// reconciled. The overrides of the configuration take precedence over the
+// reconcile policy of the ResourceGraphDefinition, which takes precedence over
+// the defaults of the configuration.
+func (r *ResourceGraphDefinitionReconciler) instanceTuning(rgd *v1alpha1.ResourceGraphDefinition) instanceTuning {
+	tuning := instanceTuning{requeueDuration: defaultRequeueDuration}
+	var cfg *config.KroControllerConfiguration
+	if r.configSource != nil {
+		cfg = r.configSource.Current()
+		if d := cfg.DynamicController.RequeueDuration.Duration; d > 0 {
+			tuning.requeueDuration = d
+		}
+	}
+
+	if policy := rgd.Spec.Reconcile; policy != nil {
+		if policy.ResyncPeriod != nil {
+			tuning.ResyncPeriod = policy.ResyncPeriod.Duration
+		}
+		if policy.ReadinessPollInterval != nil && policy.ReadinessPollInterval.Duration > 0 {
+			tuning.requeueDuration = policy.ReadinessPollInterval.Duration
+		}
+		if backoff := policy.RetryBackoff; backoff != nil {
+			if backoff.MinDelay != nil {
+				tuning.MinRetryDelay = backoff.MinDelay.Duration
+			}
+			if backoff.MaxDelay != nil {
+				tuning.MaxRetryDelay = backoff.MaxDelay.Duration
+			}
+		}
+		if policy.Workers != nil {
+			tuning.Workers = int(*policy.Workers)
+		}
+	}
+
+	if cfg == nil {
+		return tuning
+	}
+	o := cfg.ResourceGraphDefinition(rgd.Name)
+	if o.RequeueDuration.Duration > 0 {
+		tuning.requeueDuration = o.RequeueDuration.Duration
+	}
+	if o.Workers > 0 {
+		tuning.Workers = o.Workers
+	}
+	if o.ResyncPeriod.Duration > 0 {
+		tuning.ResyncPeriod = o.ResyncPeriod.Duration
+	}
+	if o.RateLimiter.MinDelay.Duration > 0 {
+		tuning.MinRetryDelay = o.RateLimiter.MinDelay.Duration
+	}
+	if o.RateLimiter.MaxDelay.Duration > 0 {
+		tuning.MaxRetryDelay = o.RateLimiter.MaxDelay.Duration
+	}
+	tuning.RateLimit = o.RateLimiter.RateLimit
+	tuning.BurstLimit = o.RateLimiter.BurstLimit
+	return tuning
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraphdefinition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/config"
+	"github.com/kubernetes-sigs/kro/pkg/dynamiccontroller"
+)
+
+type staticConfig struct {
+	cfg *config.KroControllerConfiguration
+}
+
+func (s staticConfig) Current() *config.KroControllerConfiguration { return s.cfg }
+
+func TestInstanceTuning(t *testing.T) {
+	rgd := func(name string) *v1alpha1.ResourceGraphDefinition {
+		return &v1alpha1.ResourceGraphDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	r := &ResourceGraphDefinitionReconciler{}
+	assert.Equal(t, instanceTuning{requeueDuration: defaultRequeueDuration}, r.instanceTuning(rgd("app")))
+
+	r.configSource = staticConfig{cfg: &config.KroControllerConfiguration{
+		DynamicController: config.DynamicControllerConfiguration{
+			RequeueDuration: metav1.Duration{Duration: 5 * time.Second},
+		},
+		ResourceGraphDefinitions: []config.ResourceGraphDefinitionOverrides{{
+			Name:            "cloud",
+			Workers:         2,
+			ResyncPeriod:    metav1.Duration{Duration: 30 * time.Minute},
+			RequeueDuration: metav1.Duration{Duration: time.Minute},
+			RateLimiter:     config.RateLimiterConfiguration{MaxDelay: metav1.Duration{Duration: 10 * time.Minute}},
+		}},
+	}}
+	assert.Equal(t, instanceTuning{requeueDuration: 5 * time.Second}, r.instanceTuning(rgd("app")))
+	assert.Equal(t, instanceTuning{
+		Tuning: dynamiccontroller.Tuning{
+			Workers:       2,
+			ResyncPeriod:  30 * time.Minute,
+			MaxRetryDelay: 10 * time.Minute,
+		},
+		requeueDuration: time.Minute,
+	}, r.instanceTuning(rgd("cloud")))
+}
+
+func TestInstanceTuning_ReconcilePolicy(t *testing.T) {
+	rgd := func(name string) *v1alpha1.ResourceGraphDefinition {
+		return &v1alpha1.ResourceGraphDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.ResourceGraphDefinitionSpec{
+				Reconcile: &v1alpha1.ReconcilePolicy{
+					ResyncPeriod:          &metav1.Duration{Duration: time.Hour},
+					ReadinessPollInterval: &metav1.Duration{Duration: 30 * time.Second},
+					RetryBackoff: &v1alpha1.RetryBackoff{
+						MinDelay: &metav1.Duration{Duration: 5 * time.Second},
+						MaxDelay: &metav1.Duration{Duration: 20 * time.Minute},
+					},
+					Workers: ptr.To[int32](3),
+				},
+			},
+		}
+	}
+
+	r := &ResourceGraphDefinitionReconciler{}
+	assert.Equal(t, instanceTuning{
+		Tuning: dynamiccontroller.Tuning{
+			Workers:       3,
+			ResyncPeriod:  time.Hour,
+			MinRetryDelay: 5 * time.Second,
+			MaxRetryDelay: 20 * time.Minute,
+		},
+		requeueDuration: 30 * time.Second,
+	}, r.instanceTuning(rgd("cloud")))
+
+	// The overrides of the configuration take precedence.
+	r.configSource = staticConfig{cfg: &config.KroControllerConfiguration{
+		ResourceGraphDefinitions: []config.ResourceGraphDefinitionOverrides{{
+			Name:        "cloud",
+			Workers:     1,
+			RateLimiter: config.RateLimiterConfiguration{MaxDelay: metav1.Duration{Duration: 10 * time.Minute}},
+		}},
+	}}
+	assert.Equal(t, instanceTuning{
+		Tuning: dynamiccontroller.Tuning{
+			Workers:       1,
+			ResyncPeriod:  time.Hour,
+			MinRetryDelay: 5 * time.Second,
+			MaxRetryDelay: 10 * time.Minute,
+		},
+		requeueDuration: 30 * time.Second,
+	}, r.instanceTuning(rgd("cloud")))
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraphdefinition
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlrtcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/admission"
+	"github.com/kubernetes-sigs/kro/pkg/conversion"
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+)
+
+// WebhookReconciler registers the graph of every ResourceGraphDefinition with
+// the webhooks served by the controller. The API server sends webhook requests
+// to any replica, so unlike ResourceGraphDefinitionReconciler, which only runs
+// on the leader, it runs on every replica. It never writes to the cluster.
+type WebhookReconciler struct {
+	client.Client
+
+	rgBuilder graphBuilder
+	// conversionWebhook serves conversions for multi-version instance CRDs.
+	// It may be nil.
+	conversionWebhook *conversion.Webhook
+	// instanceValidator validates instances at admission. It may be nil.
+	instanceValidator *admission.InstanceValidator
+
+	mu sync.Mutex
+	// groupKinds holds the instance kind registered for each
+	// ResourceGraphDefinition, keyed by name, so that it can be deregistered
+	// once the ResourceGraphDefinition is gone.
+	groupKinds map[string]schema.GroupKind
+}
+
+// NewWebhookReconciler creates a reconciler registering graphs with the given
+// webhooks, which may be nil.
+func NewWebhookReconciler(
+	builder *graph.Builder,
+	conversionWebhook *conversion.Webhook,
+	instanceValidator *admission.InstanceValidator,
+) *WebhookReconciler {
+	return &WebhookReconciler{
+		rgBuilder:         builder,
+		conversionWebhook: conversionWebhook,
+		instanceValidator: instanceValidator,
+		groupKinds:        make(map[string]schema.GroupKind),
+	}
+}
+
+// SetupWithManager sets up the reconciler with the Manager.
+func (r *WebhookReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+
+	logConstructor := func(req *reconcile.Request) logr.Logger {
+		log := mgr.GetLogger().WithName("rgd-webhooks").WithValues(
+			"controller", "ResourceGraphDefinitionWebhooks",
+			"controllerGroup", v1alpha1.GroupVersion.Group,
+			"controllerKind", "ResourceGraphDefinition",
+		)
+		if req != nil {
+			log = log.WithValues("name", req.Name)
+		}
+		return log
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("ResourceGraphDefinitionWebhooks").
+		For(&v1alpha1.ResourceGraphDefinition{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithOptions(ctrlrtcontroller.Options{
+			LogConstructor:     logConstructor,
+			NeedLeaderElection: ptr.To(false),
+		}).
+		Complete(r)
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *WebhookReconciler) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
+	rgd := &v1alpha1.ResourceGraphDefinition{}
+	if err := r.Get(ctx, req.NamespacedName, rgd); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.deregister(req.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !rgd.DeletionTimestamp.IsZero() || rgd.Spec.Schema == nil {
+		r.deregister(req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// Keep serving the previous graph until the new one builds: the
+	// ResourceGraphDefinitionReconciler reports why it does not.
+	processedRGD, err := r.rgBuilder.NewResourceGraphDefinition(rgd)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to build resource graph definition: %w", err)
+	}
+	r.register(rgd.Name, processedRGD)
+	return ctrl.Result{}, nil
+}
+
+// register registers the graph of the named ResourceGraphDefinition with the
+// webhooks, replacing the one registered before.
+func (r *WebhookReconciler) register(rgdName string, processedRGD *graph.Graph) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	gk := schema.GroupKind{Group: processedRGD.CRD.Spec.Group, Kind: processedRGD.CRD.Spec.Names.Kind}
+	if prev, ok := r.groupKinds[rgdName]; ok && prev != gk {
+		r.deregisterLocked(prev)
+	}
+	r.groupKinds[rgdName] = gk
+
+	if r.conversionWebhook != nil {
+		if processedRGD.Converter != nil {
+			r.conversionWebhook.Register(processedRGD.Converter)
+		} else {
+			r.conversionWebhook.Deregister(gk)
+		}
+	}
+	if r.instanceValidator != nil {
+		r.instanceValidator.Register(processedRGD)
+	}
+}
+
+// deregister stops serving the instance kind of the named
+// ResourceGraphDefinition.
+func (r *WebhookReconciler) deregister(rgdName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if gk, ok := r.groupKinds[rgdName]; ok {
+		r.deregisterLocked(gk)
+		delete(r.groupKinds, rgdName)
+	}
+}
+
+// deregisterLocked must be called with r.mu held.
+func (r *WebhookReconciler) deregisterLocked(gk schema.GroupKind) {
+	if r.conversionWebhook != nil {
+		r.conversionWebhook.Deregister(gk)
+	}
+	if r.instanceValidator != nil {
+		r.instanceValidator.Deregister(gk)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegraphdefinition
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/admission"
+	"github.com/kubernetes-sigs/kro/pkg/conversion"
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+)
+
+// versionedGraphBuilder builds graphs converting between the versions of the
+// ResourceGraphDefinition.
+type versionedGraphBuilder struct{}
+
+func (versionedGraphBuilder) NewResourceGraphDefinition(rgd *v1alpha1.ResourceGraphDefinition) (*graph.Graph, error) {
+	gk := schema.GroupKind{Group: rgd.Spec.Schema.Group, Kind: rgd.Spec.Schema.Kind}
+	g := &graph.Graph{CRD: &extv1.CustomResourceDefinition{Spec: extv1.CustomResourceDefinitionSpec{
+		Group: gk.Group,
+		Names: extv1.CustomResourceDefinitionNames{Kind: gk.Kind},
+	}}}
+	if len(rgd.Spec.Schema.Versions) > 0 {
+		converter, err := conversion.NewConverter(gk, rgd.Spec.Schema.APIVersion, rgd.Spec.Schema.Versions)
+		if err != nil {
+			return nil, err
+		}
+		g.Converter = converter
+	}
+	return g, nil
+}
+
+// convertsWebApps returns true if the webhook converts WebApps to v1.
+func convertsWebApps(t *testing.T, webhook *conversion.Webhook) bool {
+	t.Helper()
+	body, err := json.Marshal(&extv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request: &extv1.ConversionRequest{
+			UID:               "uid",
+			DesiredAPIVersion: "kro.run/v1",
+			Objects: []runtime.RawExtension{{Raw: []byte(
+				`{"apiVersion":"kro.run/v1alpha1","kind":"WebApp","metadata":{"name":"app"}}`,
+			)}},
+		},
+	})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	webhook.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, conversion.WebhookPath, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	review := &extv1.ConversionReview{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), review))
+	return review.Response.Result.Status == metav1.StatusSuccess
+}
+
+func TestWebhookReconciler(t *testing.T) {
+	ctx := t.Context()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	rgd := &v1alpha1.ResourceGraphDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapp"},
+		Spec: v1alpha1.ResourceGraphDefinitionSpec{Schema: &v1alpha1.Schema{
+			Group:      "kro.run",
+			Kind:       "WebApp",
+			APIVersion: "v1",
+			Versions:   []v1alpha1.SchemaVersion{{Name: "v1alpha1"}},
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rgd).Build()
+	webhook := conversion.NewWebhook(logr.Discard(), extv1.WebhookClientConfig{})
+	validator := admission.NewInstanceValidator()
+	r := &WebhookReconciler{
+		Client:            c,
+		rgBuilder:         versionedGraphBuilder{},
+		conversionWebhook: webhook,
+		instanceValidator: validator,
+		groupKinds:        make(map[string]schema.GroupKind),
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "webapp"}}
+
+	assert.False(t, convertsWebApps(t, webhook))
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, convertsWebApps(t, webhook))
+
+	// Dropping the additional versions stops serving conversions.
+	rgd.Spec.Schema.Versions = nil
+	require.NoError(t, c.Update(ctx, rgd))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, convertsWebApps(t, webhook))
+
+	rgd.Spec.Schema.Versions = []v1alpha1.SchemaVersion{{Name: "v1alpha1"}}
+	require.NoError(t, c.Update(ctx, rgd))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, convertsWebApps(t, webhook))
+
+	require.NoError(t, c.Delete(ctx, rgd))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, convertsWebApps(t, webhook))
+	assert.Empty(t, r.groupKinds)
+}
@@ -0,0 +1,290 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	krocel "github.com/kubernetes-sigs/kro/pkg/cel"
+	"github.com/kubernetes-sigs/kro/pkg/graph/fieldpath"
+)
+
+// SelfVarName is the name of the CEL variable holding the object being
+// converted in conversion field mappings.
+const SelfVarName = "self"
+
+// Converter converts instances of a single kind between the versions declared
+// in a ResourceGraphDefinition. Every conversion goes through the storage
+// version: an object is first converted to the storage version, then from the
+// storage version to the requested version.
+type Converter struct {
+	groupKind      schema.GroupKind
+	storageVersion string
+	versions       map[string]*versionMappings
+}
+
+// versionMappings holds the compiled mappings of a non-storage version.
+type versionMappings struct {
+	toStorage   []*fieldMapping
+	fromStorage []*fieldMapping
+}
+
+// fieldMapping is a single compiled "path: expression" mapping.
+type fieldMapping struct {
+	path       string
+	fields     []string
+	expression string
+	program    cel.Program
+}
+
+// NewConverter compiles the conversion mappings declared on the given versions.
+// storageVersion is the version instances are persisted (and reconciled) in.
+func NewConverter(groupKind schema.GroupKind, storageVersion string, versions []v1alpha1.SchemaVersion) (*Converter, error) {
+	env, err := krocel.UntypedEnvironment([]string{SelfVarName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	c := &Converter{
+		groupKind:      groupKind,
+		storageVersion: storageVersion,
+		versions:       make(map[string]*versionMappings, len(versions)),
+	}
+	for _, version := range versions {
+		if version.Name == storageVersion {
+			return nil, fmt.Errorf("version %q is the storage version and cannot declare conversion mappings", version.Name)
+		}
+		if _, ok := c.versions[version.Name]; ok {
+			return nil, fmt.Errorf("duplicate version %q", version.Name)
+		}
+
+		vm := &versionMappings{}
+		if version.Conversion != nil {
+			if vm.toStorage, err = compileMappings(env, version.Conversion.ToStorage); err != nil {
+				return nil, fmt.Errorf("version %q: toStorage: %w", version.Name, err)
+			}
+			if vm.fromStorage, err = compileMappings(env, version.Conversion.FromStorage); err != nil {
+				return nil, fmt.Errorf("version %q: fromStorage: %w", version.Name, err)
+			}
+		}
+		c.versions[version.Name] = vm
+	}
+	return c, nil
+}
+
+// GroupKind returns the group and kind handled by this converter.
+func (c *Converter) GroupKind() schema.GroupKind {
+	return c.groupKind
+}
+
+// StorageVersion returns the storage version of the kind.
+func (c *Converter) StorageVersion() string {
+	return c.storageVersion
+}
+
+// Versions returns all the versions known to the converter, storage
+// version included, sorted by name.
+func (c *Converter) Versions() []string {
+	versions := []string{c.storageVersion}
+	for name := range c.versions {
+		versions = append(versions, name)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// Convert returns a copy of obj converted to toVersion. The input object is
+// never modified.
+func (c *Converter) Convert(obj *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, error) {
+	gv, err := schema.ParseGroupVersion(obj.GetAPIVersion())
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion %q: %w", obj.GetAPIVersion(), err)
+	}
+	if gv.Group != c.groupKind.Group || obj.GetKind() != c.groupKind.Kind {
+		return nil, fmt.Errorf("cannot convert %s, converter handles %s", obj.GroupVersionKind().GroupKind(), c.groupKind)
+	}
+	if !c.knows(gv.Version) {
+		return nil, fmt.Errorf("unknown source version %q", gv.Version)
+	}
+	if !c.knows(toVersion) {
+		return nil, fmt.Errorf("unknown target version %q", toVersion)
+	}
+
+	out := obj.DeepCopy()
+	if gv.Version == toVersion {
+		return out, nil
+	}
+
+	// source -> storage
+	if gv.Version != c.storageVersion {
+		if out, err = applyMappings(out, c.versions[gv.Version].toStorage); err != nil {
+			return nil, fmt.Errorf("failed to convert %s to storage version %s: %w", gv.Version, c.storageVersion, err)
+		}
+	}
+	// storage -> target
+	if toVersion != c.storageVersion {
+		if out, err = applyMappings(out, c.versions[toVersion].fromStorage); err != nil {
+			return nil, fmt.Errorf("failed to convert storage version %s to %s: %w", c.storageVersion, toVersion, err)
+		}
+	}
+
+	out.SetAPIVersion(schema.GroupVersion{Group: c.groupKind.Group, Version: toVersion}.String())
+	return out, nil
+}
+
+func (c *Converter) knows(version string) bool {
+	if version == c.storageVersion {
+		return true
+	}
+	_, ok := c.versions[version]
+	return ok
+}
+
+// compileMappings parses the mapping paths and compiles the CEL expressions.
+// Mappings are returned sorted by path so conversions are deterministic.
+func compileMappings(env *cel.Env, mappings map[string]string) ([]*fieldMapping, error) {
+	paths := make([]string, 0, len(mappings))
+	for path := range mappings {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	compiled := make([]*fieldMapping, 0, len(paths))
+	for _, path := range paths {
+		fields, err := ParseMappingPath(path)
+		if err != nil {
+			return nil, err
+		}
+		expression := mappings[path]
+		ast, issues := env.Compile(expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile expression %q for %q: %w", expression, path, issues.Err())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create program for %q: %w", path, err)
+		}
+		compiled = append(compiled, &fieldMapping{
+			path:       path,
+			fields:     fields,
+			expression: expression,
+			program:    program,
+		})
+	}
+	return compiled, nil
+}
+
+// ParseMappingPath parses a conversion mapping target path, and returns its
+// field names. Mapping targets must live under spec or metadata.labels and
+// metadata.annotations, and cannot index into lists.
+func ParseMappingPath(path string) ([]string, error) {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mapping path %q: %w", path, err)
+	}
+	fields := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment.Index != -1 {
+			return nil, fmt.Errorf("invalid mapping path %q: list indexes are not supported", path)
+		}
+		fields = append(fields, segment.Name)
+	}
+	switch {
+	case len(fields) >= 2 && fields[0] == "spec":
+	case len(fields) == 3 && fields[0] == "metadata" && (fields[1] == "labels" || fields[1] == "annotations"):
+	default:
+		return nil, fmt.Errorf(
+			"invalid mapping path %q: must be a field under spec, metadata.labels or metadata.annotations",
+			path,
+		)
+	}
+	return fields, nil
+}
+
+// applyMappings evaluates the mappings against obj and writes the results into
+// a copy of obj. All expressions see the original object, so mappings do not
+// observe each other's writes.
+func applyMappings(obj *unstructured.Unstructured, mappings []*fieldMapping) (*unstructured.Unstructured, error) {
+	out := obj.DeepCopy()
+	activation := map[string]any{SelfVarName: obj.Object}
+	for _, m := range mappings {
+		val, _, err := m.program.Eval(activation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate %q for %q: %w", m.expression, m.path, err)
+		}
+		native, err := krocel.GoNativeType(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert result of %q for %q: %w", m.expression, m.path, err)
+		}
+		if native == nil {
+			unstructured.RemoveNestedField(out.Object, m.fields...)
+			continue
+		}
+		value, err := toUnstructuredValue(native)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert result of %q for %q: %w", m.expression, m.path, err)
+		}
+		if err := unstructured.SetNestedField(out.Object, value, m.fields...); err != nil {
+			return nil, fmt.Errorf("failed to set %q: %w", strings.Join(m.fields, "."), err)
+		}
+	}
+	return out, nil
+}
+
+// toUnstructuredValue normalizes a CEL native value (which may contain
+// durations, timestamps or bytes) into a JSON compatible value.
+func toUnstructuredValue(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	// json.Unmarshal decodes every number as float64, put integers back
+	// to int64 so they round trip through unstructured.
+	return normalizeNumbers(out), nil
+}
+
+func normalizeNumbers(v any) any {
+	switch t := v.(type) {
+	case float64:
+		if t == float64(int64(t)) {
+			return int64(t)
+		}
+		return t
+	case map[string]any:
+		for k, e := range t {
+			t[k] = normalizeNumbers(e)
+		}
+		return t
+	case []any:
+		for i, e := range t {
+			t[i] = normalizeNumbers(e)
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+)
+
+var testGroupKind = schema.GroupKind{Group: "kro.run", Kind: "WebApp"}
+
+func newTestConverter(t *testing.T) *Converter {
+	t.Helper()
+	c, err := NewConverter(testGroupKind, "v1", []v1alpha1.SchemaVersion{
+		{
+			Name: "v1alpha1",
+			Conversion: &v1alpha1.VersionConversion{
+				ToStorage: map[string]string{
+					"spec.replicas":               "self.spec.size",
+					"spec.image":                  "self.spec.repository + ':' + self.spec.tag",
+					"metadata.labels.migrated-by": "'kro'",
+				},
+				FromStorage: map[string]string{
+					"spec.size":       "self.spec.replicas",
+					"spec.repository": "self.spec.image.split(':')[0]",
+					"spec.tag":        "self.spec.image.split(':')[1]",
+					"spec.replicas":   "null",
+					"spec.image":      "null",
+				},
+			},
+		},
+		{
+			Name: "v1beta1",
+		},
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func newObject(version string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kro.run/" + version,
+		"kind":       "WebApp",
+		"metadata": map[string]interface{}{
+			"name":      "app",
+			"namespace": "default",
+		},
+		"spec": spec,
+		"status": map[string]interface{}{
+			"state": "ACTIVE",
+		},
+	}}
+}
+
+func TestConverter_Convert(t *testing.T) {
+	c := newTestConverter(t)
+
+	tests := []struct {
+		name      string
+		obj       *unstructured.Unstructured
+		toVersion string
+		want      *unstructured.Unstructured
+		wantErr   string
+	}{
+		{
+			name:      "same version is a copy",
+			obj:       newObject("v1", map[string]interface{}{"replicas": int64(2)}),
+			toVersion: "v1",
+			want:      newObject("v1", map[string]interface{}{"replicas": int64(2)}),
+		},
+		{
+			name: "to storage version",
+			obj: newObject("v1alpha1", map[string]interface{}{
+				"size": int64(3), "repository": "nginx", "tag": "1.27",
+			}),
+			toVersion: "v1",
+			want: func() *unstructured.Unstructured {
+				obj := newObject("v1", map[string]interface{}{
+					"size": int64(3), "repository": "nginx", "tag": "1.27",
+					"replicas": int64(3), "image": "nginx:1.27",
+				})
+				obj.SetLabels(map[string]string{"migrated-by": "kro"})
+				return obj
+			}(),
+		},
+		{
+			name: "from storage version removes fields mapped to null",
+			obj: newObject("v1", map[string]interface{}{
+				"replicas": int64(3), "image": "nginx:1.27",
+			}),
+			toVersion: "v1alpha1",
+			want: newObject("v1alpha1", map[string]interface{}{
+				"size": int64(3), "repository": "nginx", "tag": "1.27",
+			}),
+		},
+		{
+			name:      "between two non storage versions without mappings",
+			obj:       newObject("v1beta1", map[string]interface{}{"replicas": int64(1), "image": "a:b"}),
+			toVersion: "v1alpha1",
+			want: newObject("v1alpha1", map[string]interface{}{
+				"size": int64(1), "repository": "a", "tag": "b",
+			}),
+		},
+		{
+			name:      "unknown target version",
+			obj:       newObject("v1", map[string]interface{}{}),
+			toVersion: "v2",
+			wantErr:   `unknown target version "v2"`,
+		},
+		{
+			name:      "unknown source version",
+			obj:       newObject("v0", map[string]interface{}{}),
+			toVersion: "v1",
+			wantErr:   `unknown source version "v0"`,
+		},
+		{
+			name:      "evaluation error",
+			obj:       newObject("v1alpha1", map[string]interface{}{"size": int64(1)}),
+			toVersion: "v1",
+			wantErr:   "failed to convert v1alpha1 to storage version v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.obj.DeepCopy()
+			got, err := c.Convert(tt.obj, tt.toVersion)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Object, got.Object)
+			assert.Equal(t, original, tt.obj, "input object must not be modified")
+		})
+	}
+}
+
+func TestNewConverter_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []v1alpha1.SchemaVersion
+		wantErr  string
+	}{
+		{
+			name:     "storage version redeclared",
+			versions: []v1alpha1.SchemaVersion{{Name: "v1"}},
+			wantErr:  "is the storage version",
+		},
+		{
+			name:     "duplicate version",
+			versions: []v1alpha1.SchemaVersion{{Name: "v2"}, {Name: "v2"}},
+			wantErr:  `duplicate version "v2"`,
+		},
+		{
+			name: "invalid expression",
+			versions: []v1alpha1.SchemaVersion{{
+				Name: "v2",
+				Conversion: &v1alpha1.VersionConversion{
+					ToStorage: map[string]string{"spec.a": "self.spec.("},
+				},
+			}},
+			wantErr: "failed to compile expression",
+		},
+		{
+			name: "indexed path",
+			versions: []v1alpha1.SchemaVersion{{
+				Name: "v2",
+				Conversion: &v1alpha1.VersionConversion{
+					FromStorage: map[string]string{"spec.items[0]": "1"},
+				},
+			}},
+			wantErr: "list indexes are not supported",
+		},
+		{
+			name: "path outside of spec",
+			versions: []v1alpha1.SchemaVersion{{
+				Name: "v2",
+				Conversion: &v1alpha1.VersionConversion{
+					FromStorage: map[string]string{"metadata.name": "'x'"},
+				},
+			}},
+			wantErr: "must be a field under spec",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConverter(testGroupKind, "v1", tt.versions)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestConverter_Versions(t *testing.T) {
+	c := newTestConverter(t)
+	assert.Equal(t, []string{"v1", "v1alpha1", "v1beta1"}, c.Versions())
+	assert.Equal(t, "v1", c.StorageVersion())
+	assert.Equal(t, testGroupKind, c.GroupKind())
+}
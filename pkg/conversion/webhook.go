@@ -0,0 +1,176 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/go-logr/logr"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// WebhookPath is the path the conversion webhook is served on.
+	WebhookPath = "/kro/convert"
+
+	// maxRequestBodyBytes bounds the size of ConversionReview requests. The
+	// API server sends at most one list page worth of objects.
+	maxRequestBodyBytes = 32 << 20
+)
+
+// Webhook serves apiextensions.k8s.io/v1 ConversionReview requests for every
+// kind registered with it. A single webhook instance is shared by all the
+// multi-version CRDs generated by kro.
+type Webhook struct {
+	log          logr.Logger
+	clientConfig extv1.WebhookClientConfig
+
+	mu         sync.RWMutex
+	converters map[schema.GroupKind]*Converter
+}
+
+var _ http.Handler = (*Webhook)(nil)
+
+// NewWebhook creates a new conversion webhook. clientConfig is how the API
+// server reaches the webhook, it is injected into every generated CRD that
+// declares more than one version.
+func NewWebhook(log logr.Logger, clientConfig extv1.WebhookClientConfig) *Webhook {
+	return &Webhook{
+		log:          log.WithName("conversion-webhook"),
+		clientConfig: clientConfig,
+		converters:   make(map[schema.GroupKind]*Converter),
+	}
+}
+
+// ClientConfig returns a copy of the webhook client configuration.
+func (w *Webhook) ClientConfig() *extv1.WebhookClientConfig {
+	return w.clientConfig.DeepCopy()
+}
+
+// Register registers (or replaces) the converter for its group kind.
+func (w *Webhook) Register(c *Converter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.converters[c.GroupKind()] = c
+}
+
+// Deregister removes the converter for the given group kind, if any.
+func (w *Webhook) Deregister(gk schema.GroupKind) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.converters, gk)
+}
+
+func (w *Webhook) converterFor(gk schema.GroupKind) (*Converter, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	c, ok := w.converters[gk]
+	return c, ok
+}
+
+// ServeHTTP implements http.Handler.
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestBodyBytes))
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review := &extv1.ConversionReview{}
+	if err := json.Unmarshal(body, review); err != nil {
+		http.Error(rw, fmt.Sprintf("failed to decode ConversionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(rw, "ConversionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = w.convert(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(review); err != nil {
+		w.log.Error(err, "failed to write ConversionReview response")
+	}
+}
+
+// convert converts all the objects in the request to the desired version.
+// Conversion is all or nothing: the API server rejects partial responses.
+func (w *Webhook) convert(req *extv1.ConversionRequest) *extv1.ConversionResponse {
+	desired, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err != nil {
+		return failedResponse(fmt.Errorf("invalid desired apiVersion %q: %w", req.DesiredAPIVersion, err))
+	}
+
+	converted := make([]runtime.RawExtension, 0, len(req.Objects))
+	for i, raw := range req.Objects {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			return failedResponse(fmt.Errorf("failed to decode object %d: %w", i, err))
+		}
+
+		gk := obj.GroupVersionKind().GroupKind()
+		if gk.Group != desired.Group {
+			return failedResponse(fmt.Errorf("cannot convert %s to group %q", gk, desired.Group))
+		}
+		converter, ok := w.converterFor(gk)
+		if !ok {
+			return failedResponse(fmt.Errorf("no converter registered for %s", gk))
+		}
+
+		out, err := converter.Convert(obj, desired.Version)
+		if err != nil {
+			w.log.V(1).Info("conversion failed", "groupKind", gk, "name", obj.GetName(),
+				"namespace", obj.GetNamespace(), "desiredAPIVersion", req.DesiredAPIVersion, "error", err.Error())
+			return failedResponse(fmt.Errorf("failed to convert %s %s/%s: %w",
+				gk, obj.GetNamespace(), obj.GetName(), err))
+		}
+
+		data, err := out.MarshalJSON()
+		if err != nil {
+			return failedResponse(fmt.Errorf("failed to encode object %d: %w", i, err))
+		}
+		converted = append(converted, runtime.RawExtension{Raw: data})
+	}
+
+	return &extv1.ConversionResponse{
+		ConvertedObjects: converted,
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+}
+
+func failedResponse(err error) *extv1.ConversionResponse {
+	return &extv1.ConversionResponse{
+		Result: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+		},
+	}
+}
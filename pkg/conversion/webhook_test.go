@@ -0,0 +1,138 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// sendReview plays the role of the API server: it posts a ConversionReview to
+// the webhook and decodes the response.
+func sendReview(t *testing.T, url, desiredAPIVersion string, objs ...*unstructured.Unstructured) *extv1.ConversionReview {
+	t.Helper()
+
+	raws := make([]runtime.RawExtension, 0, len(objs))
+	for _, obj := range objs {
+		data, err := obj.MarshalJSON()
+		require.NoError(t, err)
+		raws = append(raws, runtime.RawExtension{Raw: data})
+	}
+	review := &extv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request: &extv1.ConversionRequest{
+			UID:               types.UID("review-uid"),
+			DesiredAPIVersion: desiredAPIVersion,
+			Objects:           raws,
+		},
+	}
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+
+	resp, err := http.Post(url+WebhookPath, "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	out := &extv1.ConversionReview{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	require.NotNil(t, out.Response)
+	assert.Equal(t, types.UID("review-uid"), out.Response.UID)
+	assert.Nil(t, out.Request)
+	return out
+}
+
+func TestWebhook_ServeHTTP(t *testing.T) {
+	webhook := NewWebhook(logr.Discard(), extv1.WebhookClientConfig{})
+	webhook.Register(newTestConverter(t))
+
+	mux := http.NewServeMux()
+	mux.Handle(WebhookPath, webhook)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("converts all objects", func(t *testing.T) {
+		review := sendReview(t, server.URL, "kro.run/v1alpha1",
+			newObject("v1", map[string]interface{}{"replicas": int64(2), "image": "nginx:1.27"}),
+			newObject("v1beta1", map[string]interface{}{"replicas": int64(4), "image": "redis:7"}),
+		)
+		require.Equal(t, metav1.StatusSuccess, review.Response.Result.Status, review.Response.Result.Message)
+		require.Len(t, review.Response.ConvertedObjects, 2)
+
+		for i, want := range []map[string]interface{}{
+			{"size": int64(2), "repository": "nginx", "tag": "1.27"},
+			{"size": int64(4), "repository": "redis", "tag": "7"},
+		} {
+			obj := &unstructured.Unstructured{}
+			require.NoError(t, obj.UnmarshalJSON(review.Response.ConvertedObjects[i].Raw))
+			assert.Equal(t, "kro.run/v1alpha1", obj.GetAPIVersion())
+			spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+			require.NoError(t, err)
+			assert.Equal(t, want, spec)
+			state, _, _ := unstructured.NestedString(obj.Object, "status", "state")
+			assert.Equal(t, "ACTIVE", state, "status is shared by all versions")
+		}
+	})
+
+	t.Run("fails the whole review on a single error", func(t *testing.T) {
+		review := sendReview(t, server.URL, "kro.run/v1",
+			newObject("v1beta1", map[string]interface{}{"replicas": int64(1)}),
+			newObject("v1alpha1", map[string]interface{}{"size": int64(1)}),
+		)
+		assert.Equal(t, metav1.StatusFailure, review.Response.Result.Status)
+		assert.Contains(t, review.Response.Result.Message, "failed to convert")
+		assert.Empty(t, review.Response.ConvertedObjects)
+	})
+
+	t.Run("unregistered kind", func(t *testing.T) {
+		obj := newObject("v1", nil)
+		obj.SetKind("Database")
+		review := sendReview(t, server.URL, "kro.run/v1alpha1", obj)
+		assert.Equal(t, metav1.StatusFailure, review.Response.Result.Status)
+		assert.Contains(t, review.Response.Result.Message, "no converter registered for Database.kro.run")
+	})
+
+	t.Run("deregistered kind", func(t *testing.T) {
+		webhook.Deregister(testGroupKind)
+		defer webhook.Register(newTestConverter(t))
+
+		review := sendReview(t, server.URL, "kro.run/v1alpha1", newObject("v1", nil))
+		assert.Equal(t, metav1.StatusFailure, review.Response.Result.Status)
+	})
+
+	t.Run("rejects malformed requests", func(t *testing.T) {
+		resp, err := http.Post(server.URL+WebhookPath, "application/json", bytes.NewBufferString("{"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = http.Get(server.URL + WebhookPath)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
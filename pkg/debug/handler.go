@@ -0,0 +1,88 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package debug serves the internals of the controller for troubleshooting.
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-logr/logr"
+
+	"github.com/kubernetes-sigs/kro/pkg/dynamiccontroller"
+)
+
+// Path is the path the debug handler is served on, on the metrics server.
+const Path = "/debug/kro"
+
+// DynamicController reports the internals of the dynamic controller.
+type DynamicController interface {
+	DebugState() dynamiccontroller.DebugState
+}
+
+// GraphSource reports the graphs compiled from ResourceGraphDefinitions.
+type GraphSource interface {
+	// TopologicalOrders returns the topological order of the compiled
+	// graphs of each ResourceGraphDefinition, by revision.
+	TopologicalOrders() map[string]map[int64][]string
+}
+
+// State is the document served by the handler.
+type State struct {
+	DynamicController dynamiccontroller.DebugState `json:"dynamicController"`
+	// TopologicalOrders holds the topological order of the compiled graphs
+	// of each ResourceGraphDefinition, by revision.
+	TopologicalOrders map[string]map[int64][]string `json:"topologicalOrders"`
+}
+
+// Handler serves the internals of the controller as JSON. It does not
+// authenticate requests: it must be wrapped by a filter that does.
+type Handler struct {
+	log    logr.Logger
+	dc     DynamicController
+	graphs GraphSource
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler creates a debug handler reporting the internals of dc and
+// graphs.
+func NewHandler(log logr.Logger, dc DynamicController, graphs GraphSource) *Handler {
+	return &Handler{
+		log:    log.WithName("debug"),
+		dc:     dc,
+		graphs: graphs,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(rw, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	state := State{
+		DynamicController: h.dc.DebugState(),
+		TopologicalOrders: h.graphs.TopologicalOrders(),
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(rw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(state); err != nil {
+		h.log.Error(err, "failed to write debug state")
+	}
+}
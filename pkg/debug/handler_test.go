@@ -0,0 +1,82 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubernetes-sigs/kro/pkg/dynamiccontroller"
+)
+
+type fakeDynamicController struct {
+	state dynamiccontroller.DebugState
+}
+
+func (f fakeDynamicController) DebugState() dynamiccontroller.DebugState { return f.state }
+
+type fakeGraphSource map[string]map[int64][]string
+
+func (f fakeGraphSource) TopologicalOrders() map[string]map[int64][]string { return f }
+
+func TestHandler(t *testing.T) {
+	dc := fakeDynamicController{state: dynamiccontroller.DebugState{
+		Registrations: []dynamiccontroller.RegistrationState{{
+			Parent:          "kro.run/v1alpha1/webapps",
+			ParentHandlerID: "parent",
+			ChildHandlerIDs: map[string]string{"apps/v1/deployments": "child"},
+			Priority:        dynamiccontroller.PriorityNormal,
+		}},
+		Watches: []dynamiccontroller.WatchState{{
+			GVR:        "kro.run/v1alpha1/webapps",
+			Namespaces: []string{""},
+			Handlers:   1,
+			Synced:     true,
+		}},
+		Queue: []dynamiccontroller.QueuedItemState{{
+			GVR:       "kro.run/v1alpha1/webapps",
+			Namespace: "default",
+			Name:      "app",
+			Retries:   2,
+			Waiting:   "1s",
+		}},
+	}}
+	graphs := fakeGraphSource{"webapp": {1: {"deployment", "service"}, 2: {"deployment"}}}
+	h := NewHandler(logr.Discard(), dc, graphs)
+
+	t.Run("get", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var got State
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, dc.state, got.DynamicController)
+		assert.Equal(t, map[string]map[int64][]string(graphs), got.TopologicalOrders)
+	})
+
+	t.Run("other methods", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// DebugState is a snapshot of the internals of the DynamicController, meant
+// to be dumped for troubleshooting. GVRs are formatted as group/version/resource.
+type DebugState struct {
+	Registrations []RegistrationState `json:"registrations"`
+	Watches       []WatchState        `json:"watches"`
+	Queue         []QueuedItemState   `json:"queue"`
+}
+
+// RegistrationState describes a registered parent GVR.
+type RegistrationState struct {
+	Parent          string `json:"parent"`
+	ParentHandlerID string `json:"parentHandlerID"`
+	// ChildHandlerIDs holds the handler ID of each child GVR watched for
+	// the parent.
+	ChildHandlerIDs map[string]string `json:"childHandlerIDs,omitempty"`
+	Priority        Priority          `json:"priority"`
+}
+
+// WatchState describes the informers watching a GVR.
+type WatchState struct {
+	GVR string `json:"gvr"`
+	// Namespaces are the namespaces watched. An empty namespace stands for
+	// every namespace.
+	Namespaces []string `json:"namespaces"`
+	Handlers   int      `json:"handlers"`
+	Synced     bool     `json:"synced"`
+}
+
+// QueuedItemState describes an instance waiting in the queue.
+type QueuedItemState struct {
+	GVR       string `json:"gvr"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Retries is the number of times the instance was requeued after a
+	// failure.
+	Retries int `json:"retries"`
+	// Waiting is how long the instance has been queued.
+	Waiting string `json:"waiting"`
+}
+
+// DebugState returns a snapshot of the registrations, watches and queued
+// instances of the controller. Instances waiting for a requeue delay to
+// elapse are not queued yet.
+func (dc *DynamicController) DebugState() DebugState {
+	state := DebugState{
+		Registrations: []RegistrationState{},
+		Watches:       []WatchState{},
+		Queue:         []QueuedItemState{},
+	}
+
+	dc.mu.Lock()
+	for parent, reg := range dc.registrations {
+		rs := RegistrationState{
+			Parent:          keyFromGVR(parent),
+			ParentHandlerID: reg.parentHandlerID,
+			Priority:        dc.priority(parent),
+		}
+		if len(reg.childHandlerIDs) > 0 {
+			rs.ChildHandlerIDs = make(map[string]string, len(reg.childHandlerIDs))
+			for child, id := range reg.childHandlerIDs {
+				rs.ChildHandlerIDs[keyFromGVR(child)] = id
+			}
+		}
+		state.Registrations = append(state.Registrations, rs)
+	}
+	for gvr, w := range dc.watches {
+		state.Watches = append(state.Watches, WatchState{
+			GVR:        keyFromGVR(gvr),
+			Namespaces: w.Namespaces(),
+			Handlers:   w.HandlerCount(),
+			Synced:     w.HasSynced(),
+		})
+	}
+	dc.mu.Unlock()
+
+	slices.SortFunc(state.Registrations, func(a, b RegistrationState) int { return strings.Compare(a.Parent, b.Parent) })
+	slices.SortFunc(state.Watches, func(a, b WatchState) int { return strings.Compare(a.GVR, b.GVR) })
+
+	now := time.Now()
+	for _, item := range dc.queued.snapshot() {
+		state.Queue = append(state.Queue, QueuedItemState{
+			GVR:       keyFromGVR(item.oi.GVR),
+			Namespace: item.oi.Namespace,
+			Name:      item.oi.Name,
+			Retries:   dc.queue.NumRequeues(item.oi),
+			Waiting:   now.Sub(item.added).Round(time.Millisecond).String(),
+		})
+	}
+	return state
+}
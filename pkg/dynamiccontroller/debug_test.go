@@ -0,0 +1,68 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+)
+
+func TestDynamicController_DebugState(t *testing.T) {
+	client, mapper := setupFakeClient(t)
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+
+	dc := NewDynamicController(noopLogger(), Config{}, client, mapper)
+	dc.ctx = t.Context() // simulate a start through dc.Run
+
+	state := dc.DebugState()
+	assert.Empty(t, state.Registrations)
+	assert.Empty(t, state.Watches)
+	assert.Empty(t, state.Queue)
+
+	handler := Handler(func(context.Context, controllerruntime.Request) error { return nil })
+	dc.SetPriority(gvr, PriorityHigh)
+	require.NoError(t, dc.Register(t.Context(), gvr, handler))
+
+	item := ObjectIdentifiers{GVR: gvr, NamespacedName: types.NamespacedName{Namespace: "default", Name: "one"}}
+	// A failed item queued again right away.
+	dc.queue.AddRateLimited(item)
+	dc.queue.Add(item)
+
+	state = dc.DebugState()
+	require.Len(t, state.Registrations, 1)
+	assert.Equal(t, "test/v1/tests", state.Registrations[0].Parent)
+	assert.NotEmpty(t, state.Registrations[0].ParentHandlerID)
+	assert.Equal(t, PriorityHigh, state.Registrations[0].Priority)
+
+	require.Len(t, state.Watches, 1)
+	assert.Equal(t, "test/v1/tests", state.Watches[0].GVR)
+	assert.Equal(t, []string{""}, state.Watches[0].Namespaces)
+	assert.Equal(t, 1, state.Watches[0].Handlers)
+	assert.True(t, state.Watches[0].Synced)
+
+	// The instances listed by Register are queued as well.
+	idx := slices.IndexFunc(state.Queue, func(q QueuedItemState) bool { return q.Name == "one" })
+	require.NotEqual(t, -1, idx)
+	assert.Equal(t, "test/v1/tests", state.Queue[idx].GVR)
+	assert.Equal(t, "default", state.Queue[idx].Namespace)
+	assert.Equal(t, 1, state.Queue[idx].Retries)
+}
@@ -61,13 +61,14 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
-	"golang.org/x/time/rate"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -114,12 +115,40 @@ type Config struct {
 	BurstLimit int
 	// QueueShutdownTimeout is the maximum time to wait for the queue to drain before shutting down.
 	QueueShutdownTimeout time.Duration
+	// Namespaces restricts the controller to the given namespaces. Namespaced
+	// resources are only watched and reconciled there, so that kro only needs
+	// namespaced permissions on them. If empty, every namespace is served.
+	Namespaces []string
+	// NamespaceSelector restricts the controller to the namespaces matching
+	// the selector, which are watched to follow changes. It is ignored if
+	// Namespaces is set.
+	NamespaceSelector labels.Selector
+	// Shard restricts the controller to the instances owned by this replica,
+	// when instances are sharded across replicas. If nil, every instance is
+	// reconciled.
+	Shard Shard
+	// ProgressDeadline is how long workers may reconcile no instance while
+	// instances are queued, and how long the watch of a registered GVR may
+	// keep failing, before HealthzCheck reports the controller unhealthy.
+	// If 0, HealthzCheck always succeeds.
+	ProgressDeadline time.Duration
+}
+
+// Shard decides which instances are reconciled by this replica.
+type Shard interface {
+	// Owns returns true if this replica reconciles the instance.
+	Owns(instance types.NamespacedName) bool
 }
 
 // Handler is used to actually perform the reconciliation logic for an instance GVR and will operate
 // on a single instance of the resource received from the queue
 type Handler func(ctx context.Context, req ctrl.Request) error
 
+// ReleaseHandler is called for the instances of a parent GVR the controller
+// stops serving, because their namespace is no longer watched or they moved
+// to another shard, so that the state kept for them can be dropped.
+type ReleaseHandler func(instance types.NamespacedName)
+
 // ObjectIdentifiers holds the key and GVR of the object to reconcile.
 type ObjectIdentifiers struct {
 	types.NamespacedName
@@ -182,11 +211,15 @@ type DynamicController struct {
 	// propagate instance events to the handlers.
 	mapper meta.RESTMapper
 
+	// namespaces holds the namespaces served by the controller. Objects in
+	// other namespaces are neither watched nor reconciled.
+	// It is guarded by its own lock.
+	namespaces *namespaceScope
 	// Map of active informers per GVR.
 	// No matter if an instance resource or a child resource is being watched,
-	// eventually, they will be handled by an internal.LazyInformer registered here.
+	// eventually, they will be handled by an internal.MultiNamespaceInformer registered here.
 	// Guarded by mu.
-	watches map[schema.GroupVersionResource]*internal.LazyInformer
+	watches map[schema.GroupVersionResource]*internal.MultiNamespaceInformer
 	// Map of parent registrations per GVR.
 	// A registration is created for each parent GVR that is being watched.
 	// Each registration contains a list of child GVRs that are being watched for the parent.
@@ -196,9 +229,41 @@ type DynamicController struct {
 
 	// handlers is a Handler collection for each parent GVR, invoked for queued objects.
 	handlers sync.Map // map[schema.GroupVersionResource]Handler (thread-safe on its own)
+	// externals holds the label selectors parents use to read external
+	// collections, so that events on selected objects enqueue the parents.
+	// It is guarded by its own lock.
+	externals *externalSelectorIndex
+	// priorities holds the Priority of each parent GVR set with SetPriority.
+	priorities sync.Map // map[schema.GroupVersionResource]Priority (thread-safe on its own)
+	// releasers holds the ReleaseHandler of each parent GVR set with
+	// SetReleaseHandler.
+	releasers sync.Map // map[schema.GroupVersionResource]ReleaseHandler (thread-safe on its own)
 	// queue is the work queue used to process items received via watches.
 	// The queue is shared between all informers and is used to propagate events to the handlers.
+	// It serves the items of each parent GVR fairly, see fairQueue.
 	queue workqueue.TypedRateLimitingInterface[ObjectIdentifiers]
+	// queued stores the items of queue, see newQueue.
+	queued *fairQueue
+	// rateLimiter rate limits the items of queue.
+	rateLimiter *gvrRateLimiter
+
+	// tuningMu guards tunings, lastResyncs, busyWorkers and the settings of
+	// config changed by Reconfigure.
+	tuningMu sync.Mutex
+	// tunings holds the Tuning of each parent GVR set with SetTuning.
+	tunings map[schema.GroupVersionResource]Tuning
+	// lastResyncs holds when the instances of each parent GVR tuned with a
+	// resync period were last resynced.
+	lastResyncs map[schema.GroupVersionResource]time.Time
+	// busyWorkers holds the number of workers reconciling instances of each
+	// parent GVR.
+	busyWorkers map[schema.GroupVersionResource]int
+
+	// started is set once Start is called.
+	started atomic.Bool
+	// lastProgress is when a worker last finished processing an item, in
+	// nanoseconds since the epoch.
+	lastProgress atomic.Int64
 }
 
 // NewDynamicController creates a new DynamicController.
@@ -210,18 +275,23 @@ func NewDynamicController(
 ) *DynamicController {
 	logger := log.WithName("dynamic-controller")
 
-	return &DynamicController{
+	dc := &DynamicController{
 		config:        config,
 		log:           logger,
 		client:        kubeClient,
 		mapper:        mapper,
-		watches:       make(map[schema.GroupVersionResource]*internal.LazyInformer),
+		namespaces:    newNamespaceScope(config),
+		watches:       make(map[schema.GroupVersionResource]*internal.MultiNamespaceInformer),
 		registrations: make(map[schema.GroupVersionResource]*registration),
-		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.NewTypedMaxOfRateLimiter(
-			workqueue.NewTypedItemExponentialFailureRateLimiter[ObjectIdentifiers](config.MinRetryDelay, config.MaxRetryDelay),
-			&workqueue.TypedBucketRateLimiter[ObjectIdentifiers]{Limiter: rate.NewLimiter(rate.Limit(config.RateLimit), config.BurstLimit)},
-		), workqueue.TypedRateLimitingQueueConfig[ObjectIdentifiers]{Name: "dynamic-controller-queue"}),
-	}
+		externals:     newExternalSelectorIndex(),
+		rateLimiter:   newGVRRateLimiter(config),
+		tunings:       make(map[schema.GroupVersionResource]Tuning),
+		lastResyncs:   make(map[schema.GroupVersionResource]time.Time),
+		busyWorkers:   make(map[schema.GroupVersionResource]int),
+	}
+	dc.queued = newFairQueue(dc.priority)
+	dc.queue = newQueue(dc.rateLimiter, dc.queued)
+	return dc
 }
 
 // Start starts workers and blocks until ctx.Done().
@@ -236,11 +306,20 @@ func (dc *DynamicController) Start(ctx context.Context) error {
 	defer dc.log.Info("Shutting down dynamic controller")
 
 	dc.ctx = ctx
+	dc.lastProgress.Store(time.Now().UnixNano())
+	dc.started.Store(true)
+
+	if len(dc.config.Namespaces) == 0 && dc.config.NamespaceSelector != nil {
+		if err := dc.watchNamespaceSelector(ctx); err != nil {
+			return err
+		}
+	}
 
 	// Workers.
 	for i := 0; i < dc.config.Workers; i++ {
 		go wait.UntilWithContext(ctx, dc.worker, time.Second)
 	}
+	go wait.UntilWithContext(ctx, dc.resyncTuned, resyncCheckInterval)
 
 	<-ctx.Done()
 	return dc.gracefulShutdown()
@@ -258,6 +337,15 @@ func (dc *DynamicController) processNextWorkItem(ctx context.Context) bool {
 	}
 	defer dc.queue.Done(item)
 
+	if !dc.acquireWorker(item.GVR) {
+		// The instances of the parent GVR already use every worker they may
+		// use, leave the workers to other GVRs for a while.
+		dc.queue.AddAfter(item, busyRequeueDelay)
+		return true
+	}
+	defer dc.releaseWorker(item.GVR)
+	defer func() { dc.lastProgress.Store(time.Now().UnixNano()) }()
+
 	// metric: queueLength
 	queueLength.Set(float64(dc.queue.Len()))
 
@@ -268,6 +356,14 @@ func (dc *DynamicController) processNextWorkItem(ctx context.Context) bool {
 		dc.queue.Forget(item)
 		return true
 	}
+	if !dc.serves(item.NamespacedName) {
+		// the namespace stopped being watched, or the instance moved to another
+		// shard, since the item was queued.
+		dc.log.V(1).Info("instance is no longer served, dropping item", "item", item)
+		dc.queue.Forget(item)
+		dc.release(item.GVR, item.NamespacedName)
+		return true
+	}
 
 	err := dc.syncFunc(ctx, item, handler.(Handler))
 	if err == nil {
@@ -298,7 +394,7 @@ func (dc *DynamicController) processNextWorkItem(ctx context.Context) bool {
 			return true
 		}
 		requeueTotal.WithLabelValues(gvrKey, "rate_limited").Inc()
-		if dc.queue.NumRequeues(item) < dc.config.QueueMaxRetries {
+		if dc.queue.NumRequeues(item) < dc.queueMaxRetries() {
 			dc.log.Error(err, "Error syncing item, requeuing with rate limit", "item", item)
 			dc.queue.AddRateLimited(item)
 		} else {
@@ -341,6 +437,11 @@ func (dc *DynamicController) enqueueParent(parentGVR schema.GroupVersionResource
 		Namespace: mobj.GetNamespace(),
 		Name:      mobj.GetName(),
 	}, GVR: parentGVR}
+	if !dc.serves(oi.NamespacedName) {
+		// Cluster wide child informers see children of instances in any
+		// namespace, and informers see the instances of every shard.
+		return
+	}
 	dc.log.V(1).Info("Enqueueing object", "objectIdentifiers", oi, "eventType", eventType)
 
 	informerEventsTotal.WithLabelValues(parentGVR.String(), eventType).Inc()
@@ -358,7 +459,7 @@ func (dc *DynamicController) updateFunc(parentGVR schema.GroupVersionResource, o
 		dc.log.Error(err, "failed to access old object meta")
 		return
 	}
-	if newMeta.GetGeneration() == oldMeta.GetGeneration() {
+	if newMeta.GetGeneration() == oldMeta.GetGeneration() && !reconciledMetadataChanged(oldMeta, newMeta) {
 		dc.log.V(2).Info("Skipping update due to unchanged generation",
 			"name", newMeta.GetName(), "namespace", newMeta.GetNamespace(), "generation", newMeta.GetGeneration())
 		return
@@ -366,6 +467,20 @@ func (dc *DynamicController) updateFunc(parentGVR schema.GroupVersionResource, o
 	dc.enqueueParent(parentGVR, newObj, eventTypeUpdate)
 }
 
+// reconciledMetadataChanged returns true if an update changes metadata that
+// instances are reconciled from, which does not bump their generation.
+func reconciledMetadataChanged(oldMeta, newMeta metav1.Object) bool {
+	if oldMeta.GetLabels()[metadata.GraphRevisionLabel] != newMeta.GetLabels()[metadata.GraphRevisionLabel] {
+		return true
+	}
+	for _, key := range []string{metadata.TTLAnnotation, metadata.ExpiresAtAnnotation} {
+		if oldMeta.GetAnnotations()[key] != newMeta.GetAnnotations()[key] {
+			return true
+		}
+	}
+	return false
+}
+
 // Register registers parent and children via reconciliation.
 func (dc *DynamicController) Register(
 	_ context.Context,
@@ -393,13 +508,7 @@ func (dc *DynamicController) Register(
 	}
 
 	// kick reconciliation for existing parent objects
-	if w, ok := dc.watches[parent]; ok && !w.Informer().IsStopped() {
-		// Use informer cache if running to repopulate the queue.
-		objects := w.Informer().GetStore().List()
-		for _, obj := range objects {
-			dc.enqueueParent(parent, obj, "update")
-		}
-	}
+	dc.enqueueAllLocked(parent)
 
 	dc.log.V(1).Info("Successfully registered GVR", "gvr", keyFromGVR(parent))
 	return nil
@@ -410,6 +519,10 @@ func (dc *DynamicController) Deregister(_ context.Context, parent schema.GroupVe
 	dc.mu.Lock()
 	defer dc.mu.Unlock()
 
+	dc.externals.deleteWhere(func(key externalSelectorKey) bool {
+		return key.parent.GVR == parent
+	})
+
 	reg, exists := dc.registrations[parent]
 	if !exists {
 		return nil
@@ -425,24 +538,154 @@ func (dc *DynamicController) Deregister(_ context.Context, parent schema.GroupVe
 	}
 
 	delete(dc.registrations, parent)
+	dc.priorities.Delete(parent)
+	dc.releasers.Delete(parent)
+	dc.SetTuning(parent, Tuning{})
+	dc.rateLimiter.forget(parent)
 
 	dc.log.V(1).Info("Successfully unregistered GVR", "gvr", gvrKey)
 	return nil
 }
 
+// SetPriority sets the share of the workers the instances of a parent GVR get
+// when instances of several parent GVRs are queued.
+func (dc *DynamicController) SetPriority(parent schema.GroupVersionResource, priority Priority) {
+	dc.priorities.Store(parent, priority)
+}
+
+// priority returns the Priority of a parent GVR, PriorityNormal by default.
+func (dc *DynamicController) priority(parent schema.GroupVersionResource) Priority {
+	if p, ok := dc.priorities.Load(parent); ok {
+		return p.(Priority)
+	}
+	return PriorityNormal
+}
+
+// SetReleaseHandler sets the function called for the instances of a parent GVR
+// the controller stops serving.
+func (dc *DynamicController) SetReleaseHandler(parent schema.GroupVersionResource, release ReleaseHandler) {
+	dc.releasers.Store(parent, release)
+}
+
+// release calls the ReleaseHandler of the parent GVR, if any, for the
+// instance.
+func (dc *DynamicController) release(parent schema.GroupVersionResource, instance types.NamespacedName) {
+	if release, ok := dc.releasers.Load(parent); ok {
+		release.(ReleaseHandler)(instance)
+	}
+}
+
+// releaseUnservedLocked releases the cached objects of the parent GVR that are
+// no longer served.
+// Must be called with dc.mu held.
+func (dc *DynamicController) releaseUnservedLocked(parent schema.GroupVersionResource) {
+	w, ok := dc.watches[parent]
+	if !ok {
+		return
+	}
+	for _, obj := range w.List() {
+		mobj, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		instance := types.NamespacedName{Namespace: mobj.GetNamespace(), Name: mobj.GetName()}
+		if !dc.serves(instance) {
+			dc.release(parent, instance)
+		}
+	}
+}
+
+// Enqueue triggers the reconciliation of the given instances of a registered
+// parent GVR.
+func (dc *DynamicController) Enqueue(parent schema.GroupVersionResource, instances ...types.NamespacedName) {
+	for _, instance := range instances {
+		oi := ObjectIdentifiers{NamespacedName: instance, GVR: parent}
+		dc.log.V(1).Info("Enqueueing object", "objectIdentifiers", oi, "eventType", eventTypeUpdate)
+		dc.queue.Add(oi)
+	}
+}
+
+// CachedInstances returns the cached metadata of the instances of a registered
+// parent GVR, in every watched namespace and of every shard. It fails until
+// the cache is synced.
+func (dc *DynamicController) CachedInstances(parent schema.GroupVersionResource) ([]metav1.Object, error) {
+	dc.mu.Lock()
+	w, ok := dc.watches[parent]
+	dc.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%s is not watched", keyFromGVR(parent))
+	}
+	if !w.HasSynced() {
+		return nil, fmt.Errorf("the cache of %s is not synced yet", keyFromGVR(parent))
+	}
+
+	objs := w.List()
+	instances := make([]metav1.Object, 0, len(objs))
+	for _, obj := range objs {
+		mobj, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, mobj)
+	}
+	return instances, nil
+}
+
+// Resync triggers the reconciliation of every served instance of the
+// registered parent GVRs, typically once this replica owns other instances.
+// The instances it no longer owns are released.
+func (dc *DynamicController) Resync() {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	for parent := range dc.registrations {
+		dc.releaseUnservedLocked(parent)
+		dc.enqueueAllLocked(parent)
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. When
+// instances are sharded, every replica reconciles its own instances.
+func (dc *DynamicController) NeedLeaderElection() bool {
+	return dc.config.Shard == nil
+}
+
 // ----- internal helpers -----
 
+// serves returns true if the instance is reconciled by this controller: its
+// namespace is watched and, when sharded, this replica owns it.
+func (dc *DynamicController) serves(instance types.NamespacedName) bool {
+	if !dc.namespaces.contains(instance.Namespace) {
+		return false
+	}
+	return dc.config.Shard == nil || dc.config.Shard.Owns(instance)
+}
+
+// enqueueAllLocked enqueues the cached objects of the parent GVR.
+// Must be called with dc.mu held.
+func (dc *DynamicController) enqueueAllLocked(parent schema.GroupVersionResource) {
+	if w, ok := dc.watches[parent]; ok {
+		// Use informer cache if running to repopulate the queue.
+		for _, obj := range w.List() {
+			dc.enqueueParent(parent, obj, "update")
+		}
+	}
+}
+
 func (dc *DynamicController) ensureWatchLocked(
 	gvr schema.GroupVersionResource,
-) *internal.LazyInformer {
+) (*internal.MultiNamespaceInformer, error) {
 	if w, ok := dc.watches[gvr]; ok {
-		return w
+		return w, nil
 	}
 
-	// Create per-GVR watch wrapper (informer created lazily on first handler)
-	w := internal.NewLazyInformer(dc.client, gvr, dc.config.ResyncPeriod, nil, dc.log)
+	namespaces, err := dc.informerNamespaces(gvr)
+	if err != nil {
+		return nil, err
+	}
+	// Create per-GVR watch wrapper (informers created lazily on first handler)
+	w := internal.NewMultiNamespaceInformer(dc.client, gvr, namespaces, dc.config.ResyncPeriod, nil, dc.log)
 	dc.watches[gvr] = w
-	return w
+	return w, nil
 }
 
 // reconcileParentLocked ensures a parent watch exists and has exactly one handler.
@@ -472,7 +715,10 @@ func (dc *DynamicController) reconcileParentLocked(
 	}
 
 	// ensure watch
-	w := dc.ensureWatchLocked(parent)
+	w, err := dc.ensureWatchLocked(parent)
+	if err != nil {
+		return fmt.Errorf("watch parent %s: %w", parent, err)
+	}
 
 	// create handler if missing
 	if reg.parentHandlerID == "" {
@@ -533,7 +779,10 @@ func (dc *DynamicController) reconcileChildrenLocked(
 		if _, exists := reg.childHandlerIDs[child]; exists {
 			continue
 		}
-		w := dc.ensureWatchLocked(child)
+		w, err := dc.ensureWatchLocked(child)
+		if err != nil {
+			return fmt.Errorf("watch child %s: %w", child, err)
+		}
 		childHandlerID := childHandlerID(parent, child)
 		childHandler, err := dc.handlerForChildGVR(parent, child)
 		if err != nil {
@@ -579,6 +828,13 @@ func (dc *DynamicController) handlerForChildGVR(parent, child schema.GroupVersio
 	if err != nil {
 		return nil, fmt.Errorf("failed ot get parent gvk from %s for child handler: %w", parentGVRKey, err)
 	}
+	parentMapping, err := dc.mapper.RESTMapping(parentGVK.GroupKind(), parentGVK.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent rest mapping for %s for child handler: %w", parentGVRKey, err)
+	}
+	// Cluster scoped parents have no namespace, regardless of the namespace
+	// of their children.
+	parentNamespaced := parentMapping.Scope.Name() == meta.RESTScopeNameNamespace
 	handle := func(obj interface{}, eventType string) {
 		objMeta, err := meta.Accessor(obj)
 		if err != nil {
@@ -594,9 +850,12 @@ func (dc *DynamicController) handlerForChildGVR(parent, child schema.GroupVersio
 		if !ok {
 			return
 		}
-		namespace, ok := lbls[metadata.InstanceNamespaceLabel]
-		if !ok {
-			return
+		var namespace string
+		if parentNamespaced {
+			namespace, ok = lbls[metadata.InstanceNamespaceLabel]
+			if !ok {
+				return
+			}
 		}
 
 		pom := &metav1.PartialObjectMetadata{}
@@ -615,10 +874,44 @@ func (dc *DynamicController) handlerForChildGVR(parent, child schema.GroupVersio
 		)
 		dc.enqueueParent(parent, pom, eventType)
 	}
+	// External objects are routed to the parents that selected them. On
+	// updates, both the old and new objects are matched so that objects
+	// leaving the selected set are noticed too.
+	handleExternal := func(eventType string, objs ...interface{}) {
+		metas := make([]metav1.Object, 0, len(objs))
+		for _, obj := range objs {
+			if objMeta, err := meta.Accessor(obj); err == nil {
+				metas = append(metas, objMeta)
+			}
+		}
+		for _, oi := range dc.externals.matching(parent, child, metas...) {
+			if !dc.serves(oi.NamespacedName) {
+				continue
+			}
+			dc.log.V(1).Info("External object triggered parent reconciliation",
+				"parent", parentGVRKey,
+				"child", childGVRKey,
+				"eventType", eventType,
+				"targetName", oi.Name,
+				"targetNamespace", oi.Namespace,
+			)
+			informerEventsTotal.WithLabelValues(parent.String(), eventType).Inc()
+			dc.queue.Add(oi)
+		}
+	}
 	return cache.ResourceEventHandlerFuncs{
-		AddFunc:    func(obj interface{}) { handle(obj, eventTypeAdd) },
-		UpdateFunc: func(oldObj, newObj interface{}) { handle(newObj, eventTypeUpdate) },
-		DeleteFunc: func(obj interface{}) { handle(obj, eventTypeDelete) },
+		AddFunc: func(obj interface{}) {
+			handle(obj, eventTypeAdd)
+			handleExternal(eventTypeAdd, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			handle(newObj, eventTypeUpdate)
+			handleExternal(eventTypeUpdate, oldObj, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			handle(obj, eventTypeDelete)
+			handleExternal(eventTypeDelete, obj)
+		},
 	}, nil
 }
 
@@ -32,6 +32,8 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/metadata/fake"
 	k8stesting "k8s.io/client-go/testing"
@@ -309,6 +311,96 @@ func TestEnqueueObject(t *testing.T) {
 	dc.enqueueParent(schema.GroupVersionResource{Group: "group", Version: "version", Resource: "resource"}, obj, "add")
 
 	assert.Equal(t, 1, dc.queue.Len())
+
+	// Enqueueing the same instance again is deduplicated by the queue.
+	dc.Enqueue(schema.GroupVersionResource{Group: "group", Version: "version", Resource: "resource"},
+		types.NamespacedName{Namespace: "default", Name: "test-object"},
+		types.NamespacedName{Namespace: "default", Name: "other-object"},
+	)
+
+	assert.Equal(t, 2, dc.queue.Len())
+}
+
+func TestChildHandler_ParentScope(t *testing.T) {
+	parentGVK := schema.GroupVersionKind{Group: "kro.run", Version: "v1", Kind: "Tenant"}
+	parentGVR := schema.GroupVersionResource{Group: "kro.run", Version: "v1", Resource: "tenants"}
+	childGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	tests := []struct {
+		name          string
+		scope         meta.RESTScope
+		labels        map[string]string
+		wantEnqueued  bool
+		wantNamespace string
+	}{
+		{
+			name:  "namespaced parent",
+			scope: meta.RESTScopeNamespace,
+			labels: map[string]string{
+				metadata.InstanceNamespaceLabel: "team-a",
+			},
+			wantEnqueued:  true,
+			wantNamespace: "team-a",
+		},
+		{
+			name:  "namespaced parent without namespace label",
+			scope: meta.RESTScopeNamespace,
+		},
+		{
+			name:  "cluster scoped parent ignores child namespace",
+			scope: meta.RESTScopeRoot,
+			labels: map[string]string{
+				metadata.InstanceNamespaceLabel: "",
+			},
+			wantEnqueued: true,
+		},
+		{
+			name:         "cluster scoped parent without namespace label",
+			scope:        meta.RESTScopeRoot,
+			wantEnqueued: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := setupFakeClient(t)
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.AddSpecific(parentGVK, parentGVR, parentGVR, tt.scope)
+			dc := NewDynamicController(noopLogger(), Config{
+				MinRetryDelay: 200 * time.Millisecond,
+				MaxRetryDelay: 1000 * time.Second,
+				RateLimit:     10,
+				BurstLimit:    100,
+			}, client, mapper)
+
+			handler, err := dc.handlerForChildGVR(parentGVR, childGVR)
+			require.NoError(t, err)
+
+			child := &v1.PartialObjectMetadata{}
+			child.SetName("config")
+			child.SetNamespace("default")
+			lbls := map[string]string{
+				metadata.OwnedLabel:    "true",
+				metadata.InstanceLabel: "acme",
+			}
+			for k, v := range tt.labels {
+				lbls[k] = v
+			}
+			child.SetLabels(lbls)
+			handler.OnAdd(child, false)
+
+			if !tt.wantEnqueued {
+				assert.Equal(t, 0, dc.queue.Len())
+				return
+			}
+			require.Equal(t, 1, dc.queue.Len())
+			item, _ := dc.queue.Get()
+			assert.Equal(t, ObjectIdentifiers{
+				NamespacedName: types.NamespacedName{Namespace: tt.wantNamespace, Name: "acme"},
+				GVR:            parentGVR,
+			}, item)
+		})
+	}
 }
 
 func TestInstanceUpdatePolicy(t *testing.T) {
@@ -368,3 +460,217 @@ func TestInstanceUpdatePolicy(t *testing.T) {
 		assert.True(t, ok)
 	}
 }
+
+// fakeShard owns the instances of the given names.
+type fakeShard struct {
+	mu    sync.Mutex
+	names map[string]bool
+}
+
+func (s *fakeShard) Owns(instance types.NamespacedName) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.names[instance.Name]
+}
+
+func (s *fakeShard) set(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = map[string]bool{}
+	for _, name := range names {
+		s.names[name] = true
+	}
+}
+
+func TestDynamicController_Shard(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1.AddMetaToScheme(scheme))
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+	gvk := schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"}
+
+	newObject := func(name string) *v1.PartialObjectMetadata {
+		obj := &v1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		return obj
+	}
+	client := fake.NewSimpleMetadataClient(scheme, newObject("one"), newObject("two"))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.AddSpecific(gvk, gvr, gvr, meta.RESTScopeNamespace)
+
+	shard := &fakeShard{}
+	shard.set("one")
+	dc := NewDynamicController(noopLogger(), Config{Shard: shard}, client, mapper)
+	assert.False(t, dc.NeedLeaderElection())
+	dc.ctx = t.Context() // simulate a start through dc.Run
+
+	var reconciled []types.NamespacedName
+	handler := Handler(func(_ context.Context, req controllerruntime.Request) error {
+		reconciled = append(reconciled, req.NamespacedName)
+		return nil
+	})
+	released := sets.New[types.NamespacedName]()
+	dc.SetReleaseHandler(gvr, func(instance types.NamespacedName) {
+		released.Insert(instance)
+	})
+	require.NoError(t, dc.Register(t.Context(), gvr, handler))
+
+	process := func() []types.NamespacedName {
+		reconciled = nil
+		for dc.queue.Len() > 0 {
+			require.True(t, dc.processNextWorkItem(t.Context()))
+		}
+		return reconciled
+	}
+	assert.Equal(t, []types.NamespacedName{{Namespace: "default", Name: "one"}}, process())
+
+	// Instances moving to this replica are reconciled on resync, and
+	// instances moving away are dropped even if they were already queued.
+	dc.Enqueue(gvr, types.NamespacedName{Namespace: "default", Name: "one"})
+	shard.set("two")
+	dc.Resync()
+	assert.Equal(t, []types.NamespacedName{{Namespace: "default", Name: "two"}}, process())
+	// Instances moving away are released.
+	assert.Equal(t, sets.New(types.NamespacedName{Namespace: "default", Name: "one"}), released)
+
+	dc.enqueueParent(gvr, newObject("one"), eventTypeUpdate)
+	assert.Equal(t, 0, dc.queue.Len())
+}
+
+func TestDynamicController_UpdateFunc(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+	newObject := func(generation int64, labels map[string]string) *v1.PartialObjectMetadata {
+		obj := &v1.PartialObjectMetadata{}
+		obj.SetNamespace("default")
+		obj.SetName("one")
+		obj.SetGeneration(generation)
+		obj.SetLabels(labels)
+		return obj
+	}
+	withAnnotations := func(obj *v1.PartialObjectMetadata, annotations map[string]string) *v1.PartialObjectMetadata {
+		obj.SetAnnotations(annotations)
+		return obj
+	}
+
+	tests := []struct {
+		name         string
+		oldObj       *v1.PartialObjectMetadata
+		newObj       *v1.PartialObjectMetadata
+		wantEnqueued bool
+	}{
+		{
+			name:         "spec change",
+			oldObj:       newObject(1, nil),
+			newObj:       newObject(2, nil),
+			wantEnqueued: true,
+		},
+		{
+			name:   "unrelated label",
+			oldObj: newObject(1, nil),
+			newObj: newObject(1, map[string]string{"team": "a"}),
+		},
+		{
+			name:         "pinned graph revision",
+			oldObj:       newObject(1, nil),
+			newObj:       newObject(1, map[string]string{metadata.GraphRevisionLabel: "2"}),
+			wantEnqueued: true,
+		},
+		{
+			name:         "repinned graph revision",
+			oldObj:       newObject(1, map[string]string{metadata.GraphRevisionLabel: "2"}),
+			newObj:       newObject(1, map[string]string{metadata.GraphRevisionLabel: "3"}),
+			wantEnqueued: true,
+		},
+		{
+			name:         "unpinned graph revision",
+			oldObj:       newObject(1, map[string]string{metadata.GraphRevisionLabel: "2"}),
+			newObj:       newObject(1, nil),
+			wantEnqueued: true,
+		},
+		{
+			name:   "unrelated annotation",
+			oldObj: newObject(1, nil),
+			newObj: withAnnotations(newObject(1, nil), map[string]string{"team": "a"}),
+		},
+		{
+			name:         "set ttl",
+			oldObj:       newObject(1, nil),
+			newObj:       withAnnotations(newObject(1, nil), map[string]string{metadata.TTLAnnotation: "1h"}),
+			wantEnqueued: true,
+		},
+		{
+			name:         "changed expires-at",
+			oldObj:       withAnnotations(newObject(1, nil), map[string]string{metadata.ExpiresAtAnnotation: "2030-01-01T00:00:00Z"}),
+			newObj:       withAnnotations(newObject(1, nil), map[string]string{metadata.ExpiresAtAnnotation: "2031-01-01T00:00:00Z"}),
+			wantEnqueued: true,
+		},
+		{
+			name:         "removed ttl",
+			oldObj:       withAnnotations(newObject(1, nil), map[string]string{metadata.TTLAnnotation: "1h"}),
+			newObj:       newObject(1, nil),
+			wantEnqueued: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mapper := setupFakeClient(t)
+			dc := NewDynamicController(noopLogger(), Config{}, client, mapper)
+			dc.updateFunc(gvr, tt.oldObj, tt.newObj)
+			if tt.wantEnqueued {
+				assert.Equal(t, 1, dc.queue.Len())
+			} else {
+				assert.Equal(t, 0, dc.queue.Len())
+			}
+		})
+	}
+}
+
+func TestDynamicController_CachedInstances(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1.AddMetaToScheme(scheme))
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+	gvk := schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"}
+
+	newObject := func(name string) *v1.PartialObjectMetadata {
+		obj := &v1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		return obj
+	}
+	client := fake.NewSimpleMetadataClient(scheme, newObject("one"), newObject("two"))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.AddSpecific(gvk, gvr, gvr, meta.RESTScopeNamespace)
+
+	// Every instance is cached, including those of other shards.
+	shard := &fakeShard{}
+	shard.set("one")
+	dc := NewDynamicController(noopLogger(), Config{Shard: shard}, client, mapper)
+	dc.ctx = t.Context() // simulate a start through dc.Run
+
+	_, err := dc.CachedInstances(gvr)
+	assert.Error(t, err)
+
+	handler := Handler(func(context.Context, controllerruntime.Request) error { return nil })
+	require.NoError(t, dc.Register(t.Context(), gvr, handler))
+	var names []string
+	require.Eventually(t, func() bool {
+		instances, err := dc.CachedInstances(gvr)
+		if err != nil {
+			return false
+		}
+		names = nil
+		for _, inst := range instances {
+			names = append(names, inst.GetName())
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"one", "two"}, names)
+}
+
+func TestDynamicController_NeedLeaderElection(t *testing.T) {
+	client, mapper := setupFakeClient(t)
+	dc := NewDynamicController(noopLogger(), Config{}, client, mapper)
+	assert.True(t, dc.NeedLeaderElection())
+}
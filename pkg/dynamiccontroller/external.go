@@ -0,0 +1,163 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// externalSelectorKey identifies a label selector recorded by a parent
+// instance for one of its external collections.
+type externalSelectorKey struct {
+	parent ObjectIdentifiers
+	id     string
+}
+
+// externalSelector is a label selector an instance used to read an external
+// collection. An empty namespace matches objects in every namespace.
+type externalSelector struct {
+	namespace string
+	selector  labels.Selector
+}
+
+func (s externalSelector) matches(obj metav1.Object) bool {
+	if s.namespace != "" && s.namespace != obj.GetNamespace() {
+		return false
+	}
+	return s.selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// externalSelectorIndex tracks the label selectors used by instances to read
+// external collections, indexed by the GVR of the selected objects.
+//
+// External objects are not owned by kro and carry no instance labels, so the
+// child handlers cannot route their events to a parent. Instead, every event
+// is matched against the selectors recorded here.
+type externalSelectorIndex struct {
+	mu        sync.RWMutex
+	selectors map[schema.GroupVersionResource]map[externalSelectorKey]externalSelector
+}
+
+func newExternalSelectorIndex() *externalSelectorIndex {
+	return &externalSelectorIndex{
+		selectors: make(map[schema.GroupVersionResource]map[externalSelectorKey]externalSelector),
+	}
+}
+
+func (idx *externalSelectorIndex) set(
+	key externalSelectorKey,
+	gvr schema.GroupVersionResource,
+	sel externalSelector,
+) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	// A node may select a different GVR after an RGD update.
+	for other, byKey := range idx.selectors {
+		if other != gvr {
+			idx.deleteLocked(other, byKey, key)
+		}
+	}
+	byKey, ok := idx.selectors[gvr]
+	if !ok {
+		byKey = make(map[externalSelectorKey]externalSelector)
+		idx.selectors[gvr] = byKey
+	}
+	byKey[key] = sel
+}
+
+// deleteWhere removes every selector whose key satisfies the predicate.
+func (idx *externalSelectorIndex) deleteWhere(pred func(externalSelectorKey) bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for gvr, byKey := range idx.selectors {
+		for key := range byKey {
+			if pred(key) {
+				idx.deleteLocked(gvr, byKey, key)
+			}
+		}
+	}
+}
+
+func (idx *externalSelectorIndex) deleteLocked(
+	gvr schema.GroupVersionResource,
+	byKey map[externalSelectorKey]externalSelector,
+	key externalSelectorKey,
+) {
+	delete(byKey, key)
+	if len(byKey) == 0 {
+		delete(idx.selectors, gvr)
+	}
+}
+
+// matching returns the parents of the given GVR that selected any of objs.
+func (idx *externalSelectorIndex) matching(
+	parent, gvr schema.GroupVersionResource,
+	objs ...metav1.Object,
+) []ObjectIdentifiers {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var out []ObjectIdentifiers
+	seen := make(map[ObjectIdentifiers]struct{})
+	for key, sel := range idx.selectors[gvr] {
+		if key.parent.GVR != parent {
+			continue
+		}
+		if _, ok := seen[key.parent]; ok {
+			continue
+		}
+		for _, obj := range objs {
+			if obj != nil && sel.matches(obj) {
+				seen[key.parent] = struct{}{}
+				out = append(out, key.parent)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// WatchExternalSelector records that the parent instance read the objects of
+// gvr matching selector in namespace for its external collection id. Changes
+// to matching objects then enqueue the instance. An empty namespace matches
+// every namespace. Recording a selector again replaces the previous one.
+//
+// The GVR must be one of the resources watched for the parent, see Register.
+func (dc *DynamicController) WatchExternalSelector(
+	parent schema.GroupVersionResource,
+	instance types.NamespacedName,
+	id string,
+	gvr schema.GroupVersionResource,
+	namespace string,
+	selector labels.Selector,
+) {
+	key := externalSelectorKey{parent: ObjectIdentifiers{NamespacedName: instance, GVR: parent}, id: id}
+	dc.externals.set(key, gvr, externalSelector{namespace: namespace, selector: selector})
+}
+
+// ForgetExternalSelectors drops every selector recorded for the instance,
+// typically once it is deleted.
+func (dc *DynamicController) ForgetExternalSelectors(parent schema.GroupVersionResource, instance types.NamespacedName) {
+	oi := ObjectIdentifiers{NamespacedName: instance, GVR: parent}
+	dc.externals.deleteWhere(func(key externalSelectorKey) bool {
+		return key.parent == oi
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExternalSelectors(t *testing.T) {
+	parentGVK := schema.GroupVersionKind{Group: "kro.run", Version: "v1", Kind: "App"}
+	parentGVR := schema.GroupVersionResource{Group: "kro.run", Version: "v1", Resource: "apps"}
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	instance := types.NamespacedName{Namespace: "payments", Name: "app"}
+
+	newSecret := func(namespace string, lbls map[string]string) *v1.PartialObjectMetadata {
+		obj := &v1.PartialObjectMetadata{}
+		obj.SetName("secret")
+		obj.SetNamespace(namespace)
+		obj.SetLabels(lbls)
+		return obj
+	}
+
+	setup := func(t *testing.T) (*DynamicController, func(eventType string, objs ...*v1.PartialObjectMetadata)) {
+		client, _ := setupFakeClient(t)
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.AddSpecific(parentGVK, parentGVR, parentGVR, meta.RESTScopeNamespace)
+		dc := NewDynamicController(noopLogger(), Config{
+			MinRetryDelay: 200 * time.Millisecond,
+			MaxRetryDelay: 1000 * time.Second,
+			RateLimit:     10,
+			BurstLimit:    100,
+		}, client, mapper)
+
+		handler, err := dc.handlerForChildGVR(parentGVR, secretsGVR)
+		require.NoError(t, err)
+
+		selector, err := labels.Parse("team=payments")
+		require.NoError(t, err)
+		dc.WatchExternalSelector(parentGVR, instance, "secrets", secretsGVR, "payments", selector)
+
+		send := func(eventType string, objs ...*v1.PartialObjectMetadata) {
+			switch eventType {
+			case eventTypeAdd:
+				handler.OnAdd(objs[0], false)
+			case eventTypeUpdate:
+				handler.OnUpdate(objs[0], objs[1])
+			case eventTypeDelete:
+				handler.OnDelete(objs[0])
+			}
+		}
+		return dc, send
+	}
+
+	matching := map[string]string{"team": "payments"}
+	other := map[string]string{"team": "billing"}
+
+	tests := []struct {
+		name         string
+		eventType    string
+		objs         []*v1.PartialObjectMetadata
+		wantEnqueued bool
+	}{
+		{
+			name:         "matching object added",
+			eventType:    eventTypeAdd,
+			objs:         []*v1.PartialObjectMetadata{newSecret("payments", matching)},
+			wantEnqueued: true,
+		},
+		{
+			name:      "object with other labels",
+			eventType: eventTypeAdd,
+			objs:      []*v1.PartialObjectMetadata{newSecret("payments", other)},
+		},
+		{
+			name:      "matching object in another namespace",
+			eventType: eventTypeAdd,
+			objs:      []*v1.PartialObjectMetadata{newSecret("billing", matching)},
+		},
+		{
+			name:      "object leaving the selected set",
+			eventType: eventTypeUpdate,
+			objs: []*v1.PartialObjectMetadata{
+				newSecret("payments", matching), newSecret("payments", other),
+			},
+			wantEnqueued: true,
+		},
+		{
+			name:         "matching object deleted",
+			eventType:    eventTypeDelete,
+			objs:         []*v1.PartialObjectMetadata{newSecret("payments", matching)},
+			wantEnqueued: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc, send := setup(t)
+			send(tt.eventType, tt.objs...)
+
+			if !tt.wantEnqueued {
+				assert.Equal(t, 0, dc.queue.Len())
+				return
+			}
+			require.Equal(t, 1, dc.queue.Len())
+			item, _ := dc.queue.Get()
+			assert.Equal(t, ObjectIdentifiers{NamespacedName: instance, GVR: parentGVR}, item)
+		})
+	}
+
+	t.Run("forgotten instance", func(t *testing.T) {
+		dc, send := setup(t)
+		dc.ForgetExternalSelectors(parentGVR, instance)
+		send(eventTypeAdd, newSecret("payments", matching))
+		assert.Equal(t, 0, dc.queue.Len())
+	})
+
+	t.Run("deregistered parent", func(t *testing.T) {
+		dc, send := setup(t)
+		require.NoError(t, dc.Deregister(t.Context(), parentGVR))
+		send(eventTypeAdd, newSecret("payments", matching))
+		assert.Equal(t, 0, dc.queue.Len())
+	})
+}
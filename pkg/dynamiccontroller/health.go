@@ -0,0 +1,80 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ReadyzCheck is a healthz.Checker failing until the controller is started
+// and the informers of every registered GVR are synced.
+func (dc *DynamicController) ReadyzCheck(_ *http.Request) error {
+	if !dc.started.Load() {
+		return fmt.Errorf("dynamic controller is not started")
+	}
+
+	dc.mu.Lock()
+	var unsynced []string
+	for gvr, w := range dc.watches {
+		if w.HandlerCount() > 0 && !w.HasSynced() {
+			unsynced = append(unsynced, keyFromGVR(gvr))
+		}
+	}
+	dc.mu.Unlock()
+
+	if len(unsynced) > 0 {
+		slices.Sort(unsynced)
+		return fmt.Errorf("informers are not synced: %s", strings.Join(unsynced, ", "))
+	}
+	return nil
+}
+
+// HealthzCheck is a healthz.Checker failing when the workers are stuck, not
+// having processed any item for Config.ProgressDeadline while items waited
+// longer than that in the queue, or when the watch of a registered GVR kept
+// failing for Config.ProgressDeadline.
+func (dc *DynamicController) HealthzCheck(_ *http.Request) error {
+	deadline := dc.progressDeadline()
+	if deadline <= 0 || !dc.started.Load() {
+		return nil
+	}
+	now := time.Now()
+
+	lastProgress := time.Unix(0, dc.lastProgress.Load())
+	if oldest := dc.queued.oldest(); !oldest.IsZero() &&
+		now.Sub(oldest) > deadline && now.Sub(lastProgress) > deadline {
+		return fmt.Errorf("workers processed no item for %s while %d are queued",
+			now.Sub(lastProgress).Round(time.Second), dc.queue.Len())
+	}
+
+	dc.mu.Lock()
+	var failing []string
+	for gvr, w := range dc.watches {
+		if since := w.WatchFailingSince(now); !since.IsZero() && now.Sub(since) > deadline {
+			failing = append(failing, keyFromGVR(gvr))
+		}
+	}
+	dc.mu.Unlock()
+
+	if len(failing) > 0 {
+		slices.Sort(failing)
+		return fmt.Errorf("watches keep failing for %s", strings.Join(failing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+)
+
+func TestDynamicController_ReadyzCheck(t *testing.T) {
+	client, mapper := setupFakeClient(t)
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+
+	dc := NewDynamicController(noopLogger(), Config{}, client, mapper)
+	assert.ErrorContains(t, dc.ReadyzCheck(nil), "not started")
+
+	// simulate a start through dc.Run
+	dc.ctx = t.Context()
+	dc.started.Store(true)
+	assert.NoError(t, dc.ReadyzCheck(nil))
+
+	handler := Handler(func(context.Context, controllerruntime.Request) error { return nil })
+	require.NoError(t, dc.Register(t.Context(), gvr, handler))
+	assert.NoError(t, dc.ReadyzCheck(nil))
+}
+
+func TestDynamicController_HealthzCheck(t *testing.T) {
+	client, mapper := setupFakeClient(t)
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+	longAgo := time.Now().Add(-time.Hour)
+
+	t.Run("disabled", func(t *testing.T) {
+		dc := NewDynamicController(noopLogger(), Config{}, client, mapper)
+		dc.started.Store(true)
+		dc.lastProgress.Store(longAgo.UnixNano())
+		dc.queued.now = func() time.Time { return longAgo }
+		dc.Enqueue(gvr, types.NamespacedName{Name: "one"})
+		assert.NoError(t, dc.HealthzCheck(nil))
+	})
+
+	t.Run("stuck workers", func(t *testing.T) {
+		dc := NewDynamicController(noopLogger(), Config{ProgressDeadline: time.Minute}, client, mapper)
+		assert.NoError(t, dc.HealthzCheck(nil), "not started")
+
+		dc.started.Store(true)
+		dc.lastProgress.Store(longAgo.UnixNano())
+		assert.NoError(t, dc.HealthzCheck(nil), "empty queue")
+
+		dc.queued.now = func() time.Time { return longAgo }
+		dc.Enqueue(gvr, types.NamespacedName{Name: "one"})
+		assert.ErrorContains(t, dc.HealthzCheck(nil), "workers processed no item")
+
+		// Workers made progress recently.
+		dc.lastProgress.Store(time.Now().UnixNano())
+		assert.NoError(t, dc.HealthzCheck(nil))
+	})
+
+	t.Run("recently queued", func(t *testing.T) {
+		dc := NewDynamicController(noopLogger(), Config{ProgressDeadline: time.Minute}, client, mapper)
+		dc.started.Store(true)
+		dc.lastProgress.Store(longAgo.UnixNano())
+		dc.Enqueue(gvr, types.NamespacedName{Name: "one"})
+		assert.NoError(t, dc.HealthzCheck(nil))
+	})
+}
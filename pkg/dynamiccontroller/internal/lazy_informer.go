@@ -28,14 +28,15 @@ import (
 	"k8s.io/client-go/tools/cache"
 )
 
-// LazyInformer manages a SharedIndexInformer per GVR with multiple handlers.
+// LazyInformer manages a SharedIndexInformer per GVR and namespace with multiple handlers.
 // It lazily starts when the first handler is added and stops when the last is removed.
 // It can restart again after a full shutdown.
 type LazyInformer struct {
-	gvr    schema.GroupVersionResource
-	client metadata.Interface
-	resync time.Duration
-	tweak  metadatainformer.TweakListOptionsFunc
+	gvr       schema.GroupVersionResource
+	namespace string
+	client    metadata.Interface
+	resync    time.Duration
+	tweak     metadatainformer.TweakListOptionsFunc
 
 	mu       sync.Mutex
 	informer cache.SharedIndexInformer
@@ -44,23 +45,42 @@ type LazyInformer struct {
 	done   <-chan struct{}
 	cancel context.CancelFunc
 
+	// failMu guards the current streak of watch failures, recorded by the
+	// reflector of the informer.
+	failMu       sync.Mutex
+	failingSince time.Time
+	lastFailure  time.Time
+
 	log logr.Logger
 }
 
+// watchFailureGap is how long a watch must go without failing for its streak
+// of failures to end. Reflectors retry failed watches at least every 30s.
+const watchFailureGap = time.Minute
+
+// NewLazyInformer creates a LazyInformer watching the objects of gvr in
+// namespace. metav1.NamespaceAll watches every namespace, and must be used for
+// cluster scoped resources.
 func NewLazyInformer(
 	client metadata.Interface,
 	gvr schema.GroupVersionResource,
+	namespace string,
 	resync time.Duration,
 	tweak metadatainformer.TweakListOptionsFunc,
 	logger logr.Logger,
 ) *LazyInformer {
+	log := logger.WithValues("gvr", gvr.String())
+	if namespace != metav1.NamespaceAll {
+		log = log.WithValues("namespace", namespace)
+	}
 	li := &LazyInformer{
-		gvr:      gvr,
-		client:   client,
-		resync:   resync,
-		tweak:    tweak,
-		handlers: make(map[string]cache.ResourceEventHandlerRegistration),
-		log:      logger.WithValues("gvr", gvr.String()),
+		gvr:       gvr,
+		namespace: namespace,
+		client:    client,
+		resync:    resync,
+		tweak:     tweak,
+		handlers:  make(map[string]cache.ResourceEventHandlerRegistration),
+		log:       log,
 	}
 	return li
 }
@@ -77,13 +97,14 @@ func (w *LazyInformer) ensureInformer() {
 		return
 	}
 	inf := metadatainformer.NewFilteredMetadataInformer(
-		w.client, w.gvr, metav1.NamespaceAll, w.resync,
+		w.client, w.gvr, w.namespace, w.resync,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 		w.tweak,
 	).Informer()
 
 	_ = inf.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
 		w.log.V(1).Error(err, "watch error for lazy informer", "gvr", w.gvr)
+		w.recordWatchFailure(time.Now())
 	})
 
 	w.informer = inf
@@ -162,6 +183,33 @@ func (w *LazyInformer) Informer() cache.SharedIndexInformer {
 	return w.informer
 }
 
+// HasSynced reports whether the informer is running and its cache synced.
+func (w *LazyInformer) HasSynced() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.informer != nil && w.informer.HasSynced()
+}
+
+func (w *LazyInformer) recordWatchFailure(now time.Time) {
+	w.failMu.Lock()
+	defer w.failMu.Unlock()
+	if w.failingSince.IsZero() || now.Sub(w.lastFailure) > watchFailureGap {
+		w.failingSince = now
+	}
+	w.lastFailure = now
+}
+
+// WatchFailingSince returns since when the watch of the informer keeps
+// failing, or the zero time if it is not failing.
+func (w *LazyInformer) WatchFailingSince(now time.Time) time.Time {
+	w.failMu.Lock()
+	defer w.failMu.Unlock()
+	if w.failingSince.IsZero() || now.Sub(w.lastFailure) > watchFailureGap {
+		return time.Time{}
+	}
+	return w.failingSince
+}
+
 // Shutdown stops the informer and clears state.
 func (w *LazyInformer) Shutdown() {
 	w.mu.Lock()
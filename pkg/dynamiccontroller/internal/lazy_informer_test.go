@@ -46,7 +46,7 @@ func TestLazyInformer_AddAndRemoveHandler(t *testing.T) {
 	defer cancel()
 
 	logger := noopLogger()
-	li := NewLazyInformer(client, gvr, time.Second, nil, logger)
+	li := NewLazyInformer(client, gvr, v1.NamespaceAll, time.Second, nil, logger)
 
 	// Adding first handler should start informer
 	err := li.AddHandler(ctx, "h1", cache.ResourceEventHandlerFuncs{})
@@ -73,7 +73,7 @@ func TestLazyInformer_MultipleHandlers(t *testing.T) {
 	defer cancel()
 
 	logger := noopLogger()
-	li := NewLazyInformer(client, gvr, time.Second, nil, logger)
+	li := NewLazyInformer(client, gvr, v1.NamespaceAll, time.Second, nil, logger)
 
 	require.NoError(t, li.AddHandler(ctx, "a", cache.ResourceEventHandlerFuncs{}))
 	require.NoError(t, li.AddHandler(ctx, "b", cache.ResourceEventHandlerFuncs{}))
@@ -104,7 +104,7 @@ func TestLazyInformer_Shutdown(t *testing.T) {
 	defer cancel()
 
 	logger := noopLogger()
-	li := NewLazyInformer(client, gvr, time.Second, nil, logger)
+	li := NewLazyInformer(client, gvr, v1.NamespaceAll, time.Second, nil, logger)
 
 	require.NoError(t, li.AddHandler(ctx, "h", cache.ResourceEventHandlerFuncs{}))
 	assert.NotNil(t, li.Informer())
@@ -123,7 +123,7 @@ func TestLazyInformer_RecreateAfterStop(t *testing.T) {
 
 	ctx := t.Context()
 	logger := noopLogger()
-	li := NewLazyInformer(client, gvr, time.Second, nil, logger) // avoid tiny resync
+	li := NewLazyInformer(client, gvr, v1.NamespaceAll, time.Second, nil, logger) // avoid tiny resync
 
 	// Add first handler and remove it -> stops informer
 	require.NoError(t, li.AddHandler(ctx, "h1", cache.ResourceEventHandlerFuncs{}))
@@ -154,7 +154,7 @@ func TestLazyInformer_ShutdownSafetyAndRestart(t *testing.T) {
 
 	ctx := t.Context()
 	logger := noopLogger()
-	li := NewLazyInformer(client, gvr, time.Second, nil, logger)
+	li := NewLazyInformer(client, gvr, v1.NamespaceAll, time.Second, nil, logger)
 
 	// shutdown before start should not panic
 	assert.NotPanics(t, li.Shutdown, "shutdown before any AddHandler must be safe")
@@ -183,3 +183,22 @@ func TestLazyInformer_ShutdownSafetyAndRestart(t *testing.T) {
 	assert.NotNil(t, li.cancel)
 	assert.NotNil(t, li.done)
 }
+
+func TestLazyInformer_WatchFailingSince(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+	li := NewLazyInformer(nil, gvr, v1.NamespaceAll, time.Second, nil, noopLogger())
+	start := time.Now()
+
+	assert.True(t, li.WatchFailingSince(start).IsZero())
+
+	// Failures close to each other make a streak.
+	li.recordWatchFailure(start)
+	li.recordWatchFailure(start.Add(30 * time.Second))
+	li.recordWatchFailure(start.Add(time.Minute))
+	assert.Equal(t, start, li.WatchFailingSince(start.Add(time.Minute)))
+
+	// The streak ends once the watch stops failing.
+	assert.True(t, li.WatchFailingSince(start.Add(3*time.Minute)).IsZero())
+	li.recordWatchFailure(start.Add(3 * time.Minute))
+	assert.Equal(t, start.Add(3*time.Minute), li.WatchFailingSince(start.Add(3*time.Minute)))
+}
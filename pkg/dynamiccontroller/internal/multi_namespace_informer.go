@@ -0,0 +1,249 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// MultiNamespaceInformer watches a GVR in a set of namespaces, with one
+// LazyInformer per namespace sharing the same handlers. Watching
+// metav1.NamespaceAll uses a single cluster wide informer.
+//
+// The set of namespaces can change at runtime: informers are started for new
+// namespaces with the registered handlers, and stopped for removed ones.
+type MultiNamespaceInformer struct {
+	gvr    schema.GroupVersionResource
+	client metadata.Interface
+	resync time.Duration
+	tweak  metadatainformer.TweakListOptionsFunc
+
+	mu sync.Mutex
+	// ctx is the context handlers were added with, used to start the
+	// informers of namespaces added later.
+	ctx        context.Context
+	namespaces sets.Set[string]
+	informers  map[string]*LazyInformer
+	handlers   map[string]cache.ResourceEventHandler
+
+	log logr.Logger
+}
+
+// NewMultiNamespaceInformer creates a MultiNamespaceInformer watching gvr in
+// the given namespaces.
+func NewMultiNamespaceInformer(
+	client metadata.Interface,
+	gvr schema.GroupVersionResource,
+	namespaces []string,
+	resync time.Duration,
+	tweak metadatainformer.TweakListOptionsFunc,
+	logger logr.Logger,
+) *MultiNamespaceInformer {
+	return &MultiNamespaceInformer{
+		gvr:        gvr,
+		client:     client,
+		resync:     resync,
+		tweak:      tweak,
+		namespaces: sets.New(namespaces...),
+		informers:  make(map[string]*LazyInformer),
+		handlers:   make(map[string]cache.ResourceEventHandler),
+		log:        logger,
+	}
+}
+
+// AddHandler registers a new event handler in every namespace, starting the
+// informers if needed. If any informer fails to start, the handler is removed
+// from all of them.
+func (m *MultiNamespaceInformer) AddHandler(ctx context.Context, id string, h cache.ResourceEventHandler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.handlers) == 0 {
+		m.ctx = ctx
+	}
+	for ns := range m.namespaces {
+		if err := m.informerLocked(ns).AddHandler(ctx, id, h); err != nil {
+			for other, li := range m.informers {
+				if _, rmErr := li.RemoveHandler(id); rmErr != nil {
+					m.log.Error(rmErr, "failed to roll back handler", "namespace", other, "handler", id)
+				}
+			}
+			m.pruneStoppedLocked()
+			return err
+		}
+	}
+	m.handlers[id] = h
+	return nil
+}
+
+// RemoveHandler unregisters a handler from every namespace. Returns true once
+// the last handler is removed and all informers are stopped.
+func (m *MultiNamespaceInformer) RemoveHandler(id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.handlers[id]; !ok {
+		return false, nil
+	}
+	var errs []error
+	for ns, li := range m.informers {
+		if _, err := li.RemoveHandler(id); err != nil {
+			errs = append(errs, fmt.Errorf("namespace %q: %w", ns, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return false, err
+	}
+	delete(m.handlers, id)
+	m.pruneStoppedLocked()
+	return len(m.handlers) == 0, nil
+}
+
+// SetNamespaces changes the namespaces watched. Informers of removed
+// namespaces are stopped, and informers of added namespaces are started with
+// the registered handlers.
+func (m *MultiNamespaceInformer) SetNamespaces(namespaces []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	desired := sets.New(namespaces...)
+	for ns := range m.namespaces.Difference(desired) {
+		if li, ok := m.informers[ns]; ok {
+			li.Shutdown()
+			delete(m.informers, ns)
+		}
+	}
+	added := desired.Difference(m.namespaces)
+	m.namespaces = desired
+	if len(m.handlers) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for ns := range added {
+		li := m.informerLocked(ns)
+		for id, h := range m.handlers {
+			if err := li.AddHandler(m.ctx, id, h); err != nil {
+				errs = append(errs, fmt.Errorf("namespace %q: %w", ns, err))
+				li.Shutdown()
+				delete(m.informers, ns)
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// List returns the objects cached by the running informers.
+func (m *MultiNamespaceInformer) List() []interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var objs []interface{}
+	for _, li := range m.informers {
+		if inf := li.Informer(); inf != nil && !inf.IsStopped() {
+			objs = append(objs, inf.GetStore().List()...)
+		}
+	}
+	return objs
+}
+
+// Namespaces returns the namespaces watched, sorted.
+func (m *MultiNamespaceInformer) Namespaces() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sets.List(m.namespaces)
+}
+
+// HandlerCount returns the number of registered handlers.
+func (m *MultiNamespaceInformer) HandlerCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.handlers)
+}
+
+// HasSynced reports whether the informers of every watched namespace are
+// running and their caches synced.
+func (m *MultiNamespaceInformer) HasSynced() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.handlers) == 0 {
+		return false
+	}
+	for ns := range m.namespaces {
+		li, ok := m.informers[ns]
+		if !ok || !li.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// WatchFailingSince returns since when the watch of an informer keeps
+// failing, the earliest if several do, or the zero time if none does.
+func (m *MultiNamespaceInformer) WatchFailingSince(now time.Time) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var since time.Time
+	for _, li := range m.informers {
+		if s := li.WatchFailingSince(now); !s.IsZero() && (since.IsZero() || s.Before(since)) {
+			since = s
+		}
+	}
+	return since
+}
+
+// Shutdown stops every informer and clears state.
+func (m *MultiNamespaceInformer) Shutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, li := range m.informers {
+		li.Shutdown()
+	}
+	m.informers = make(map[string]*LazyInformer)
+	m.handlers = make(map[string]cache.ResourceEventHandler)
+}
+
+func (m *MultiNamespaceInformer) informerLocked(namespace string) *LazyInformer {
+	li, ok := m.informers[namespace]
+	if !ok {
+		li = NewLazyInformer(m.client, m.gvr, namespace, m.resync, m.tweak, m.log)
+		m.informers[namespace] = li
+	}
+	return li
+}
+
+// pruneStoppedLocked drops the informers left without handlers.
+func (m *MultiNamespaceInformer) pruneStoppedLocked() {
+	for ns, li := range m.informers {
+		if li.Informer() == nil {
+			delete(m.informers, ns)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/metadata/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newTestObject(gvk schema.GroupVersionKind, namespace, name string) *v1.PartialObjectMetadata {
+	obj := &v1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func listedNamespaces(m *MultiNamespaceInformer) sets.Set[string] {
+	namespaces := sets.New[string]()
+	for _, obj := range m.List() {
+		namespaces.Insert(obj.(v1.Object).GetNamespace())
+	}
+	return namespaces
+}
+
+func TestMultiNamespaceInformer_WatchesNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1.AddMetaToScheme(scheme))
+	gvk := schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"}
+	client := fake.NewSimpleMetadataClient(scheme,
+		newTestObject(gvk, "a", "one"),
+		newTestObject(gvk, "b", "two"),
+		newTestObject(gvk, "c", "three"),
+	)
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+
+	m := NewMultiNamespaceInformer(client, gvr, []string{"a", "b"}, time.Hour, nil, noopLogger())
+	require.NoError(t, m.AddHandler(t.Context(), "h1", cache.ResourceEventHandlerFuncs{}))
+	assert.Len(t, m.informers, 2)
+	assert.Equal(t, sets.New("a", "b"), listedNamespaces(m))
+
+	// Namespaces added later are watched with the registered handlers.
+	require.NoError(t, m.SetNamespaces([]string{"b", "c"}))
+	assert.Len(t, m.informers, 2)
+	assert.Equal(t, sets.New("b", "c"), listedNamespaces(m))
+	assert.Len(t, m.informers["c"].handlers, 1)
+
+	stopped, err := m.RemoveHandler("h1")
+	require.NoError(t, err)
+	assert.True(t, stopped)
+	assert.Empty(t, m.informers)
+	assert.Empty(t, m.List())
+}
+
+func TestMultiNamespaceInformer_AllNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1.AddMetaToScheme(scheme))
+	gvk := schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"}
+	client := fake.NewSimpleMetadataClient(scheme,
+		newTestObject(gvk, "a", "one"),
+		newTestObject(gvk, "b", "two"),
+	)
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+
+	m := NewMultiNamespaceInformer(client, gvr, []string{v1.NamespaceAll}, time.Hour, nil, noopLogger())
+	require.NoError(t, m.AddHandler(t.Context(), "h1", cache.ResourceEventHandlerFuncs{}))
+	require.NoError(t, m.AddHandler(t.Context(), "h2", cache.ResourceEventHandlerFuncs{}))
+	assert.Len(t, m.informers, 1)
+	assert.Equal(t, sets.New("a", "b"), listedNamespaces(m))
+
+	stopped, err := m.RemoveHandler("h1")
+	require.NoError(t, err)
+	assert.False(t, stopped)
+
+	m.Shutdown()
+	assert.Empty(t, m.informers)
+	assert.Empty(t, m.handlers)
+}
+
+func TestMultiNamespaceInformer_NoNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1.AddMetaToScheme(scheme))
+	gvk := schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"}
+	client := fake.NewSimpleMetadataClient(scheme, newTestObject(gvk, "a", "one"))
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+
+	// Handlers are kept until namespaces are watched.
+	m := NewMultiNamespaceInformer(client, gvr, nil, time.Hour, nil, noopLogger())
+	require.NoError(t, m.AddHandler(t.Context(), "h1", cache.ResourceEventHandlerFuncs{}))
+	assert.Empty(t, m.informers)
+	assert.Empty(t, m.List())
+
+	require.NoError(t, m.SetNamespaces([]string{"a"}))
+	assert.Equal(t, sets.New("a"), listedNamespaces(m))
+
+	require.NoError(t, m.SetNamespaces(nil))
+	assert.Empty(t, m.informers)
+	assert.Empty(t, m.List())
+}
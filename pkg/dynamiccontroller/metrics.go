@@ -28,6 +28,8 @@ func init() {
 		reconcileDuration,
 		gvrCount,
 		queueLength,
+		queueDepth,
+		queueWaitDuration,
 		handlerCount,
 		handlerAttachTotal,
 		handlerDetachTotal,
@@ -75,6 +77,24 @@ var (
 			Help: "Current length of the workqueue",
 		},
 	)
+	// queueDepth is the number of queued instances per parent GVR.
+	queueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dynamic_controller_queue_depth",
+			Help: "Current number of queued instances per GVR",
+		},
+		[]string{"gvr"},
+	)
+	// queueWaitDuration tracks how long instances wait in the queue before a
+	// worker picks them, per parent GVR.
+	queueWaitDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dynamic_controller_queue_wait_duration_seconds",
+			Help:    "Time instances wait in the queue before being reconciled per GVR",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"gvr"},
+	)
 	handlerCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "dynamic_controller_handler_count_total",
 		Help: "Number of active handlers used for distributing events to instance controllers",
@@ -0,0 +1,166 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+var namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// namespaceScope holds the namespaces served by the controller.
+type namespaceScope struct {
+	mu sync.RWMutex
+	// namespaces is nil when every namespace is served.
+	namespaces sets.Set[string]
+}
+
+func newNamespaceScope(config Config) *namespaceScope {
+	switch {
+	case len(config.Namespaces) > 0:
+		return &namespaceScope{namespaces: sets.New(config.Namespaces...)}
+	case config.NamespaceSelector != nil:
+		// Nothing is served until the selected namespaces are listed.
+		return &namespaceScope{namespaces: sets.New[string]()}
+	default:
+		return &namespaceScope{}
+	}
+}
+
+// contains returns true if objects in namespace are served. Cluster scoped
+// objects, with an empty namespace, are always served.
+func (s *namespaceScope) contains(namespace string) bool {
+	if namespace == "" {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.namespaces == nil || s.namespaces.Has(namespace)
+}
+
+// list returns the sorted served namespaces, or nil if every namespace is
+// served.
+func (s *namespaceScope) list() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.namespaces == nil {
+		return nil
+	}
+	return sets.List(s.namespaces)
+}
+
+// set replaces the served namespaces. Returns true if they changed.
+func (s *namespaceScope) set(namespaces sets.Set[string]) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.namespaces.Equal(namespaces) {
+		return false
+	}
+	s.namespaces = namespaces
+	return true
+}
+
+// WatchedNamespaces returns the sorted namespaces served by the controller, or
+// nil if it serves every namespace.
+func (dc *DynamicController) WatchedNamespaces() []string {
+	return dc.namespaces.list()
+}
+
+// informerNamespaces returns the namespaces to watch gvr in. Cluster scoped
+// resources, and every resource when all namespaces are served, are watched
+// cluster wide.
+func (dc *DynamicController) informerNamespaces(gvr schema.GroupVersionResource) ([]string, error) {
+	namespaces := dc.namespaces.list()
+	if namespaces == nil {
+		return []string{metav1.NamespaceAll}, nil
+	}
+	gvk, err := dc.mapper.KindFor(gvr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gvk for %s: %w", keyFromGVR(gvr), err)
+	}
+	mapping, err := dc.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rest mapping for %s: %w", keyFromGVR(gvr), err)
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return []string{metav1.NamespaceAll}, nil
+	}
+	return namespaces, nil
+}
+
+// watchNamespaceSelector keeps the served namespaces in sync with the
+// namespaces matching the configured selector, until ctx is done.
+func (dc *DynamicController) watchNamespaceSelector(ctx context.Context) error {
+	selector := dc.config.NamespaceSelector.String()
+	inf := metadatainformer.NewFilteredMetadataInformer(
+		dc.client, namespacesGVR, metav1.NamespaceAll, dc.config.ResyncPeriod,
+		cache.Indexers{},
+		func(opts *metav1.ListOptions) { opts.LabelSelector = selector },
+	).Informer()
+
+	sync := func(interface{}) { dc.syncNamespaces(inf.GetStore().ListKeys()) }
+	if _, err := inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    sync,
+		UpdateFunc: func(_, obj interface{}) { sync(obj) },
+		DeleteFunc: sync,
+	}); err != nil {
+		return err
+	}
+
+	go inf.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), inf.HasSynced) {
+		return fmt.Errorf("failed to sync namespaces matching %q", selector)
+	}
+	dc.syncNamespaces(inf.GetStore().ListKeys())
+	return nil
+}
+
+// syncNamespaces serves the given namespaces, starting and stopping the
+// namespaced informers of every watched GVR accordingly. The instances in the
+// namespaces no longer served are released.
+func (dc *DynamicController) syncNamespaces(namespaces []string) {
+	if !dc.namespaces.set(sets.New(namespaces...)) {
+		return
+	}
+	sort.Strings(namespaces)
+	dc.log.Info("Watched namespaces changed", "namespaces", namespaces)
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	// Release before the informers of the namespaces stop, while their
+	// instances are still cached.
+	for parent := range dc.registrations {
+		dc.releaseUnservedLocked(parent)
+	}
+	for gvr, w := range dc.watches {
+		informerNamespaces, err := dc.informerNamespaces(gvr)
+		if err == nil {
+			err = w.SetNamespaces(informerNamespaces)
+		}
+		if err != nil {
+			dc.log.Error(err, "failed to update watched namespaces", "gvr", keyFromGVR(gvr))
+		}
+	}
+}
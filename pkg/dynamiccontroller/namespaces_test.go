@@ -0,0 +1,149 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/metadata/fake"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+)
+
+func TestNamespaceScope(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       Config
+		wantList     []string
+		wantContains map[string]bool
+	}{
+		{
+			name:         "every namespace",
+			config:       Config{},
+			wantList:     nil,
+			wantContains: map[string]bool{"": true, "a": true, "b": true},
+		},
+		{
+			name:         "listed namespaces",
+			config:       Config{Namespaces: []string{"b", "a"}},
+			wantList:     []string{"a", "b"},
+			wantContains: map[string]bool{"": true, "a": true, "b": true, "c": false},
+		},
+		{
+			name:         "selected namespaces before the first sync",
+			config:       Config{NamespaceSelector: labels.Everything()},
+			wantList:     []string{},
+			wantContains: map[string]bool{"": true, "a": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := newNamespaceScope(tt.config)
+			assert.Equal(t, tt.wantList, scope.list())
+			for ns, want := range tt.wantContains {
+				assert.Equal(t, want, scope.contains(ns), "namespace %q", ns)
+			}
+		})
+	}
+}
+
+func TestDynamicController_WatchNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1.AddMetaToScheme(scheme))
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+	gvk := schema.GroupVersionKind{Group: "test", Version: "v1", Kind: "Test"}
+
+	newObject := func(namespace, name string) *v1.PartialObjectMetadata {
+		obj := &v1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gvk)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		return obj
+	}
+	client := fake.NewSimpleMetadataClient(scheme,
+		newObject("team-a", "one"),
+		newObject("team-b", "two"),
+	)
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.AddSpecific(gvk, gvr, gvr, meta.RESTScopeNamespace)
+
+	dc := NewDynamicController(noopLogger(), Config{NamespaceSelector: labels.Everything()}, client, mapper)
+	dc.ctx = t.Context() // simulate a start through dc.Run
+	dc.syncNamespaces([]string{"team-a"})
+
+	var released []types.NamespacedName
+	dc.SetReleaseHandler(gvr, func(instance types.NamespacedName) {
+		released = append(released, instance)
+	})
+	handler := Handler(func(context.Context, controllerruntime.Request) error { return nil })
+	require.NoError(t, dc.Register(t.Context(), gvr, handler))
+
+	drain := func() []types.NamespacedName {
+		var keys []types.NamespacedName
+		for dc.queue.Len() > 0 {
+			item, _ := dc.queue.Get()
+			dc.queue.Done(item)
+			dc.queue.Forget(item)
+			keys = append(keys, item.NamespacedName)
+		}
+		return keys
+	}
+	assert.Equal(t, []types.NamespacedName{{Namespace: "team-a", Name: "one"}}, drain())
+
+	// Instances of namespaces selected later are enqueued.
+	dc.syncNamespaces([]string{"team-a", "team-b"})
+	assert.Equal(t, []string{"team-a", "team-b"}, dc.WatchedNamespaces())
+	assert.Equal(t, []types.NamespacedName{{Namespace: "team-b", Name: "two"}}, drain())
+
+	assert.Empty(t, released)
+
+	// Instances of namespaces no longer selected are released, and not
+	// enqueued anymore.
+	dc.syncNamespaces([]string{"team-b"})
+	assert.Equal(t, []types.NamespacedName{{Namespace: "team-a", Name: "one"}}, released)
+	dc.enqueueParent(gvr, newObject("team-a", "one"), eventTypeUpdate)
+	assert.Equal(t, 0, dc.queue.Len())
+}
+
+func TestProcessNextWorkItem_UnwatchedNamespace(t *testing.T) {
+	client, mapper := setupFakeClient(t)
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+
+	dc := NewDynamicController(noopLogger(), Config{Namespaces: []string{"team-a"}}, client, mapper)
+
+	var reconciled []types.NamespacedName
+	dc.handlers.Store(gvr, Handler(func(_ context.Context, req controllerruntime.Request) error {
+		reconciled = append(reconciled, req.NamespacedName)
+		return nil
+	}))
+
+	dc.Enqueue(gvr,
+		types.NamespacedName{Namespace: "team-a", Name: "one"},
+		types.NamespacedName{Namespace: "team-b", Name: "two"},
+	)
+	for dc.queue.Len() > 0 {
+		require.True(t, dc.processNextWorkItem(t.Context()))
+	}
+	assert.Equal(t, []types.NamespacedName{{Namespace: "team-a", Name: "one"}}, reconciled)
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// queueName names the workqueue in the workqueue metrics.
+const queueName = "dynamic-controller-queue"
+
+// Priority is the number of queued instances of a parent GVR served in a
+// turn, when instances of several parent GVRs are queued.
+type Priority int
+
+const (
+	// PriorityLow serves half as many instances per turn as PriorityNormal.
+	PriorityLow Priority = 1
+	// PriorityNormal is the priority of parent GVRs by default.
+	PriorityNormal Priority = 2
+	// PriorityHigh serves twice as many instances per turn as PriorityNormal.
+	PriorityHigh Priority = 4
+)
+
+// newQueue creates the rate limited workqueue shared by every parent GVR.
+// The workqueue deduplicates, delays and rate limits items as usual, but
+// stores them in fair.
+func newQueue(limiter *gvrRateLimiter, fair *fairQueue) workqueue.TypedRateLimitingInterface[ObjectIdentifiers] {
+	queue := workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[ObjectIdentifiers]{
+		Name:  queueName,
+		Queue: fair,
+	})
+	delaying := workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[ObjectIdentifiers]{
+		Name:  queueName,
+		Queue: queue,
+	})
+	return workqueue.NewTypedRateLimitingQueueWithConfig(limiter, workqueue.TypedRateLimitingQueueConfig[ObjectIdentifiers]{
+		Name:          queueName,
+		DelayingQueue: delaying,
+	})
+}
+
+// newRateLimiter creates a rate limiter backing off failing items
+// exponentially, and limiting the rate of the items with a token bucket.
+func newRateLimiter(config Config) workqueue.TypedRateLimiter[ObjectIdentifiers] {
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[ObjectIdentifiers](config.MinRetryDelay, config.MaxRetryDelay),
+		&workqueue.TypedBucketRateLimiter[ObjectIdentifiers]{Limiter: rate.NewLimiter(rate.Limit(config.RateLimit), config.BurstLimit)},
+	)
+}
+
+// gvrRateLimiter implements workqueue.TypedRateLimiter with a rate limiter per
+// parent GVR, so that the items of a busy GVR do not drain the token bucket of
+// the others. The rate limiters of GVRs whose rate limits are tuned are set
+// with setGVR, see Tuning; the others are created from config on first use.
+//
+// Replacing a rate limiter resets the backoff of the items it limited.
+type gvrRateLimiter struct {
+	mu     sync.RWMutex
+	config Config
+	// tuned holds the rate limiters of the tuned parent GVRs.
+	tuned map[schema.GroupVersionResource]workqueue.TypedRateLimiter[ObjectIdentifiers]
+	// defaults holds the rate limiters of the other parent GVRs.
+	defaults map[schema.GroupVersionResource]workqueue.TypedRateLimiter[ObjectIdentifiers]
+}
+
+var _ workqueue.TypedRateLimiter[ObjectIdentifiers] = &gvrRateLimiter{}
+
+func newGVRRateLimiter(config Config) *gvrRateLimiter {
+	return &gvrRateLimiter{
+		config:   config,
+		tuned:    make(map[schema.GroupVersionResource]workqueue.TypedRateLimiter[ObjectIdentifiers]),
+		defaults: make(map[schema.GroupVersionResource]workqueue.TypedRateLimiter[ObjectIdentifiers]),
+	}
+}
+
+// When implements workqueue.TypedRateLimiter.
+func (l *gvrRateLimiter) When(oi ObjectIdentifiers) time.Duration {
+	return l.limiterFor(oi.GVR).When(oi)
+}
+
+// Forget implements workqueue.TypedRateLimiter.
+func (l *gvrRateLimiter) Forget(oi ObjectIdentifiers) {
+	l.limiterFor(oi.GVR).Forget(oi)
+}
+
+// NumRequeues implements workqueue.TypedRateLimiter.
+func (l *gvrRateLimiter) NumRequeues(oi ObjectIdentifiers) int {
+	return l.limiterFor(oi.GVR).NumRequeues(oi)
+}
+
+func (l *gvrRateLimiter) limiterFor(gvr schema.GroupVersionResource) workqueue.TypedRateLimiter[ObjectIdentifiers] {
+	l.mu.RLock()
+	limiter, ok := l.tuned[gvr]
+	if !ok {
+		limiter, ok = l.defaults[gvr]
+	}
+	l.mu.RUnlock()
+	if ok {
+		return limiter
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limiter, ok := l.tuned[gvr]; ok {
+		return limiter
+	}
+	if limiter, ok := l.defaults[gvr]; ok {
+		return limiter
+	}
+	limiter = newRateLimiter(l.config)
+	l.defaults[gvr] = limiter
+	return limiter
+}
+
+// set replaces the rate limiters. The parent GVRs missing from tuned get new
+// rate limiters created from config.
+func (l *gvrRateLimiter) set(
+	config Config,
+	tuned map[schema.GroupVersionResource]workqueue.TypedRateLimiter[ObjectIdentifiers],
+) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = config
+	l.tuned = tuned
+	l.defaults = make(map[schema.GroupVersionResource]workqueue.TypedRateLimiter[ObjectIdentifiers])
+}
+
+// setGVR replaces the rate limiter of a parent GVR. If limiter is nil, the
+// items of the GVR get a new rate limiter created from config.
+func (l *gvrRateLimiter) setGVR(gvr schema.GroupVersionResource, limiter workqueue.TypedRateLimiter[ObjectIdentifiers]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.defaults, gvr)
+	if limiter == nil {
+		delete(l.tuned, gvr)
+		return
+	}
+	l.tuned[gvr] = limiter
+}
+
+// forget drops the rate limiters of a parent GVR.
+func (l *gvrRateLimiter) forget(gvr schema.GroupVersionResource) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.tuned, gvr)
+	delete(l.defaults, gvr)
+}
+
+// fairQueue implements workqueue.Queue with a FIFO sub-queue per parent GVR.
+// Sub-queues are served round-robin, each serving as many items per turn as
+// the priority of its GVR, so that a GVR with many queued instances does not
+// starve the others.
+//
+// The workqueue calls its methods with its own lock held. mu only guards
+// against snapshot, which is called without it.
+type fairQueue struct {
+	// priority returns the priority of a parent GVR.
+	priority func(schema.GroupVersionResource) Priority
+	now      func() time.Time
+
+	mu     sync.Mutex
+	queues map[schema.GroupVersionResource][]queuedItem
+	// active holds the GVRs with queued items, in the order they are served.
+	active []schema.GroupVersionResource
+	// served is the number of items served by active[0] in its current turn.
+	served int
+	len    int
+}
+
+type queuedItem struct {
+	oi    ObjectIdentifiers
+	added time.Time
+}
+
+var _ workqueue.Queue[ObjectIdentifiers] = &fairQueue{}
+
+func newFairQueue(priority func(schema.GroupVersionResource) Priority) *fairQueue {
+	return &fairQueue{
+		priority: priority,
+		now:      time.Now,
+		queues:   make(map[schema.GroupVersionResource][]queuedItem),
+	}
+}
+
+// Touch implements workqueue.Queue. Items queued again keep their place.
+func (q *fairQueue) Touch(ObjectIdentifiers) {}
+
+// Push implements workqueue.Queue.
+func (q *fairQueue) Push(oi ObjectIdentifiers) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items, ok := q.queues[oi.GVR]
+	if !ok {
+		q.active = append(q.active, oi.GVR)
+	}
+	q.queues[oi.GVR] = append(items, queuedItem{oi: oi, added: q.now()})
+	q.len++
+	queueDepth.WithLabelValues(keyFromGVR(oi.GVR)).Inc()
+}
+
+// Len implements workqueue.Queue.
+func (q *fairQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.len
+}
+
+// Pop implements workqueue.Queue. It is only called when Len is positive.
+func (q *fairQueue) Pop() ObjectIdentifiers {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	gvr := q.active[0]
+	items := q.queues[gvr]
+	item := items[0]
+	items[0] = queuedItem{}
+	items = items[1:]
+	q.len--
+	q.served++
+
+	switch {
+	case len(items) == 0:
+		delete(q.queues, gvr)
+		q.active = q.active[1:]
+		q.served = 0
+	case q.served >= int(q.priority(gvr)):
+		// End of the turn, serve the next GVR.
+		q.queues[gvr] = items
+		q.active = append(q.active[1:], gvr)
+		q.served = 0
+	default:
+		q.queues[gvr] = items
+	}
+
+	gvrKey := keyFromGVR(gvr)
+	queueDepth.WithLabelValues(gvrKey).Dec()
+	queueWaitDuration.WithLabelValues(gvrKey).Observe(q.now().Sub(item.added).Seconds())
+	return item.oi
+}
+
+// snapshot returns the queued items, in the order of their sub-queues.
+func (q *fairQueue) snapshot() []queuedItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := make([]queuedItem, 0, q.len)
+	for _, gvr := range q.active {
+		items = append(items, q.queues[gvr]...)
+	}
+	return items
+}
+
+// oldest returns when the item queued for the longest was queued, or the
+// zero time if the queue is empty.
+func (q *fairQueue) oldest() time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var oldest time.Time
+	for _, gvr := range q.active {
+		// Sub-queues are FIFO, their first item is their oldest.
+		if added := q.queues[gvr][0].added; oldest.IsZero() || added.Before(oldest) {
+			oldest = added
+		}
+	}
+	return oldest
+}
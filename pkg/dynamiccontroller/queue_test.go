@@ -0,0 +1,120 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	busyGVR  = schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "busies"}
+	quietGVR = schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "quiets"}
+)
+
+func queueItem(gvr schema.GroupVersionResource, name string) ObjectIdentifiers {
+	return ObjectIdentifiers{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}, GVR: gvr}
+}
+
+// popOrder pops every item of the queue and returns their resource and name.
+func popOrder(q *fairQueue) []string {
+	var order []string
+	for q.Len() > 0 {
+		oi := q.Pop()
+		order = append(order, oi.GVR.Resource+"/"+oi.Name)
+	}
+	return order
+}
+
+func TestFairQueue_RoundRobin(t *testing.T) {
+	q := newFairQueue(func(schema.GroupVersionResource) Priority { return PriorityLow })
+	for i := range 3 {
+		q.Push(queueItem(busyGVR, fmt.Sprintf("b%d", i)))
+	}
+	q.Push(queueItem(quietGVR, "q0"))
+	q.Push(queueItem(quietGVR, "q1"))
+
+	assert.Equal(t, []string{"busies/b0", "quiets/q0", "busies/b1", "quiets/q1", "busies/b2"}, popOrder(q))
+	assert.Empty(t, q.active)
+	assert.Empty(t, q.queues)
+}
+
+func TestFairQueue_Priority(t *testing.T) {
+	priorities := map[schema.GroupVersionResource]Priority{busyGVR: PriorityHigh, quietGVR: PriorityLow}
+	q := newFairQueue(func(gvr schema.GroupVersionResource) Priority { return priorities[gvr] })
+	for i := range 6 {
+		q.Push(queueItem(busyGVR, fmt.Sprintf("b%d", i)))
+		q.Push(queueItem(quietGVR, fmt.Sprintf("q%d", i)))
+	}
+
+	order := popOrder(q)
+	assert.Equal(t, []string{
+		"busies/b0", "busies/b1", "busies/b2", "busies/b3", "quiets/q0",
+		"busies/b4", "busies/b5", "quiets/q1",
+	}, order[:8])
+	assert.Len(t, order, 12)
+}
+
+func TestFairQueue_NoStarvation(t *testing.T) {
+	dc := NewDynamicController(noopLogger(), Config{RateLimit: 10, BurstLimit: 100}, nil, nil)
+	for i := range 5000 {
+		dc.queue.Add(queueItem(busyGVR, fmt.Sprintf("b%d", i)))
+	}
+	dc.queue.Add(queueItem(quietGVR, "q0"))
+	// Items are deduplicated by the workqueue.
+	dc.queue.Add(queueItem(busyGVR, "b0"))
+	require.Equal(t, 5001, dc.queue.Len())
+
+	// The quiet GVR is served after a turn of the busy GVR, not after the
+	// 5000 busy instances.
+	var served []string
+	for range int(PriorityNormal) + 1 {
+		item, shutdown := dc.queue.Get()
+		require.False(t, shutdown)
+		served = append(served, item.GVR.Resource)
+		dc.queue.Done(item)
+	}
+	assert.Equal(t, "busies,busies,quiets", strings.Join(served, ","))
+	dc.queue.ShutDown()
+}
+
+func TestDynamicController_SetPriority(t *testing.T) {
+	dc := NewDynamicController(noopLogger(), Config{}, nil, nil)
+	assert.Equal(t, PriorityNormal, dc.priority(busyGVR))
+	dc.SetPriority(busyGVR, PriorityHigh)
+	assert.Equal(t, PriorityHigh, dc.priority(busyGVR))
+}
+
+func TestGVRRateLimiter_BucketPerGVR(t *testing.T) {
+	l := newGVRRateLimiter(Config{MinRetryDelay: time.Millisecond, MaxRetryDelay: time.Minute, RateLimit: 1, BurstLimit: 1})
+
+	// The first item of busies uses its only token, the next one waits for
+	// the bucket to refill.
+	assert.Equal(t, time.Millisecond, l.When(queueItem(busyGVR, "b0")))
+	assert.Greater(t, l.When(queueItem(busyGVR, "b1")), 500*time.Millisecond)
+	// Quiets have their own bucket.
+	assert.Equal(t, time.Millisecond, l.When(queueItem(quietGVR, "q0")))
+
+	l.forget(busyGVR)
+	assert.NotContains(t, l.defaults, busyGVR)
+	assert.Contains(t, l.defaults, quietGVR)
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// busyRequeueDelay is how long an instance waits before being queued
+	// again when its parent GVR already uses every worker it may use.
+	busyRequeueDelay = 100 * time.Millisecond
+	// resyncCheckInterval is the interval at which the parent GVRs tuned with
+	// a resync period are checked for instances due for a resync.
+	resyncCheckInterval = 10 * time.Second
+)
+
+// Tuning overrides the Config for the instances of a parent GVR. Zero fields
+// keep the value of the Config.
+type Tuning struct {
+	// Workers is the maximum number of workers reconciling instances of the
+	// parent GVR at once. Values above Config.Workers have no effect.
+	Workers int
+	// ResyncPeriod is the interval at which every instance of the parent GVR
+	// is reconciled, even if nothing changed.
+	ResyncPeriod time.Duration
+	// MinRetryDelay is the minimum delay before retrying a failed instance.
+	MinRetryDelay time.Duration
+	// MaxRetryDelay is the maximum delay before retrying a failed instance.
+	MaxRetryDelay time.Duration
+	// RateLimit is the maximum number of instances of the parent GVR
+	// processed per second.
+	RateLimit int
+	// BurstLimit is the maximum number of instances of the parent GVR in a
+	// burst.
+	BurstLimit int
+}
+
+// overridesRateLimits returns true if the tuning changes the rate limiting of
+// the instances, which then get a rate limiter of their own.
+func (t Tuning) overridesRateLimits() bool {
+	return t.rateLimits() != Tuning{}
+}
+
+// rateLimits returns the rate limits of the tuning.
+func (t Tuning) rateLimits() Tuning {
+	return Tuning{
+		MinRetryDelay: t.MinRetryDelay,
+		MaxRetryDelay: t.MaxRetryDelay,
+		RateLimit:     t.RateLimit,
+		BurstLimit:    t.BurstLimit,
+	}
+}
+
+// rateLimitConfig returns config with the rate limits of the tuning applied.
+func (t Tuning) rateLimitConfig(config Config) Config {
+	if t.MinRetryDelay > 0 {
+		config.MinRetryDelay = t.MinRetryDelay
+	}
+	if t.MaxRetryDelay > 0 {
+		config.MaxRetryDelay = t.MaxRetryDelay
+	}
+	if t.RateLimit > 0 {
+		config.RateLimit = t.RateLimit
+	}
+	if t.BurstLimit > 0 {
+		config.BurstLimit = t.BurstLimit
+	}
+	return config
+}
+
+// SetTuning overrides the Config for the instances of a parent GVR. A zero
+// Tuning drops the overrides, as does deregistering the parent GVR.
+func (dc *DynamicController) SetTuning(parent schema.GroupVersionResource, tuning Tuning) {
+	dc.tuningMu.Lock()
+	defer dc.tuningMu.Unlock()
+
+	prev, exists := dc.tunings[parent]
+	if exists && prev == tuning {
+		return
+	}
+	if tuning == (Tuning{}) {
+		delete(dc.tunings, parent)
+		delete(dc.lastResyncs, parent)
+	} else {
+		dc.tunings[parent] = tuning
+		if tuning.ResyncPeriod != prev.ResyncPeriod {
+			dc.lastResyncs[parent] = time.Now()
+		}
+	}
+	if prev.rateLimits() != tuning.rateLimits() {
+		dc.rateLimiter.setGVR(parent, dc.tunedRateLimiterLocked(tuning))
+	}
+}
+
+// Reconfigure applies the settings of config that can change while the
+// controller runs: QueueMaxRetries, ProgressDeadline and the rate limits. Other
+// settings are ignored. The backoff of failing instances is reset.
+func (dc *DynamicController) Reconfigure(config Config) {
+	dc.tuningMu.Lock()
+	defer dc.tuningMu.Unlock()
+
+	dc.config.QueueMaxRetries = config.QueueMaxRetries
+	dc.config.ProgressDeadline = config.ProgressDeadline
+	dc.config.MinRetryDelay = config.MinRetryDelay
+	dc.config.MaxRetryDelay = config.MaxRetryDelay
+	dc.config.RateLimit = config.RateLimit
+	dc.config.BurstLimit = config.BurstLimit
+
+	limiters := make(map[schema.GroupVersionResource]workqueue.TypedRateLimiter[ObjectIdentifiers])
+	for gvr, tuning := range dc.tunings {
+		if limiter := dc.tunedRateLimiterLocked(tuning); limiter != nil {
+			limiters[gvr] = limiter
+		}
+	}
+	dc.rateLimiter.set(dc.config, limiters)
+}
+
+// tunedRateLimiterLocked returns the rate limiter of the instances of a tuned
+// parent GVR, or nil if they use the default rate limits.
+// Must be called with dc.tuningMu held.
+func (dc *DynamicController) tunedRateLimiterLocked(tuning Tuning) workqueue.TypedRateLimiter[ObjectIdentifiers] {
+	if !tuning.overridesRateLimits() {
+		return nil
+	}
+	return newRateLimiter(tuning.rateLimitConfig(dc.config))
+}
+
+// queueMaxRetries returns Config.QueueMaxRetries, which Reconfigure changes.
+func (dc *DynamicController) queueMaxRetries() int {
+	dc.tuningMu.Lock()
+	defer dc.tuningMu.Unlock()
+	return dc.config.QueueMaxRetries
+}
+
+// progressDeadline returns Config.ProgressDeadline, which Reconfigure changes.
+func (dc *DynamicController) progressDeadline() time.Duration {
+	dc.tuningMu.Lock()
+	defer dc.tuningMu.Unlock()
+	return dc.config.ProgressDeadline
+}
+
+// acquireWorker returns true if a worker may reconcile an instance of the
+// parent GVR, in which case releaseWorker must be called once it is done.
+func (dc *DynamicController) acquireWorker(parent schema.GroupVersionResource) bool {
+	dc.tuningMu.Lock()
+	defer dc.tuningMu.Unlock()
+	if workers := dc.tunings[parent].Workers; workers > 0 && dc.busyWorkers[parent] >= workers {
+		return false
+	}
+	dc.busyWorkers[parent]++
+	return true
+}
+
+func (dc *DynamicController) releaseWorker(parent schema.GroupVersionResource) {
+	dc.tuningMu.Lock()
+	defer dc.tuningMu.Unlock()
+	if dc.busyWorkers[parent]--; dc.busyWorkers[parent] <= 0 {
+		delete(dc.busyWorkers, parent)
+	}
+}
+
+// resyncTuned enqueues every instance of the parent GVRs whose resync period
+// elapsed since they were last resynced.
+func (dc *DynamicController) resyncTuned(_ context.Context) {
+	now := time.Now()
+	var due []schema.GroupVersionResource
+	dc.tuningMu.Lock()
+	for gvr, tuning := range dc.tunings {
+		if tuning.ResyncPeriod > 0 && now.Sub(dc.lastResyncs[gvr]) >= tuning.ResyncPeriod {
+			dc.lastResyncs[gvr] = now
+			due = append(due, gvr)
+		}
+	}
+	dc.tuningMu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	for _, gvr := range due {
+		if _, ok := dc.registrations[gvr]; ok {
+			dc.log.V(1).Info("Resyncing instances", "gvr", keyFromGVR(gvr))
+			dc.enqueueAllLocked(gvr)
+		}
+	}
+}
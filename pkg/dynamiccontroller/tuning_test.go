@@ -0,0 +1,100 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamiccontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+)
+
+func TestDynamicController_TuningRateLimits(t *testing.T) {
+	dc := NewDynamicController(noopLogger(), Config{
+		MinRetryDelay: time.Millisecond,
+		MaxRetryDelay: time.Minute,
+		RateLimit:     1000,
+		BurstLimit:    1000,
+	}, nil, nil)
+	busy, quiet := queueItem(busyGVR, "b0"), queueItem(quietGVR, "q0")
+
+	dc.SetTuning(busyGVR, Tuning{MinRetryDelay: time.Second})
+	assert.Equal(t, time.Second, dc.rateLimiter.When(busy))
+	assert.Equal(t, 2*time.Second, dc.rateLimiter.When(busy))
+	assert.Equal(t, time.Millisecond, dc.rateLimiter.When(quiet))
+	assert.Equal(t, 2, dc.queue.NumRequeues(busy))
+
+	// Tuning something else than the rate limits keeps the backoff.
+	dc.SetTuning(busyGVR, Tuning{MinRetryDelay: time.Second, Workers: 1})
+	assert.Equal(t, 2, dc.queue.NumRequeues(busy))
+
+	// The defaults apply to tuned GVRs too.
+	dc.Reconfigure(Config{MinRetryDelay: 5 * time.Millisecond, MaxRetryDelay: 1500 * time.Millisecond,
+		RateLimit: 1000, BurstLimit: 1000})
+	assert.Equal(t, 5*time.Millisecond, dc.rateLimiter.When(quiet))
+	assert.Equal(t, time.Second, dc.rateLimiter.When(busy))
+	assert.Equal(t, 1500*time.Millisecond, dc.rateLimiter.When(busy))
+
+	dc.SetTuning(busyGVR, Tuning{})
+	assert.Equal(t, 5*time.Millisecond, dc.rateLimiter.When(busy))
+}
+
+func TestDynamicController_TuningWorkers(t *testing.T) {
+	dc := NewDynamicController(noopLogger(), Config{RateLimit: 10, BurstLimit: 100}, nil, nil)
+	dc.SetTuning(busyGVR, Tuning{Workers: 1})
+
+	require.True(t, dc.acquireWorker(busyGVR))
+	assert.False(t, dc.acquireWorker(busyGVR))
+	// Other GVRs are not limited.
+	require.True(t, dc.acquireWorker(quietGVR))
+	require.True(t, dc.acquireWorker(quietGVR))
+
+	dc.releaseWorker(busyGVR)
+	assert.True(t, dc.acquireWorker(busyGVR))
+}
+
+func TestDynamicController_TuningResyncPeriod(t *testing.T) {
+	client, mapper := setupFakeClient(t)
+	gvr := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "tests"}
+
+	dc := NewDynamicController(noopLogger(), Config{RateLimit: 10, BurstLimit: 100}, client, mapper)
+	dc.ctx = t.Context() // simulate a start through dc.Run
+
+	handler := Handler(func(context.Context, controllerruntime.Request) error { return nil })
+	require.NoError(t, dc.Register(t.Context(), gvr, handler))
+	for dc.queue.Len() > 0 {
+		item, _ := dc.queue.Get()
+		dc.queue.Done(item)
+	}
+
+	dc.SetTuning(gvr, Tuning{ResyncPeriod: time.Hour})
+	dc.resyncTuned(t.Context())
+	assert.Zero(t, dc.queue.Len(), "instances are not resynced before the period elapsed")
+
+	dc.tuningMu.Lock()
+	dc.lastResyncs[gvr] = time.Now().Add(-time.Hour)
+	dc.tuningMu.Unlock()
+	dc.resyncTuned(t.Context())
+	assert.NotZero(t, dc.queue.Len())
+
+	require.NoError(t, dc.Deregister(t.Context(), gvr))
+	dc.tuningMu.Lock()
+	assert.Empty(t, dc.tunings)
+	dc.tuningMu.Unlock()
+}
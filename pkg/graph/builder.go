@@ -15,16 +15,21 @@
 package graph
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/cel-go/cel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/maps"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/apiserver/pkg/cel/openapi"
 	"k8s.io/apiserver/pkg/cel/openapi/resolver"
@@ -44,6 +49,7 @@ import (
 	"github.com/kubernetes-sigs/kro/pkg/graph/variable"
 	"github.com/kubernetes-sigs/kro/pkg/metadata"
 	"github.com/kubernetes-sigs/kro/pkg/simpleschema"
+	"github.com/kubernetes-sigs/kro/pkg/tracing"
 )
 
 // NewBuilder creates a new GraphBuilder instance.
@@ -99,6 +105,32 @@ type Builder struct {
 // of the resource graph definition CRD, it's underlying resources, and the relationships between
 // the resources.
 func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphDefinition) (*Graph, error) {
+	return b.NewResourceGraphDefinitionContext(context.Background(), originalCR)
+}
+
+// NewResourceGraphDefinitionContext is like NewResourceGraphDefinition, and
+// traces the build phases as children of the span in ctx.
+func (b *Builder) NewResourceGraphDefinitionContext(
+	ctx context.Context,
+	originalCR *v1alpha1.ResourceGraphDefinition,
+) (_ *Graph, err error) {
+	ctx, span := tracing.Start(ctx, "Builder.NewResourceGraphDefinition",
+		attribute.String("name", originalCR.Name))
+	var phase trace.Span
+	startPhase := func(name string) {
+		if phase != nil {
+			phase.End()
+		}
+		_, phase = tracing.Start(ctx, name)
+	}
+	defer func() {
+		// the phase that failed, if any, is the one still running.
+		if phase != nil {
+			tracing.End(phase, err)
+		}
+		tracing.End(span, err)
+	}()
+
 	// Before anything else, let's copy the resource graph definition to avoid modifying the
 	// original object.
 	rgd := originalCR.DeepCopy()
@@ -110,7 +142,7 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 	//    that the names of the resources are valid to be used in CEL expressions.
 	//    for example name-something-something is not a valid name for a resource,
 	//    because in CEL - is a subtraction operator.
-	err := validateResourceGraphDefinitionNamingConventions(rgd)
+	err = validateResourceGraphDefinitionNamingConventions(rgd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to validate resourcegraphdefinition: %w", err)
 	}
@@ -128,16 +160,17 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 
 	// we'll also store the nodes and schemas in maps for easy access later.
 	// Schemas are only needed during build for CEL validation.
+	startPhase("Builder.ResolveSchemas")
 	nodes := make(map[string]*Node)
 	schemas := make(map[string]*spec.Schema)
 	for i, rgResource := range rgd.Spec.Resources {
 		id := rgResource.ID
 		node, nodeSchema, err := b.buildRGResource(rgResource, i)
 		if err != nil {
-			return nil, fmt.Errorf("failed to build resource %q: %w", id, err)
+			return nil, fmt.Errorf("failed to build resource %q: %w", id, resourceError(id, err))
 		}
 		if nodes[id] != nil {
-			return nil, fmt.Errorf("found resources with duplicate id %q", id)
+			return nil, resourceFieldError(id, "id", fmt.Errorf("found resources with duplicate id %q", id))
 		}
 		nodes[id] = node
 		schemas[id] = nodeSchema
@@ -182,6 +215,7 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 	// 3. Validate them against the resources defined in the resource graph definition.
 	// 4. Infer the status schema based on the CEL expressions.
 
+	startPhase("Builder.ParseInstance")
 	instance, instanceCRD, err := b.buildInstanceNode(
 		rgd.Spec.Schema.Group,
 		rgd.Spec.Schema.APIVersion,
@@ -193,7 +227,23 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 		schemas,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build resourcegraphdefinition '%v': %w", rgd.Name, err)
+		return nil, fmt.Errorf("failed to build resourcegraphdefinition '%v': %w", rgd.Name, specError("schema", err))
+	}
+
+	// One-shot instances finish once their completion expressions are met.
+	// The resources these expressions reference become dependencies of the
+	// instance node, so that their observed state is available to them.
+	instance.Completion, err = parseCompletion(rgd.Spec.Completion, instance, nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse completion: %w", specError("completion", err))
+	}
+
+	// If the schema declares additional versions, add them to the CRD and
+	// type check their conversion mappings. The instance node always refers
+	// to the storage version, this is the only version kro reconciles.
+	converter, err := buildVersions(rgd.Spec.Schema, instanceCRD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build schema versions: %w", specFieldError("schema.versions", err))
 	}
 
 	// Prepare schemas for CEL type checking.
@@ -222,21 +272,26 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 	//
 	// We do this BEFORE type checking so that undeclared resource errors
 	// are caught here with clear messages, rather than as CEL type errors.
+	startPhase("Builder.BuildDAG")
 	dag, err := b.buildDependencyGraph(nodes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+		return nil, fmt.Errorf("failed to build dependency graph: %w", specError("resources", err))
 	}
 	// Ensure the graph is acyclic and get the topological order of resources.
 	topologicalOrder, err := dag.TopologicalSort()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get topological order: %w", err)
+		return nil, fmt.Errorf("failed to get topological order: %w", specFieldError("resources", err))
 	}
 
 	// Now that we know all resources are properly declared and dependencies are valid,
 	// we can perform type checking on the CEL expressions.
+	startPhase("Builder.TypeCheck")
 
 	// Create a typed CEL environment with all resource schemas for template expressions
-	templatesEnv, err := krocel.TypedEnvironment(celSchemas)
+	templatesEnv, err := krocel.DefaultEnvironment(
+		krocel.WithTypedResources(celSchemas),
+		krocel.WithOptionalResources(optionalValueNodeIDs(nodes)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create typed CEL environment: %w", err)
 	}
@@ -253,9 +308,12 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 	// Validate all CEL expressions for each node
 	for id, node := range nodes {
 		if err := validateNode(node, templatesEnv, schemaEnv, schemas[id], typeProvider); err != nil {
-			return nil, fmt.Errorf("failed to validate resource %q: %w", id, err)
+			return nil, fmt.Errorf("failed to validate resource %q: %w", id, resourceError(id, err))
 		}
 	}
+	if err := validateCompletionExpressions(templatesEnv, instance.Completion); err != nil {
+		return nil, fmt.Errorf("failed to validate completion: %w", specError("completion", err))
+	}
 
 	resourceGraphDefinition := &Graph{
 		DAG:              dag,
@@ -264,24 +322,41 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 		Resources:        nodes,
 		TopologicalOrder: topologicalOrder,
 		CRD:              instanceCRD,
+		Converter:        converter,
 	}
 	return resourceGraphDefinition, nil
 }
 
 // buildExternalRefResource builds an empty resource with metadata from the given externalRef definition.
+// The label selector and the default object of external refs are carried at the top level of the resource.
 func (b *Builder) buildExternalRefResource(
-	externalRef *v1alpha1.ExternalRef) map[string]interface{} {
+	externalRef *v1alpha1.ExternalRef) (map[string]interface{}, error) {
 	resourceObject := map[string]interface{}{}
 	resourceObject["apiVersion"] = externalRef.APIVersion
 	resourceObject["kind"] = externalRef.Kind
-	metadata := map[string]interface{}{
-		"name": externalRef.Metadata.Name,
+	metadata := map[string]interface{}{}
+	if externalRef.Metadata.Name != "" {
+		metadata["name"] = externalRef.Metadata.Name
 	}
 	if externalRef.Metadata.Namespace != "" {
 		metadata["namespace"] = externalRef.Metadata.Namespace
 	}
 	resourceObject["metadata"] = metadata
-	return resourceObject
+	if externalRef.Metadata.Selector != nil {
+		selector, err := runtime.DefaultUnstructuredConverter.ToUnstructured(externalRef.Metadata.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert selector: %w", err)
+		}
+		resourceObject[ExternalSelectorPath] = selector
+	}
+	if externalRef.Default != nil {
+		defaultObject := map[string]interface{}{}
+		if err := yaml.UnmarshalStrict(externalRef.Default.Raw, &defaultObject); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal default: %w", err)
+		}
+		resourceObject[ExternalDefaultPath] = defaultObject
+	}
+	return resourceObject, nil
 }
 
 // buildRGResource builds a node from the given resource definition.
@@ -306,7 +381,12 @@ func (b *Builder) buildRGResource(
 			return nil, nil, fmt.Errorf("failed to unmarshal resource %s: %w", rgResource.ID, err)
 		}
 	} else {
-		resourceObject = b.buildExternalRefResource(rgResource.ExternalRef)
+		var err error
+		resourceObject, err = b.buildExternalRefResource(rgResource.ExternalRef)
+		if err != nil {
+			return nil, nil, resourceFieldError(rgResource.ID, "externalRef",
+				fmt.Errorf("failed to build external reference %s: %w", rgResource.ID, err))
+		}
 	}
 
 	// 3. Check if it looks like a valid Kubernetes resource.
@@ -328,6 +408,17 @@ func (b *Builder) buildRGResource(
 	}
 
 	// 6. Extract CEL fieldDescriptors from the resource.
+	// The label selector and the default object of external refs are not
+	// part of the resource itself, so they are parsed separately.
+	externalFields := map[string]interface{}{}
+	if rgResource.ExternalRef != nil {
+		for _, path := range []string{ExternalSelectorPath, ExternalDefaultPath} {
+			if value, ok := resourceObject[path]; ok {
+				externalFields[path] = value
+				delete(resourceObject, path)
+			}
+		}
+	}
 	var fieldDescriptors []variable.FieldDescriptor
 	if gvk.Group == "apiextensions.k8s.io" && gvk.Version == "v1" && gvk.Kind == "CustomResourceDefinition" {
 		fieldDescriptors, err = parser.ParseSchemalessResource(resourceObject)
@@ -351,6 +442,17 @@ func (b *Builder) buildRGResource(
 			setExpectedTypeOnDescriptor(&fieldDescriptors[i], resourceSchema, rgResource.ID)
 		}
 	}
+	if len(externalFields) > 0 {
+		externalDescriptors, err := parseExternalRefFields(externalFields, resourceSchema, rgResource.ID)
+		if err != nil {
+			return nil, nil, resourceFieldError(rgResource.ID, "externalRef",
+				fmt.Errorf("failed to parse external reference %s: %w", rgResource.ID, err))
+		}
+		fieldDescriptors = append(fieldDescriptors, externalDescriptors...)
+		for path, value := range externalFields {
+			resourceObject[path] = value
+		}
+	}
 
 	templateVariables := make([]*variable.ResourceField, 0, len(fieldDescriptors))
 	for _, fieldDescriptor := range fieldDescriptors {
@@ -364,19 +466,43 @@ func (b *Builder) buildRGResource(
 	// 7. Parse ReadyWhen expressions
 	readyWhen, err := parser.ParseConditionExpressions(rgResource.ReadyWhen)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse readyWhen expressions: %v", err)
+		return nil, nil, resourceFieldError(rgResource.ID, "readyWhen", fmt.Errorf("failed to parse readyWhen expressions: %v", err))
 	}
 
 	// 8. Parse condition expressions
 	includeWhen, err := parser.ParseConditionExpressions(rgResource.IncludeWhen)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse includeWhen expressions: %v", err)
+		return nil, nil, resourceFieldError(rgResource.ID, "includeWhen", fmt.Errorf("failed to parse includeWhen expressions: %v", err))
 	}
 
 	// 9. Parse forEach dimensions
 	forEachDimensions, err := parseForEachDimensions(rgResource.ForEach)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse forEach dimensions: %v", err)
+		return nil, nil, resourceFieldError(rgResource.ID, "forEach", fmt.Errorf("failed to parse forEach dimensions: %v", err))
+	}
+
+	// 10. Parse the drift policy
+	drift, err := parseDriftPolicy(rgResource)
+	if err != nil {
+		return nil, nil, resourceFieldError(rgResource.ID, "drift", fmt.Errorf("failed to parse drift policy: %w", err))
+	}
+
+	// 11. Parse the ignored fields
+	ignoreFields, err := parseIgnoreFields(rgResource)
+	if err != nil {
+		return nil, nil, resourceFieldError(rgResource.ID, "ignoreFields", fmt.Errorf("failed to parse ignoreFields: %w", err))
+	}
+
+	// 12. Parse the update policy
+	updatePolicy, err := parseUpdatePolicy(rgResource)
+	if err != nil {
+		return nil, nil, resourceFieldError(rgResource.ID, "updatePolicy", fmt.Errorf("failed to parse updatePolicy: %w", err))
+	}
+
+	// 13. Parse the hook
+	hook, err := parseHook(rgResource)
+	if err != nil {
+		return nil, nil, resourceFieldError(rgResource.ID, "hook", fmt.Errorf("failed to parse hook: %w", err))
 	}
 
 	mapping, err := b.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
@@ -386,7 +512,9 @@ func (b *Builder) buildRGResource(
 
 	// Determine node type
 	nodeType := NodeTypeResource
-	if rgResource.ExternalRef != nil {
+	if rgResource.ExternalRef != nil && rgResource.ExternalRef.Metadata.Selector != nil {
+		nodeType = NodeTypeExternalCollection
+	} else if rgResource.ExternalRef != nil {
 		nodeType = NodeTypeExternal
 	} else if len(forEachDimensions) > 0 {
 		nodeType = NodeTypeCollection
@@ -400,17 +528,61 @@ func (b *Builder) buildRGResource(
 			Type:       nodeType,
 			GVR:        mapping.Resource,
 			Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+			Optional:   rgResource.ExternalRef != nil && rgResource.ExternalRef.Optional,
 			// Dependencies will be set by buildDependencyGraph
 		},
-		Template:    &unstructured.Unstructured{Object: resourceObject},
-		Variables:   templateVariables,
-		IncludeWhen: includeWhen,
-		ReadyWhen:   readyWhen,
-		ForEach:     forEachDimensions,
+		Template:     &unstructured.Unstructured{Object: resourceObject},
+		Variables:    templateVariables,
+		IncludeWhen:  includeWhen,
+		ReadyWhen:    readyWhen,
+		ForEach:      forEachDimensions,
+		Drift:        drift,
+		IgnoreFields: ignoreFields,
+		UpdatePolicy: updatePolicy,
+		Hook:         hook,
 	}
 	return node, resourceSchema, nil
 }
 
+// parseExternalRefFields extracts the CEL expressions from the label selector
+// and the default object of an external reference.
+func parseExternalRefFields(
+	fields map[string]interface{},
+	resourceSchema *spec.Schema,
+	resourceID string,
+) ([]variable.FieldDescriptor, error) {
+	var descriptors []variable.FieldDescriptor
+	if selector, ok := fields[ExternalSelectorPath]; ok {
+		selectorDescriptors, err := parser.ParseSchemalessResource(map[string]interface{}{ExternalSelectorPath: selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse selector: %w", err)
+		}
+		// Label keys and values are always strings.
+		for i := range selectorDescriptors {
+			selectorDescriptors[i].ExpectedType = cel.StringType
+		}
+		descriptors = append(descriptors, selectorDescriptors...)
+	}
+	if defaultObject, ok := fields[ExternalDefaultPath]; ok {
+		// The default object follows the schema of the referenced kind.
+		defaultSchema := &spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:       []string{"object"},
+				Properties: map[string]spec.Schema{ExternalDefaultPath: *resourceSchema},
+			},
+		}
+		defaultDescriptors, err := parser.ParseResource(map[string]interface{}{ExternalDefaultPath: defaultObject}, defaultSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse default: %w", err)
+		}
+		for i := range defaultDescriptors {
+			setExpectedTypeOnDescriptor(&defaultDescriptors[i], defaultSchema, resourceID)
+		}
+		descriptors = append(descriptors, defaultDescriptors...)
+	}
+	return descriptors, nil
+}
+
 // buildDependencyGraph builds the dependency graph between the nodes in the
 // resource graph definition. The dependency graph is a directed acyclic graph
 // that represents the relationships between the nodes. The graph is used
@@ -454,16 +626,16 @@ func (b *Builder) buildDependencyGraph(
 				}
 			}
 			if len(missing) > 0 {
-				return nil, fmt.Errorf(
+				return nil, resourceFieldError(node.Meta.ID, "forEach", fmt.Errorf(
 					"node %q: all forEach dimensions must be used to produce a unique resource identity, missing: %v",
 					node.Meta.ID, missing,
-				)
+				))
 			}
 		}
 
 		forEachDeps, err := extractForEachDependencies(env, node, nodeNames, iteratorNames)
 		if err != nil {
-			return nil, err
+			return nil, resourceFieldError(node.Meta.ID, "forEach", err)
 		}
 
 		// Add all dependencies to node and DAG
@@ -476,9 +648,83 @@ func (b *Builder) buildDependencyGraph(
 		}
 	}
 
+	if err := addHookDependencies(nodes, directedAcyclicGraph); err != nil {
+		return nil, err
+	}
+
 	return directedAcyclicGraph, nil
 }
 
+// addHookDependencies orders the hooks with the other nodes of the graph.
+// Managed nodes wait for the PreCreate hooks they are not a dependency of, and
+// PostReady hooks wait for the managed nodes that do not depend on them.
+// PreDelete hooks run outside of the graph: they can only reference the
+// instance, and cannot be referenced.
+func addHookDependencies(nodes map[string]*Node, d *dag.DirectedAcyclicGraph[string]) error {
+	ids := maps.Keys(nodes)
+	slices.Sort(ids)
+	for _, id := range ids {
+		node := nodes[id]
+		if node.IsHook(HookPhasePreDelete) && len(node.Meta.Dependencies) > 0 {
+			return resourceFieldError(id, "", fmt.Errorf("pre-delete hook %q can only reference the instance", id))
+		}
+		for _, dep := range node.Meta.Dependencies {
+			if nodes[dep].IsHook(HookPhasePreDelete) {
+				return resourceFieldError(id, "", fmt.Errorf("resource %q cannot reference the pre-delete hook %q", id, dep))
+			}
+		}
+	}
+
+	isManaged := func(node *Node) bool {
+		return node.Hook == nil && !node.Meta.Type.IsExternal()
+	}
+	for _, phase := range []HookPhase{HookPhasePreCreate, HookPhasePostReady} {
+		for _, hookID := range ids {
+			if !nodes[hookID].IsHook(phase) {
+				continue
+			}
+			for _, id := range ids {
+				node := nodes[id]
+				if !isManaged(node) {
+					continue
+				}
+				from, to := id, hookID
+				if phase == HookPhasePostReady {
+					from, to = hookID, id
+				}
+				// Nodes on the other side of the hook are left alone.
+				if dependsOn(d, to, from) {
+					continue
+				}
+				nodes[from].Meta.WaitsFor = append(nodes[from].Meta.WaitsFor, to)
+				if err := d.AddDependencies(from, []string{to}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// dependsOn returns true if from depends on to, directly or not.
+func dependsOn(d *dag.DirectedAcyclicGraph[string], from, to string) bool {
+	seen := map[string]bool{}
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		if seen[id] {
+			return false
+		}
+		seen[id] = true
+		for dep := range d.Vertices[id].DependsOn {
+			if dep == to || visit(dep) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(from)
+}
+
 // collectIteratorNames returns the iterator variable names for a node's forEach.
 func collectIteratorNames(node *Node) []string {
 	names := make([]string, 0, len(node.ForEach))
@@ -507,7 +753,8 @@ func extractTemplateDependencies(
 		for _, expression := range templateVariable.Expressions {
 			nodeDeps, iteratorRefs, err := extractDependencies(env, expression, nodeNames, iteratorNames)
 			if err != nil {
-				return nil, nil, fmt.Errorf("failed to extract dependencies: %w", err)
+				return nil, nil, resourceFieldError(node.Meta.ID, templateField(node, templateVariable.Path),
+					fmt.Errorf("failed to extract dependencies: %w", err))
 			}
 
 			// Promote variable Kind based on expression references.
@@ -635,7 +882,8 @@ func (b *Builder) buildInstanceNode(
 		for _, expr := range statusVariable.Expressions {
 			deps, _, err := extractDependencies(env, expr, nodeNames, nil)
 			if err != nil {
-				return nil, nil, fmt.Errorf("failed to extract dependencies from expression %q: %w", expr, err)
+				return nil, nil, specFieldError("schema."+path,
+					fmt.Errorf("failed to extract dependencies from expression %q: %w", expr, err))
 			}
 			for _, dep := range deps {
 				if !slices.Contains(resourceDeps, dep) {
@@ -644,7 +892,8 @@ func (b *Builder) buildInstanceNode(
 			}
 		}
 		if len(resourceDeps) == 0 {
-			return nil, nil, fmt.Errorf("instance status field must refer to a resource: %s", statusVariable.Path)
+			return nil, nil, specFieldError("schema."+path,
+				fmt.Errorf("instance status field must refer to a resource: %s", statusVariable.Path))
 		}
 		instanceDeps = append(instanceDeps, resourceDeps...)
 
@@ -662,7 +911,7 @@ func (b *Builder) buildInstanceNode(
 			ID:           InstanceNodeID,
 			Type:         NodeTypeInstance,
 			GVR:          gvr,
-			Namespaced:   true, // Instances are always namespaced
+			Namespaced:   !rgDefinition.IsClusterScoped(),
 			Dependencies: instanceDeps,
 		},
 		Template: &unstructured.Unstructured{
@@ -734,7 +983,10 @@ func buildStatusSchema(
 
 	schemas := collectNodeSchemas(nodes, nodeSchemas)
 
-	env, err := krocel.TypedEnvironment(schemas)
+	env, err := krocel.DefaultEnvironment(
+		krocel.WithTypedResources(schemas),
+		krocel.WithOptionalResources(optionalValueNodeIDs(nodes)),
+	)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to create typed CEL environment: %w", err)
 	}
@@ -750,7 +1002,8 @@ func buildStatusSchema(
 
 			checkedAST, err := parseAndCheckCELExpression(env, expression)
 			if err != nil {
-				return nil, nil, nil, fmt.Errorf("failed to type-check status expression %q at path %q: %w", expression, fieldDescriptor.Path, err)
+				return nil, nil, nil, specFieldError("schema.status."+fieldDescriptor.Path,
+					fmt.Errorf("failed to type-check status expression %q at path %q: %w", expression, fieldDescriptor.Path, err))
 			}
 
 			statusTypeMap[fieldDescriptor.Path] = checkedAST.OutputType()
@@ -759,12 +1012,13 @@ func buildStatusSchema(
 			for _, expression := range fieldDescriptor.Expressions {
 				checkedAST, err := parseAndCheckCELExpression(env, expression)
 				if err != nil {
-					return nil, nil, nil, fmt.Errorf("failed to type-check status expression %q at path %q: %w", expression, fieldDescriptor.Path, err)
+					return nil, nil, nil, specFieldError("schema.status."+fieldDescriptor.Path,
+						fmt.Errorf("failed to type-check status expression %q at path %q: %w", expression, fieldDescriptor.Path, err))
 				}
 
 				outputType := checkedAST.OutputType()
 				if err := validateExpressionType(outputType, cel.StringType, expression, "status", fieldDescriptor.Path, provider); err != nil {
-					return nil, nil, nil, err
+					return nil, nil, nil, specFieldError("schema.status."+fieldDescriptor.Path, err)
 				}
 			}
 			statusTypeMap[fieldDescriptor.Path] = cel.StringType
@@ -857,6 +1111,179 @@ func parseForEachDimensions(apiDimensions []v1alpha1.ForEachDimension) ([]ForEac
 	return result, nil
 }
 
+// parseDriftPolicy validates the drift policy of a resource.
+func parseDriftPolicy(rgResource *v1alpha1.Resource) (DriftPolicy, error) {
+	policy := rgResource.Drift
+	if policy == nil {
+		return DriftPolicy{}, nil
+	}
+	if rgResource.ExternalRef != nil {
+		return DriftPolicy{}, fmt.Errorf("drift policies are not supported on external references")
+	}
+	if err := validateFieldPaths(policy.Ignore); err != nil {
+		return DriftPolicy{}, err
+	}
+	return DriftPolicy{
+		Report: policy.Action == v1alpha1.DriftActionReport,
+		Ignore: slices.Clone(policy.Ignore),
+	}, nil
+}
+
+// parseIgnoreFields validates the field paths kro releases the ownership of
+// once a resource exists.
+func parseIgnoreFields(rgResource *v1alpha1.Resource) ([]string, error) {
+	if len(rgResource.IgnoreFields) == 0 {
+		return nil, nil
+	}
+	if rgResource.ExternalRef != nil {
+		return nil, fmt.Errorf("ignoreFields is not supported on external references")
+	}
+	if err := validateFieldPaths(rgResource.IgnoreFields); err != nil {
+		return nil, err
+	}
+	return slices.Clone(rgResource.IgnoreFields), nil
+}
+
+// parseUpdatePolicy converts the update policy of a resource.
+func parseUpdatePolicy(rgResource *v1alpha1.Resource) (UpdatePolicy, error) {
+	switch rgResource.UpdatePolicy {
+	case "", v1alpha1.UpdatePolicyUpdate:
+		return UpdatePolicyUpdate, nil
+	case v1alpha1.UpdatePolicyRecreate, v1alpha1.UpdatePolicyRecreateOnImmutableError:
+		if rgResource.ExternalRef != nil {
+			return UpdatePolicyUpdate, fmt.Errorf("update policies are not supported on external references")
+		}
+		if rgResource.UpdatePolicy == v1alpha1.UpdatePolicyRecreate {
+			return UpdatePolicyRecreate, nil
+		}
+		return UpdatePolicyRecreateOnImmutableError, nil
+	default:
+		return UpdatePolicyUpdate, fmt.Errorf("unknown update policy %q", rgResource.UpdatePolicy)
+	}
+}
+
+// parseHook converts the hook of a resource.
+func parseHook(rgResource *v1alpha1.Resource) (*Hook, error) {
+	hook := rgResource.Hook
+	if hook == nil {
+		return nil, nil
+	}
+	if rgResource.ExternalRef != nil {
+		return nil, fmt.Errorf("hooks are not supported on external references")
+	}
+	if len(rgResource.ForEach) > 0 {
+		return nil, fmt.Errorf("hooks are not supported on collections")
+	}
+	if rgResource.UpdatePolicy != "" && rgResource.UpdatePolicy != v1alpha1.UpdatePolicyUpdate {
+		return nil, fmt.Errorf("hooks are recreated on every run and cannot set an update policy")
+	}
+
+	parsed := &Hook{DeleteOnCompletion: hook.CleanupPolicy == v1alpha1.HookCleanupDeleteOnCompletion}
+	switch hook.Phase {
+	case v1alpha1.HookPhasePreCreate:
+		parsed.Phase = HookPhasePreCreate
+	case v1alpha1.HookPhasePostReady:
+		parsed.Phase = HookPhasePostReady
+	case v1alpha1.HookPhasePreDelete:
+		parsed.Phase = HookPhasePreDelete
+	default:
+		return nil, fmt.Errorf("unknown hook phase %q", hook.Phase)
+	}
+	switch hook.CleanupPolicy {
+	case "", v1alpha1.HookCleanupRetain, v1alpha1.HookCleanupDeleteOnCompletion:
+	default:
+		return nil, fmt.Errorf("unknown hook cleanup policy %q", hook.CleanupPolicy)
+	}
+	return parsed, nil
+}
+
+// parseCompletion parses the completion policy of the resource graph
+// definition, and adds the resources its expressions reference to the
+// dependencies of the instance node.
+func parseCompletion(policy *v1alpha1.CompletionPolicy, instance *Node, nodes map[string]*Node) (*Completion, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	if len(policy.SuccessWhen) == 0 {
+		return nil, fmt.Errorf("successWhen must have at least one expression")
+	}
+	successWhen, err := parser.ParseConditionExpressions(policy.SuccessWhen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse successWhen expressions: %w", err)
+	}
+	failureWhen, err := parser.ParseConditionExpressions(policy.FailureWhen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse failureWhen expressions: %w", err)
+	}
+
+	nodeNames := maps.Keys(nodes)
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(nodeNames))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	for _, expr := range slices.Concat(successWhen, failureWhen) {
+		deps, _, err := extractDependencies(env, expr, nodeNames, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract dependencies from expression %q: %w", expr, err)
+		}
+		for _, dep := range deps {
+			if !slices.Contains(instance.Meta.Dependencies, dep) {
+				instance.Meta.Dependencies = append(instance.Meta.Dependencies, dep)
+			}
+		}
+	}
+
+	completion := &Completion{SuccessWhen: successWhen, FailureWhen: failureWhen}
+	if policy.TTLSecondsAfterFinished != nil {
+		ttl := time.Duration(*policy.TTLSecondsAfterFinished) * time.Second
+		completion.TTLAfterFinished = &ttl
+	}
+	return completion, nil
+}
+
+// validateCompletionExpressions type checks the completion expressions
+// against the resources of the graph and the instance spec.
+func validateCompletionExpressions(env *cel.Env, completion *Completion) error {
+	if completion == nil {
+		return nil
+	}
+	check := func(field string, expressions []string) error {
+		for _, expression := range expressions {
+			checkedAST, err := parseAndCheckCELExpression(env, expression)
+			if err != nil {
+				return fmt.Errorf("failed to type-check %s expression %q: %w", field, expression, err)
+			}
+			if outputType := checkedAST.OutputType(); !krocel.IsBoolOrOptionalBool(outputType) {
+				return fmt.Errorf("%s expression %q must return bool or optional_type(bool), but returns %q",
+					field, expression, outputType.String())
+			}
+		}
+		return nil
+	}
+	if err := check("successWhen", completion.SuccessWhen); err != nil {
+		return err
+	}
+	return check("failureWhen", completion.FailureWhen)
+}
+
+func validateFieldPaths(paths []string) error {
+	for _, path := range paths {
+		segments, err := fieldpath.Parse(path)
+		if err != nil {
+			return fmt.Errorf("invalid ignored field path %q: %w", path, err)
+		}
+		if len(segments) == 0 {
+			return fmt.Errorf("ignored field paths must not be empty")
+		}
+		if segments[0].Name == "apiVersion" || segments[0].Name == "kind" ||
+			(len(segments) > 1 && segments[0].Name == "metadata" &&
+				(segments[1].Name == "name" || segments[1].Name == "namespace")) {
+			return fmt.Errorf("field path %q identifies the resource and cannot be ignored", path)
+		}
+	}
+	return nil
+}
+
 // setExpectedTypeOnDescriptor sets the ExpectedType field on a FieldDescriptor.
 // This is the single place where ExpectedType is determined for all field descriptors.
 //
@@ -982,7 +1409,7 @@ func validateNode(node *Node, templatesEnv, schemaEnv *cel.Env, nodeSchema *spec
 		// other nodes (collection chaining), not just schema
 		iteratorTypes, err := validateForEachExpressions(templatesEnv, node)
 		if err != nil {
-			return err
+			return resourceFieldError(node.Meta.ID, "forEach", err)
 		}
 
 		// Extend the templates environment with iterator variables
@@ -1006,7 +1433,7 @@ func validateNode(node *Node, templatesEnv, schemaEnv *cel.Env, nodeSchema *spec
 	// Validate includeWhen expressions if present
 	if len(node.IncludeWhen) > 0 {
 		if err := validateIncludeWhenExpressions(schemaEnv, node); err != nil {
-			return err
+			return resourceFieldError(node.Meta.ID, "includeWhen", err)
 		}
 	}
 
@@ -1023,7 +1450,7 @@ func validateNode(node *Node, templatesEnv, schemaEnv *cel.Env, nodeSchema *spec
 		//   - ${schema.spec.enabled} - schema not in scope at runtime
 		//   - ${otherNode.status.ready} - other nodes not in scope
 		allowedVar := node.Meta.ID
-		if node.Meta.Type == NodeTypeCollection {
+		if node.Meta.Type.IsList() {
 			allowedVar = EachVarName
 		}
 
@@ -1037,17 +1464,18 @@ func validateNode(node *Node, templatesEnv, schemaEnv *cel.Env, nodeSchema *spec
 			inspector := ast.NewInspectorWithEnv(readyEnv, []string{allowedVar})
 			result, err := inspector.Inspect(expression)
 			if err != nil {
-				return fmt.Errorf("failed to inspect readyWhen expression %q: %w", expression, err)
+				return resourceFieldError(node.Meta.ID, "readyWhen",
+					fmt.Errorf("failed to inspect readyWhen expression %q: %w", expression, err))
 			}
 			if len(result.UnknownResources) > 0 {
 				var names []string
 				for _, r := range result.UnknownResources {
 					names = append(names, r.ID)
 				}
-				return fmt.Errorf(
+				return resourceFieldError(node.Meta.ID, "readyWhen", fmt.Errorf(
 					"resource %q readyWhen expression %q cannot reference %v - only '%s' is available (use includeWhen for schema-based conditions)",
 					node.Meta.ID, expression, names, allowedVar,
-				)
+				))
 			}
 		}
 
@@ -1056,7 +1484,7 @@ func validateNode(node *Node, templatesEnv, schemaEnv *cel.Env, nodeSchema *spec
 		// Regular nodes use their ID with their full schema.
 		varName := node.Meta.ID
 		sch := nodeSchema
-		if node.Meta.Type == NodeTypeCollection {
+		if node.Meta.Type.IsList() {
 			varName = EachVarName
 			// nodeSchema is already the item schema (not wrapped as list)
 		}
@@ -1067,7 +1495,7 @@ func validateNode(node *Node, templatesEnv, schemaEnv *cel.Env, nodeSchema *spec
 		}
 
 		if err := validateReadyWhenExpressions(nodeEnv, node); err != nil {
-			return err
+			return resourceFieldError(node.Meta.ID, "readyWhen", err)
 		}
 	}
 
@@ -1085,23 +1513,25 @@ func validateTemplateExpressions(env *cel.Env, node *Node, typeProvider *krocel.
 
 			checkedAST, err := parseAndCheckCELExpression(env, expression)
 			if err != nil {
-				return fmt.Errorf("failed to type-check template expression %q at path %q: %w", expression, templateVariable.Path, err)
+				return resourceFieldError(node.Meta.ID, templateField(node, templateVariable.Path),
+					fmt.Errorf("failed to type-check template expression %q at path %q: %w", expression, templateVariable.Path, err))
 			}
 			outputType := checkedAST.OutputType()
 			if err := validateExpressionType(outputType, templateVariable.ExpectedType, expression, node.Meta.ID, templateVariable.Path, typeProvider); err != nil {
-				return err
+				return resourceFieldError(node.Meta.ID, templateField(node, templateVariable.Path), err)
 			}
 		} else if len(templateVariable.Expressions) > 1 {
 			// Multiple expressions - all must be strings for concatenation
 			for _, expression := range templateVariable.Expressions {
 				checkedAST, err := parseAndCheckCELExpression(env, expression)
 				if err != nil {
-					return fmt.Errorf("failed to type-check template expression %q at path %q: %w", expression, templateVariable.Path, err)
+					return resourceFieldError(node.Meta.ID, templateField(node, templateVariable.Path),
+						fmt.Errorf("failed to type-check template expression %q at path %q: %w", expression, templateVariable.Path, err))
 				}
 
 				outputType := checkedAST.OutputType()
 				if err := validateExpressionType(outputType, templateVariable.ExpectedType, expression, node.Meta.ID, templateVariable.Path, typeProvider); err != nil {
-					return err
+					return resourceFieldError(node.Meta.ID, templateField(node, templateVariable.Path), err)
 				}
 			}
 		}
@@ -1233,25 +1663,43 @@ func validateForEachExpressions(env *cel.Env, node *Node) (map[string]*cel.Type,
 	return iteratorTypes, nil
 }
 
-// getSchemaWithoutStatus returns a schema from the CRD with the status field removed.
-func getSchemaWithoutStatus(crd *extv1.CustomResourceDefinition) (*spec.Schema, error) {
-	crdCopy := crd.DeepCopy()
+// getSchemaWithoutStatus returns the schema of the CRD storage version with the
+// status field removed.
+func getSchemaWithoutStatus(instanceCRD *extv1.CustomResourceDefinition) (*spec.Schema, error) {
+	storage := crd.StorageVersion(instanceCRD)
+	if storage == nil {
+		return nil, fmt.Errorf("expected CRD to have a storage version, got none")
+	}
+	return getVersionSchema(instanceCRD, storage.Name, false)
+}
 
-	// TODO(a-hilaly) expand this function when we start support CRD upgrades.
-	if len(crdCopy.Spec.Versions) != 1 {
-		return nil, fmt.Errorf("expected CRD to have exactly one version, got %d versions: multi-version CRDs not yet supported", len(crdCopy.Spec.Versions))
+// getVersionSchema returns the schema of the given CRD version. The metadata
+// field is replaced by the full ObjectMeta schema, and status is only kept if
+// withStatus is true.
+func getVersionSchema(instanceCRD *extv1.CustomResourceDefinition, version string, withStatus bool) (*spec.Schema, error) {
+	var crdVersion *extv1.CustomResourceDefinitionVersion
+	for i := range instanceCRD.Spec.Versions {
+		if instanceCRD.Spec.Versions[i].Name == version {
+			crdVersion = instanceCRD.Spec.Versions[i].DeepCopy()
+			break
+		}
 	}
-	if crdCopy.Spec.Versions[0].Schema == nil {
+	if crdVersion == nil {
+		return nil, fmt.Errorf("CRD has no version %q", version)
+	}
+	if crdVersion.Schema == nil || crdVersion.Schema.OpenAPIV3Schema == nil {
 		return nil, fmt.Errorf("expected CRD version to have schema defined, but schema is nil")
 	}
 
-	openAPISchema := crdCopy.Spec.Versions[0].Schema.OpenAPIV3Schema
+	openAPISchema := crdVersion.Schema.OpenAPIV3Schema
 
 	if openAPISchema.Properties == nil {
 		openAPISchema.Properties = make(map[string]extv1.JSONSchemaProps)
 	}
 
-	delete(openAPISchema.Properties, "status")
+	if !withStatus {
+		delete(openAPISchema.Properties, "status")
+	}
 
 	specSchema, err := schema.ConvertJSONSchemaPropsToSpecSchema(openAPISchema)
 	if err != nil {
@@ -1266,13 +1714,13 @@ func getSchemaWithoutStatus(crd *extv1.CustomResourceDefinition) (*spec.Schema,
 }
 
 // collectNodeSchemas builds a map of node IDs to their OpenAPI schemas.
-// Collections (those with forEach) are wrapped as list types
+// Collections (those with forEach) and external collections are wrapped as list types
 // so other nodes can reference them as arrays and use CEL list functions.
 func collectNodeSchemas(nodes map[string]*Node, nodeSchemas map[string]*spec.Schema) map[string]*spec.Schema {
 	result := make(map[string]*spec.Schema)
 	for id, node := range nodes {
 		if sch, ok := nodeSchemas[id]; ok {
-			if node.Meta.Type == NodeTypeCollection {
+			if node.Meta.Type.IsList() {
 				result[id] = schema.WrapSchemaAsList(sch)
 			} else {
 				result[id] = sch
@@ -1281,3 +1729,15 @@ func collectNodeSchemas(nodes map[string]*Node, nodeSchemas map[string]*spec.Sch
 	}
 	return result
 }
+
+// optionalValueNodeIDs returns the IDs of the nodes exposed to CEL expressions
+// as optional values, see Node.IsOptionalValue.
+func optionalValueNodeIDs(nodes map[string]*Node) []string {
+	var ids []string
+	for id, node := range nodes {
+		if node.IsOptionalValue() {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
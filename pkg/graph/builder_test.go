@@ -16,10 +16,19 @@ package graph
 
 import (
 	"net/http"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	memory2 "k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
@@ -3128,3 +3137,1082 @@ func TestGraphBuilder_CollectionValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestGraphBuilder_Versions(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	storageSpec := map[string]interface{}{
+		"replicas": "integer | default=1",
+		"name":     "string",
+	}
+
+	tests := []struct {
+		name         string
+		schemaOpts   []generator.SchemaOption
+		wantErr      bool
+		errMsg       string
+		wantVersions []string
+	}{
+		{
+			name:         "single version",
+			wantVersions: []string{"v1beta1"},
+		},
+		{
+			name: "additional version with valid mappings",
+			schemaOpts: []generator.SchemaOption{
+				generator.WithVersion("v1alpha1", map[string]interface{}{
+					"size": "integer",
+					"name": "string",
+				}, &krov1alpha1.VersionConversion{
+					ToStorage:   map[string]string{"spec.replicas": "self.spec.size"},
+					FromStorage: map[string]string{"spec.size": "self.spec.replicas"},
+				}),
+			},
+			wantVersions: []string{"v1alpha1", "v1beta1"},
+		},
+		{
+			name: "mapping to unknown target field",
+			schemaOpts: []generator.SchemaOption{
+				generator.WithVersion("v1alpha1", map[string]interface{}{
+					"size": "integer",
+				}, &krov1alpha1.VersionConversion{
+					ToStorage: map[string]string{"spec.count": "self.spec.size"},
+				}),
+			},
+			wantErr: true,
+			errMsg:  `mapping path "spec.count" does not exist in the target version`,
+		},
+		{
+			name: "mapping referencing unknown source field",
+			schemaOpts: []generator.SchemaOption{
+				generator.WithVersion("v1alpha1", map[string]interface{}{
+					"size": "integer",
+				}, &krov1alpha1.VersionConversion{
+					ToStorage: map[string]string{"spec.replicas": "self.spec.count"},
+				}),
+			},
+			wantErr: true,
+			errMsg:  "failed to type-check expression",
+		},
+		{
+			name: "mapping with mismatched type",
+			schemaOpts: []generator.SchemaOption{
+				generator.WithVersion("v1alpha1", map[string]interface{}{
+					"size": "string",
+				}, &krov1alpha1.VersionConversion{
+					ToStorage: map[string]string{"spec.replicas": "self.spec.size"},
+				}),
+			},
+			wantErr: true,
+			errMsg:  "type mismatch",
+		},
+		{
+			name: "mapping outside of spec",
+			schemaOpts: []generator.SchemaOption{
+				generator.WithVersion("v1alpha1", map[string]interface{}{
+					"size": "integer",
+				}, &krov1alpha1.VersionConversion{
+					ToStorage: map[string]string{"status.replicas": "self.spec.size"},
+				}),
+			},
+			wantErr: true,
+			errMsg:  "must be a field under spec",
+		},
+		{
+			name: "version clashing with storage version",
+			schemaOpts: []generator.SchemaOption{
+				generator.WithVersion("v1beta1", map[string]interface{}{
+					"size": "integer",
+				}, nil),
+			},
+			wantErr: true,
+			errMsg:  `already has version "v1beta1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-rgd",
+				generator.WithSchema("Test", "v1beta1", storageSpec, nil, tt.schemaOpts...),
+			)
+			graph, err := builder.NewResourceGraphDefinition(rgd)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			var versions []string
+			storageVersions := 0
+			for _, v := range graph.CRD.Spec.Versions {
+				versions = append(versions, v.Name)
+				if v.Storage {
+					storageVersions++
+					assert.Equal(t, "v1beta1", v.Name)
+				}
+			}
+			assert.ElementsMatch(t, tt.wantVersions, versions)
+			assert.Equal(t, 1, storageVersions)
+			assert.Equal(t, "v1beta1", graph.Instance.Meta.GVR.Version)
+
+			if len(tt.wantVersions) > 1 {
+				require.NotNil(t, graph.Converter)
+				require.NotNil(t, graph.CRD.Spec.Conversion)
+				assert.Equal(t, extv1.WebhookConverter, graph.CRD.Spec.Conversion.Strategy)
+			} else {
+				assert.Nil(t, graph.Converter)
+			}
+		})
+	}
+}
+
+func TestGraphBuilder_Scope(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	tests := []struct {
+		name           string
+		schemaOpts     []generator.SchemaOption
+		wantScope      extv1.ResourceScope
+		wantNamespaced bool
+	}{
+		{
+			name:           "namespaced by default",
+			wantScope:      extv1.NamespaceScoped,
+			wantNamespaced: true,
+		},
+		{
+			name:           "explicitly namespaced",
+			schemaOpts:     []generator.SchemaOption{generator.WithScope(krov1alpha1.InstanceScopeNamespaced)},
+			wantScope:      extv1.NamespaceScoped,
+			wantNamespaced: true,
+		},
+		{
+			name:       "cluster scoped",
+			schemaOpts: []generator.SchemaOption{generator.WithScope(krov1alpha1.InstanceScopeCluster)},
+			wantScope:  extv1.ClusterScoped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-rgd",
+				generator.WithSchema("Tenant", "v1alpha1", map[string]interface{}{
+					"name": "string",
+				}, nil, tt.schemaOpts...),
+				generator.WithResource("pod", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name}",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "app", "image": "nginx"},
+						},
+					},
+				}, nil, nil),
+			)
+			graph, err := builder.NewResourceGraphDefinition(rgd)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantScope, graph.CRD.Spec.Scope)
+			assert.Equal(t, tt.wantNamespaced, graph.Instance.Meta.Namespaced)
+			assert.True(t, graph.Nodes["pod"].Meta.Namespaced)
+		})
+	}
+}
+
+func TestGraphBuilder_ExternalCollection(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	selectorRef := func(meta krov1alpha1.ExternalRefMetadata) *krov1alpha1.ExternalRef {
+		return &krov1alpha1.ExternalRef{APIVersion: "v1", Kind: "Pod", Metadata: meta}
+	}
+	teamSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"team": "${schema.spec.team}"},
+	}
+
+	tests := []struct {
+		name       string
+		opts       []generator.ResourceGraphDefinitionOption
+		wantErr    bool
+		errMsg     string
+		checkGraph func(t *testing.T, g *Graph)
+	}{
+		{
+			name: "selector resolves to a list",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("Team", "v1alpha1",
+					map[string]interface{}{"team": "string"},
+					map[string]interface{}{
+						"podCount": "${pods.size()}",
+						"podNames": "${pods.map(p, p.metadata.name)}",
+					},
+				),
+				generator.WithExternalRef("pods", selectorRef(krov1alpha1.ExternalRefMetadata{
+					Selector: teamSelector,
+				}), []string{"${each.status.phase == 'Running'}"}, nil),
+			},
+			checkGraph: func(t *testing.T, g *Graph) {
+				node := g.Nodes["pods"]
+				require.NotNil(t, node)
+				assert.Equal(t, NodeTypeExternalCollection, node.Meta.Type)
+				assert.True(t, node.Meta.Type.IsList())
+				assert.True(t, node.Meta.Type.IsExternal())
+				assert.Contains(t, node.Template.Object, ExternalSelectorPath)
+				assert.NotContains(t, node.Template.Object["metadata"], "name")
+			},
+		},
+		{
+			name: "collection items can drive forEach",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("Team", "v1alpha1",
+					map[string]interface{}{"team": "string"}, nil),
+				generator.WithExternalRef("pods", selectorRef(krov1alpha1.ExternalRefMetadata{
+					Selector: teamSelector,
+				}), nil, nil),
+				generator.WithResourceCollection("sidecars", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${pod.metadata.name}-sidecar",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "sidecar", "image": "busybox"},
+						},
+					},
+				}, []krov1alpha1.ForEachDimension{
+					{"pod": "${pods}"},
+				}, nil, nil),
+			},
+			checkGraph: func(t *testing.T, g *Graph) {
+				assert.Contains(t, g.Nodes["sidecars"].Meta.Dependencies, "pods")
+			},
+		},
+		{
+			name: "collection cannot be used as a single object",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("Team", "v1alpha1",
+					map[string]interface{}{"team": "string"},
+					map[string]interface{}{"phase": "${pods.status.phase}"},
+				),
+				generator.WithExternalRef("pods", selectorRef(krov1alpha1.ExternalRefMetadata{
+					Selector: teamSelector,
+				}), nil, nil),
+			},
+			wantErr: true,
+		},
+		{
+			name: "name and selector are mutually exclusive",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("Team", "v1alpha1",
+					map[string]interface{}{"team": "string"}, nil),
+				generator.WithExternalRef("pods", selectorRef(krov1alpha1.ExternalRefMetadata{
+					Name:     "pod",
+					Selector: teamSelector,
+				}), nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "exactly one of metadata.name or metadata.selector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-rgd", tt.opts...)
+			g, err := builder.NewResourceGraphDefinition(rgd)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			if tt.checkGraph != nil {
+				tt.checkGraph(t, g)
+			}
+		})
+	}
+}
+
+func TestGraphBuilder_OptionalExternalRef(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	optionalRef := func(defaultObject string) *krov1alpha1.ExternalRef {
+		ref := &krov1alpha1.ExternalRef{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Metadata:   krov1alpha1.ExternalRefMetadata{Name: "${schema.spec.name}-seed"},
+			Optional:   true,
+		}
+		if defaultObject != "" {
+			ref.Default = &k8sruntime.RawExtension{Raw: []byte(defaultObject)}
+		}
+		return ref
+	}
+	podWithImage := func(image string) generator.ResourceGraphDefinitionOption {
+		return generator.WithResource("pod", map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "app", "image": image},
+				},
+			},
+		}, nil, nil)
+	}
+
+	tests := []struct {
+		name       string
+		opts       []generator.ResourceGraphDefinitionOption
+		wantErr    bool
+		errMsg     string
+		checkGraph func(t *testing.T, g *Graph)
+	}{
+		{
+			name: "optional without default is an optional value",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("App", "v1alpha1",
+					map[string]interface{}{"name": "string"},
+					map[string]interface{}{
+						"seeded":   "${seed.hasValue()}",
+						"seedName": "${seed.?metadata.?name}",
+					},
+				),
+				generator.WithExternalRef("seed", optionalRef(""), nil, nil),
+				podWithImage(`${seed.?spec.?containers[0].image.orValue("nginx")}`),
+			},
+			checkGraph: func(t *testing.T, g *Graph) {
+				seed := g.Nodes["seed"]
+				assert.True(t, seed.Meta.Optional)
+				assert.True(t, seed.IsOptionalValue())
+				assert.Contains(t, g.Nodes["pod"].Meta.Dependencies, "seed")
+			},
+		},
+		{
+			name: "optional without default must be unwrapped",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("App", "v1alpha1",
+					map[string]interface{}{"name": "string"}, nil),
+				generator.WithExternalRef("seed", optionalRef(""), nil, nil),
+				podWithImage(`${seed.metadata.name.size() > 0 ? "nginx" : "busybox"}`),
+			},
+			wantErr: true,
+			errMsg:  "optional_type(string)",
+		},
+		{
+			name: "optional with default resolves to the referenced kind",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("App", "v1alpha1",
+					map[string]interface{}{"name": "string", "image": "string"}, nil),
+				generator.WithExternalRef("seed", optionalRef(
+					`{"spec": {"containers": [{"name": "seed", "image": "${schema.spec.image}"}]}}`,
+				), nil, nil),
+				podWithImage("${seed.spec.containers[0].image}"),
+			},
+			checkGraph: func(t *testing.T, g *Graph) {
+				seed := g.Nodes["seed"]
+				assert.True(t, seed.Meta.Optional)
+				assert.False(t, seed.IsOptionalValue())
+				assert.Contains(t, seed.Template.Object, ExternalDefaultPath)
+
+				var paths []string
+				for _, v := range seed.Variables {
+					paths = append(paths, v.Path)
+				}
+				assert.Contains(t, paths, "default.spec.containers[0].image")
+			},
+		},
+		{
+			name: "default must match the referenced kind",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("App", "v1alpha1",
+					map[string]interface{}{"name": "string"}, nil),
+				generator.WithExternalRef("seed", optionalRef(
+					`{"spec": {"containers": "not-a-list"}}`,
+				), nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "failed to parse default",
+		},
+		{
+			name: "default requires optional",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("App", "v1alpha1",
+					map[string]interface{}{"name": "string"}, nil),
+				generator.WithExternalRef("seed", &krov1alpha1.ExternalRef{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Metadata:   krov1alpha1.ExternalRefMetadata{Name: "seed"},
+					Default:    &k8sruntime.RawExtension{Raw: []byte(`{}`)},
+				}, nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "default can only be set on optional external references",
+		},
+		{
+			name: "optional requires a name",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("App", "v1alpha1",
+					map[string]interface{}{"name": "string"}, nil),
+				generator.WithExternalRef("seeds", &krov1alpha1.ExternalRef{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Metadata: krov1alpha1.ExternalRefMetadata{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "seed"}},
+					},
+					Optional: true,
+				}, nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "optional externalRef must select a resource by name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-rgd", tt.opts...)
+			g, err := builder.NewResourceGraphDefinition(rgd)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			if tt.checkGraph != nil {
+				tt.checkGraph(t, g)
+			}
+		})
+	}
+}
+
+func TestGraphBuilder_DriftPolicy(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	withDrift := func(policy *krov1alpha1.DriftPolicy) generator.ResourceGraphDefinitionOption {
+		return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+			resources := rgd.Spec.Resources
+			resources[len(resources)-1].Drift = policy
+		}
+	}
+	schema := generator.WithSchema("App", "v1alpha1", map[string]interface{}{"name": "string"}, nil)
+	pod := generator.WithResource("pod", map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "${schema.spec.name}"},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "nginx"},
+			},
+		},
+	}, nil, nil)
+
+	tests := []struct {
+		name    string
+		opts    []generator.ResourceGraphDefinitionOption
+		want    DriftPolicy
+		wantErr string
+	}{
+		{
+			name: "no policy",
+			opts: []generator.ResourceGraphDefinitionOption{schema, pod},
+		},
+		{
+			name: "report with ignored fields",
+			opts: []generator.ResourceGraphDefinitionOption{schema, pod, withDrift(&krov1alpha1.DriftPolicy{
+				Action: krov1alpha1.DriftActionReport,
+				Ignore: []string{"spec.containers[0].image", `metadata.annotations["example.com/owner"]`},
+			})},
+			want: DriftPolicy{
+				Report: true,
+				Ignore: []string{"spec.containers[0].image", `metadata.annotations["example.com/owner"]`},
+			},
+		},
+		{
+			name: "invalid ignored field path",
+			opts: []generator.ResourceGraphDefinitionOption{schema, pod, withDrift(&krov1alpha1.DriftPolicy{
+				Ignore: []string{"spec.containers[x]"},
+			})},
+			wantErr: "invalid ignored field path",
+		},
+		{
+			name: "not supported on external references",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schema,
+				generator.WithExternalRef("seed", &krov1alpha1.ExternalRef{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Metadata:   krov1alpha1.ExternalRefMetadata{Name: "seed"},
+				}, nil, nil),
+				withDrift(&krov1alpha1.DriftPolicy{Action: krov1alpha1.DriftActionReport}),
+			},
+			wantErr: "drift policies are not supported on external references",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-rgd", tt.opts...)
+			g, err := builder.NewResourceGraphDefinition(rgd)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			for _, node := range g.Nodes {
+				if node.Meta.Type == NodeTypeResource {
+					assert.Equal(t, tt.want, node.Drift)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphBuilder_IgnoreFields(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	withIgnoreFields := func(paths ...string) generator.ResourceGraphDefinitionOption {
+		return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+			resources := rgd.Spec.Resources
+			resources[len(resources)-1].IgnoreFields = paths
+		}
+	}
+	schema := generator.WithSchema("App", "v1alpha1", map[string]interface{}{"name": "string"}, nil)
+	pod := generator.WithResource("pod", map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "${schema.spec.name}"},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "nginx"},
+			},
+		},
+	}, nil, nil)
+
+	tests := []struct {
+		name    string
+		opts    []generator.ResourceGraphDefinitionOption
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "valid paths",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schema, pod, withIgnoreFields("spec.containers[0].image", `metadata.annotations["example.com/owner"]`),
+			},
+			want: []string{"spec.containers[0].image", `metadata.annotations["example.com/owner"]`},
+		},
+		{
+			name:    "invalid path",
+			opts:    []generator.ResourceGraphDefinitionOption{schema, pod, withIgnoreFields("spec..replicas")},
+			wantErr: "invalid ignored field path",
+		},
+		{
+			name:    "identity fields",
+			opts:    []generator.ResourceGraphDefinitionOption{schema, pod, withIgnoreFields("metadata.name")},
+			wantErr: "cannot be ignored",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-rgd", tt.opts...)
+			g, err := builder.NewResourceGraphDefinition(rgd)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, g.Nodes["pod"].IgnoreFields)
+		})
+	}
+}
+
+func TestGraphBuilder_UpdatePolicy(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	withUpdatePolicy := func(policy krov1alpha1.UpdatePolicy) generator.ResourceGraphDefinitionOption {
+		return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+			resources := rgd.Spec.Resources
+			resources[len(resources)-1].UpdatePolicy = policy
+		}
+	}
+	schema := generator.WithSchema("App", "v1alpha1", map[string]interface{}{"name": "string"}, nil)
+	pod := generator.WithResource("pod", map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "${schema.spec.name}"},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "nginx"},
+			},
+		},
+	}, nil, nil)
+
+	tests := []struct {
+		name    string
+		opts    []generator.ResourceGraphDefinitionOption
+		want    UpdatePolicy
+		wantErr string
+	}{
+		{
+			name: "default",
+			opts: []generator.ResourceGraphDefinitionOption{schema, pod},
+			want: UpdatePolicyUpdate,
+		},
+		{
+			name: "recreate",
+			opts: []generator.ResourceGraphDefinitionOption{schema, pod, withUpdatePolicy(krov1alpha1.UpdatePolicyRecreate)},
+			want: UpdatePolicyRecreate,
+		},
+		{
+			name: "recreate on immutable error",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schema, pod, withUpdatePolicy(krov1alpha1.UpdatePolicyRecreateOnImmutableError),
+			},
+			want: UpdatePolicyRecreateOnImmutableError,
+		},
+		{
+			name:    "unknown policy",
+			opts:    []generator.ResourceGraphDefinitionOption{schema, pod, withUpdatePolicy("Replace")},
+			wantErr: `unknown update policy "Replace"`,
+		},
+		{
+			name: "not supported on external references",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schema,
+				generator.WithExternalRef("seed", &krov1alpha1.ExternalRef{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Metadata:   krov1alpha1.ExternalRefMetadata{Name: "seed"},
+				}, nil, nil),
+				withUpdatePolicy(krov1alpha1.UpdatePolicyRecreate),
+			},
+			wantErr: "update policies are not supported on external references",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-rgd", tt.opts...)
+			g, err := builder.NewResourceGraphDefinition(rgd)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, g.Nodes["pod"].UpdatePolicy)
+		})
+	}
+}
+
+func TestGraphBuilder_Hooks(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	withHook := func(hook *krov1alpha1.Hook) generator.ResourceGraphDefinitionOption {
+		return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+			resources := rgd.Spec.Resources
+			resources[len(resources)-1].Hook = hook
+		}
+	}
+	pod := func(id, name string) generator.ResourceGraphDefinitionOption {
+		return generator.WithResource(id, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "app", "image": "nginx"},
+				},
+			},
+		}, nil, nil)
+	}
+	schema := generator.WithSchema("App", "v1alpha1", map[string]interface{}{"name": "string"}, nil)
+
+	t.Run("dependencies", func(t *testing.T) {
+		rgd := generator.NewResourceGraphDefinition("test-rgd",
+			schema,
+			pod("migrate", "${schema.spec.name}-migrate"),
+			withHook(&krov1alpha1.Hook{Phase: krov1alpha1.HookPhasePreCreate}),
+			pod("app", "${schema.spec.name}"),
+			pod("notify", "${app.metadata.name}-notify"),
+			withHook(&krov1alpha1.Hook{
+				Phase:         krov1alpha1.HookPhasePostReady,
+				CleanupPolicy: krov1alpha1.HookCleanupDeleteOnCompletion,
+			}),
+			pod("cleanup", "${schema.spec.name}-cleanup"),
+			withHook(&krov1alpha1.Hook{Phase: krov1alpha1.HookPhasePreDelete}),
+		)
+		g, err := builder.NewResourceGraphDefinition(rgd)
+		require.NoError(t, err)
+
+		assert.Nil(t, g.Nodes["app"].Hook)
+		assert.Equal(t, &Hook{Phase: HookPhasePreCreate}, g.Nodes["migrate"].Hook)
+		assert.Equal(t, &Hook{Phase: HookPhasePostReady, DeleteOnCompletion: true}, g.Nodes["notify"].Hook)
+		assert.Equal(t, &Hook{Phase: HookPhasePreDelete}, g.Nodes["cleanup"].Hook)
+
+		assert.Equal(t, []string{"migrate"}, g.Nodes["app"].Meta.WaitsFor)
+		assert.Empty(t, g.Nodes["migrate"].Meta.WaitsFor)
+		assert.Equal(t, []string{"app"}, g.Nodes["notify"].Meta.WaitsFor)
+		assert.Empty(t, g.Nodes["cleanup"].Meta.WaitsFor)
+		assert.Less(t, slices.Index(g.TopologicalOrder, "migrate"), slices.Index(g.TopologicalOrder, "app"))
+	})
+
+	tests := []struct {
+		name    string
+		opts    []generator.ResourceGraphDefinitionOption
+		wantErr string
+	}{
+		{
+			name: "unknown phase",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schema, pod("migrate", "migrate"), withHook(&krov1alpha1.Hook{Phase: "PostDelete"}),
+			},
+			wantErr: `unknown hook phase "PostDelete"`,
+		},
+		{
+			name: "update policy",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schema, pod("migrate", "migrate"), withHook(&krov1alpha1.Hook{Phase: krov1alpha1.HookPhasePreCreate}),
+				func(rgd *krov1alpha1.ResourceGraphDefinition) {
+					rgd.Spec.Resources[0].UpdatePolicy = krov1alpha1.UpdatePolicyRecreate
+				},
+			},
+			wantErr: "cannot set an update policy",
+		},
+		{
+			name: "pre-delete hook referencing a resource",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schema,
+				pod("app", "${schema.spec.name}"),
+				pod("cleanup", "${app.metadata.name}-cleanup"),
+				withHook(&krov1alpha1.Hook{Phase: krov1alpha1.HookPhasePreDelete}),
+			},
+			wantErr: `pre-delete hook "cleanup" can only reference the instance`,
+		},
+		{
+			name: "resource referencing a pre-delete hook",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schema,
+				pod("cleanup", "${schema.spec.name}-cleanup"),
+				withHook(&krov1alpha1.Hook{Phase: krov1alpha1.HookPhasePreDelete}),
+				pod("app", "${cleanup.metadata.name}-app"),
+			},
+			wantErr: `resource "app" cannot reference the pre-delete hook "cleanup"`,
+		},
+		{
+			name: "not supported on external references",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schema,
+				generator.WithExternalRef("seed", &krov1alpha1.ExternalRef{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Metadata:   krov1alpha1.ExternalRefMetadata{Name: "seed"},
+				}, nil, nil),
+				withHook(&krov1alpha1.Hook{Phase: krov1alpha1.HookPhasePreCreate}),
+			},
+			wantErr: "hooks are not supported on external references",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-rgd", tt.opts...)
+			_, err := builder.NewResourceGraphDefinition(rgd)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestGraphBuilder_Completion(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	withCompletion := func(policy *krov1alpha1.CompletionPolicy) generator.ResourceGraphDefinitionOption {
+		return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+			rgd.Spec.Completion = policy
+		}
+	}
+	schema := generator.WithSchema("Export", "v1alpha1", map[string]interface{}{"name": "string"}, nil)
+	pod := generator.WithResource("pod", map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "${schema.spec.name}"},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "export", "image": "export"},
+			},
+		},
+	}, nil, nil)
+	ttl := int32(60)
+
+	t.Run("no completion", func(t *testing.T) {
+		g, err := builder.NewResourceGraphDefinition(generator.NewResourceGraphDefinition("test-rgd", schema, pod))
+		require.NoError(t, err)
+		assert.Nil(t, g.Instance.Completion)
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		rgd := generator.NewResourceGraphDefinition("test-rgd", schema, pod, withCompletion(&krov1alpha1.CompletionPolicy{
+			SuccessWhen:             []string{"${pod.status.phase == 'Succeeded'}"},
+			FailureWhen:             []string{"${pod.status.phase == 'Failed'}", "${schema.spec.name == 'fail'}"},
+			TTLSecondsAfterFinished: &ttl,
+		}))
+		g, err := builder.NewResourceGraphDefinition(rgd)
+		require.NoError(t, err)
+
+		completion := g.Instance.Completion
+		require.NotNil(t, completion)
+		assert.Equal(t, []string{"pod.status.phase == 'Succeeded'"}, completion.SuccessWhen)
+		assert.Equal(t, []string{"pod.status.phase == 'Failed'", "schema.spec.name == 'fail'"}, completion.FailureWhen)
+		require.NotNil(t, completion.TTLAfterFinished)
+		assert.Equal(t, time.Minute, *completion.TTLAfterFinished)
+		assert.Contains(t, g.Instance.Meta.Dependencies, "pod")
+	})
+
+	tests := []struct {
+		name    string
+		policy  *krov1alpha1.CompletionPolicy
+		wantErr string
+	}{
+		{
+			name:    "no success expression",
+			policy:  &krov1alpha1.CompletionPolicy{},
+			wantErr: "successWhen must have at least one expression",
+		},
+		{
+			name:    "not a standalone expression",
+			policy:  &krov1alpha1.CompletionPolicy{SuccessWhen: []string{"phase is ${pod.status.phase}"}},
+			wantErr: "only standalone expressions are allowed",
+		},
+		{
+			name:    "unknown resource",
+			policy:  &krov1alpha1.CompletionPolicy{SuccessWhen: []string{"${job.status.succeeded > 0}"}},
+			wantErr: "unknown resources",
+		},
+		{
+			name:    "not a bool",
+			policy:  &krov1alpha1.CompletionPolicy{SuccessWhen: []string{"${pod.status.phase}"}},
+			wantErr: "must return bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-rgd", schema, pod, withCompletion(tt.policy))
+			_, err := builder.NewResourceGraphDefinition(rgd)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestGraphBuilder_TracesPhases(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	spans := func() map[string]codes.Code {
+		status := map[string]codes.Code{}
+		for _, span := range recorder.Ended() {
+			status[span.Name()] = span.Status().Code
+		}
+		return status
+	}
+	schema := generator.WithSchema("Test", "v1alpha1", map[string]interface{}{"name": "string"}, nil)
+
+	rgd := generator.NewResourceGraphDefinition("testrgd", schema,
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "${schema.spec.name}",
+			},
+		}, nil, nil),
+	)
+	_, err := builder.NewResourceGraphDefinitionContext(t.Context(), rgd)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]codes.Code{
+		"Builder.NewResourceGraphDefinition": codes.Unset,
+		"Builder.ResolveSchemas":             codes.Unset,
+		"Builder.ParseInstance":              codes.Unset,
+		"Builder.BuildDAG":                   codes.Unset,
+		"Builder.TypeCheck":                  codes.Unset,
+	}, spans())
+
+	// The phase that failed reports the error.
+	recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	rgd = generator.NewResourceGraphDefinition("testrgd", schema,
+		generator.WithResource("vpc", map[string]interface{}{
+			"apiVersion": "unknown.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata": map[string]interface{}{
+				"name": "test-vpc",
+			},
+		}, nil, nil),
+	)
+	_, err = builder.NewResourceGraphDefinitionContext(t.Context(), rgd)
+	require.Error(t, err)
+	assert.Equal(t, map[string]codes.Code{
+		"Builder.NewResourceGraphDefinition": codes.Error,
+		"Builder.ResolveSchemas":             codes.Error,
+	}, spans())
+}
+
+func TestGraphBuilder_FieldErrors(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory2.NewMemCacheClient(fakeDiscovery))
+	builder := &Builder{
+		schemaResolver: fakeResolver,
+		restMapper:     restMapper,
+	}
+
+	vpc := func(spec map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+			"kind":       "VPC",
+			"metadata":   map[string]interface{}{"name": "test-vpc"},
+			"spec":       spec,
+		}
+	}
+	schemaOpt := func(status map[string]interface{}) generator.ResourceGraphDefinitionOption {
+		return generator.WithSchema("Test", "v1alpha1", map[string]interface{}{"name": "string"}, status)
+	}
+
+	tests := []struct {
+		name       string
+		opts       []generator.ResourceGraphDefinitionOption
+		resourceID string
+		field      string
+	}{
+		{
+			name: "invalid kind",
+			opts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema("invalidKind", "v1alpha1", map[string]interface{}{"name": "string"}, nil),
+			},
+			field: "schema.kind",
+		},
+		{
+			name: "invalid resource id",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schemaOpt(nil),
+				generator.WithResource("vpc-1", vpc(nil), nil, nil),
+			},
+			resourceID: "vpc-1",
+			field:      "id",
+		},
+		{
+			name: "unknown resource type",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schemaOpt(nil),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "unknown.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata":   map[string]interface{}{"name": "test-vpc"},
+				}, nil, nil),
+			},
+			resourceID: "vpc",
+		},
+		{
+			name: "invalid template expression",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schemaOpt(nil),
+				generator.WithResource("vpc", vpc(map[string]interface{}{
+					"cidrBlocks": []interface{}{"${schema.spec.unknown}"},
+				}), nil, nil),
+			},
+			resourceID: "vpc",
+			field:      "template.spec.cidrBlocks[0]",
+		},
+		{
+			name: "readyWhen referencing the schema",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schemaOpt(nil),
+				generator.WithResource("vpc", vpc(nil), []string{"${schema.spec.name == 'ready'}"}, nil),
+			},
+			resourceID: "vpc",
+			field:      "readyWhen",
+		},
+		{
+			name: "invalid status expression",
+			opts: []generator.ResourceGraphDefinitionOption{
+				schemaOpt(map[string]interface{}{"state": "${vpc.status.unknown}"}),
+				generator.WithResource("vpc", vpc(nil), nil, nil),
+			},
+			field: "schema.status.state",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-group", tt.opts...)
+			_, err := builder.NewResourceGraphDefinition(rgd)
+			require.Error(t, err)
+
+			var fieldErr *FieldError
+			require.ErrorAs(t, err, &fieldErr)
+			assert.Equal(t, tt.resourceID, fieldErr.ResourceID)
+			assert.Equal(t, tt.field, fieldErr.Field)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+	krocel "github.com/kubernetes-sigs/kro/pkg/cel"
+	"github.com/kubernetes-sigs/kro/pkg/conversion"
+	"github.com/kubernetes-sigs/kro/pkg/graph/crd"
+	"github.com/kubernetes-sigs/kro/pkg/graph/fieldpath"
+)
+
+// conversionTargetVarName is the name used to register the target version
+// schema in the type provider when type checking conversion mappings.
+const conversionTargetVarName = "target"
+
+// buildVersions adds the additional versions declared in the schema to the
+// instance CRD, type checks their conversion mappings and returns a converter
+// for them. It returns a nil converter if the schema declares a single version.
+func buildVersions(rgSchema *v1alpha1.Schema, instanceCRD *extv1.CustomResourceDefinition) (*conversion.Converter, error) {
+	if len(rgSchema.Versions) == 0 {
+		return nil, nil
+	}
+
+	for _, version := range rgSchema.Versions {
+		specSchema, err := buildInstanceSpecSchema(&v1alpha1.Schema{Spec: version.Spec, Types: rgSchema.Types})
+		if err != nil {
+			return nil, fmt.Errorf("version %q: %w", version.Name, err)
+		}
+		served := version.Served == nil || *version.Served
+		if err := crd.AddServedVersion(instanceCRD, version.Name, *specSchema, served, version.Deprecated); err != nil {
+			return nil, fmt.Errorf("version %q: %w", version.Name, err)
+		}
+	}
+
+	converter, err := conversion.NewConverter(
+		schema.GroupKind{Group: rgSchema.Group, Kind: rgSchema.Kind},
+		rgSchema.APIVersion,
+		rgSchema.Versions,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	storageSchema, err := getVersionSchema(instanceCRD, rgSchema.APIVersion, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range rgSchema.Versions {
+		if version.Conversion == nil {
+			continue
+		}
+		versionSchema, err := getVersionSchema(instanceCRD, version.Name, true)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateConversionMappings(version.Conversion.ToStorage, versionSchema, storageSchema); err != nil {
+			return nil, fmt.Errorf("version %q: toStorage: %w", version.Name, err)
+		}
+		if err := validateConversionMappings(version.Conversion.FromStorage, storageSchema, versionSchema); err != nil {
+			return nil, fmt.Errorf("version %q: fromStorage: %w", version.Name, err)
+		}
+	}
+
+	return converter, nil
+}
+
+// validateConversionMappings type checks conversion mappings: expressions are
+// checked against the source schema (as `self`), and their output type must be
+// compatible with the schema of the target field.
+func validateConversionMappings(mappings map[string]string, sourceSchema, targetSchema *spec.Schema) error {
+	schemas := map[string]*spec.Schema{
+		conversion.SelfVarName:  sourceSchema,
+		conversionTargetVarName: targetSchema,
+	}
+	env, err := krocel.TypedEnvironment(map[string]*spec.Schema{conversion.SelfVarName: sourceSchema})
+	if err != nil {
+		return fmt.Errorf("failed to create typed CEL environment: %w", err)
+	}
+	typeProvider := krocel.CreateDeclTypeProvider(schemas)
+
+	paths := make([]string, 0, len(mappings))
+	for path := range mappings {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		expression := mappings[path]
+		fields, err := conversion.ParseMappingPath(path)
+		if err != nil {
+			return err
+		}
+		checkedAST, err := parseAndCheckCELExpression(env, expression)
+		if err != nil {
+			return fmt.Errorf("failed to type-check expression %q for %q: %w", expression, path, err)
+		}
+
+		// Labels and annotations are plain string maps.
+		expectedType := cel.StringType
+		if fields[0] != "metadata" {
+			segments, err := fieldpath.Parse(path)
+			if err != nil {
+				return fmt.Errorf("invalid mapping path %q: %w", path, err)
+			}
+			fieldSchema, typeName, err := resolveSchemaAndTypeName(segments, targetSchema, conversionTargetVarName)
+			if err != nil {
+				return fmt.Errorf("mapping path %q does not exist in the target version: %w", path, err)
+			}
+			expectedType = getCelTypeFromSchema(fieldSchema, typeName)
+		}
+
+		outputType := checkedAST.OutputType()
+		// Mappings may return optional values or null to unset the target field.
+		if krocel.WouldMatchIfUnwrapped(outputType, expectedType) || outputType.IsExactType(cel.NullType) {
+			continue
+		}
+		if err := validateExpressionType(outputType, expectedType, expression, "conversion", path, typeProvider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// IncompatibilityType categorizes a breaking change between two versions of a CRD.
+type IncompatibilityType string
+
+const (
+	// IncompatibilityScopeChanged means the CRD scope changed between
+	// Namespaced and Cluster.
+	IncompatibilityScopeChanged IncompatibilityType = "ScopeChanged"
+	// IncompatibilityVersionRemoved means a served version is no longer served.
+	IncompatibilityVersionRemoved IncompatibilityType = "VersionRemoved"
+	// IncompatibilityFieldRemoved means a field is no longer part of the schema.
+	IncompatibilityFieldRemoved IncompatibilityType = "FieldRemoved"
+	// IncompatibilityTypeChanged means the type of a field changed.
+	IncompatibilityTypeChanged IncompatibilityType = "TypeChanged"
+	// IncompatibilityRequiredAdded means a field became required without a default.
+	IncompatibilityRequiredAdded IncompatibilityType = "RequiredAdded"
+	// IncompatibilityEnumNarrowed means allowed enum values were removed.
+	IncompatibilityEnumNarrowed IncompatibilityType = "EnumNarrowed"
+	// IncompatibilityValidationTightened means a validation became stricter.
+	IncompatibilityValidationTightened IncompatibilityType = "ValidationTightened"
+)
+
+// Incompatibility describes a single breaking change between two CRDs.
+type Incompatibility struct {
+	// Type is the category of the breaking change.
+	Type IncompatibilityType
+	// Version is the CRD version the change was found in. Empty for CRD wide
+	// changes such as scope changes.
+	Version string
+	// Path is the field path the change was found at, e.g. spec.replicas.
+	Path string
+	// Message is a human readable description of the change.
+	Message string
+}
+
+// String returns a human readable representation of the incompatibility.
+func (i Incompatibility) String() string {
+	var location string
+	switch {
+	case i.Version != "" && i.Path != "":
+		location = fmt.Sprintf("%s %s: ", i.Version, i.Path)
+	case i.Version != "":
+		location = i.Version + ": "
+	}
+	return fmt.Sprintf("%s%s (%s)", location, i.Message, i.Type)
+}
+
+// Incompatibilities is a list of breaking changes.
+type Incompatibilities []Incompatibility
+
+// String returns all the incompatibilities joined in a single line, suitable
+// for condition messages.
+func (is Incompatibilities) String() string {
+	parts := make([]string, 0, len(is))
+	for _, i := range is {
+		parts = append(parts, i.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// CheckCompatibility compares an existing CRD with its desired state and returns
+// every change that could break existing instances or clients. Only the spec
+// and metadata of each version are analyzed: status is computed by kro and is
+// rewritten on every reconciliation.
+//
+// The following changes are considered breaking:
+//   - changing the CRD scope
+//   - removing a served version
+//   - removing a field, unless unknown fields are preserved
+//   - changing the type of a field
+//   - making a field required without a default
+//   - removing enum values, or adding an enum to a field that had none
+//   - tightening validation (bounds, lengths, patterns, formats, CEL rules...)
+func CheckCompatibility(existing, desired *extv1.CustomResourceDefinition) Incompatibilities {
+	var out Incompatibilities
+
+	existingScope, desiredScope := existing.Spec.Scope, desired.Spec.Scope
+	if existingScope == "" {
+		existingScope = extv1.NamespaceScoped
+	}
+	if desiredScope == "" {
+		desiredScope = extv1.NamespaceScoped
+	}
+	if existingScope != desiredScope {
+		out = append(out, Incompatibility{
+			Type:    IncompatibilityScopeChanged,
+			Message: fmt.Sprintf("scope changed from %s to %s", existingScope, desiredScope),
+		})
+	}
+
+	for _, existingVersion := range existing.Spec.Versions {
+		if !existingVersion.Served {
+			continue
+		}
+		desiredVersion := findVersion(desired, existingVersion.Name)
+		if desiredVersion == nil || !desiredVersion.Served {
+			out = append(out, Incompatibility{
+				Type:    IncompatibilityVersionRemoved,
+				Version: existingVersion.Name,
+				Message: "version is no longer served",
+			})
+			continue
+		}
+		if existingVersion.Schema == nil || existingVersion.Schema.OpenAPIV3Schema == nil ||
+			desiredVersion.Schema == nil || desiredVersion.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		c := &compatibilityChecker{version: existingVersion.Name}
+		existingRoot := existingVersion.Schema.OpenAPIV3Schema
+		desiredRoot := desiredVersion.Schema.OpenAPIV3Schema
+		for _, field := range []string{"metadata", "spec"} {
+			existingField, ok := existingRoot.Properties[field]
+			if !ok {
+				continue
+			}
+			desiredField, ok := desiredRoot.Properties[field]
+			if !ok {
+				c.add(IncompatibilityFieldRemoved, field, "field was removed")
+				continue
+			}
+			c.compare(field, &existingField, &desiredField)
+		}
+		out = append(out, c.incompatibilities...)
+	}
+
+	return out
+}
+
+func findVersion(crd *extv1.CustomResourceDefinition, name string) *extv1.CustomResourceDefinitionVersion {
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Name == name {
+			return &crd.Spec.Versions[i]
+		}
+	}
+	return nil
+}
+
+// compatibilityChecker walks two schemas side by side and records breaking changes.
+type compatibilityChecker struct {
+	version           string
+	incompatibilities Incompatibilities
+}
+
+func (c *compatibilityChecker) add(t IncompatibilityType, path, format string, args ...interface{}) {
+	c.incompatibilities = append(c.incompatibilities, Incompatibility{
+		Type:    t,
+		Version: c.version,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *compatibilityChecker) compare(path string, existing, desired *extv1.JSONSchemaProps) {
+	if existing.Type != "" && desired.Type != "" && existing.Type != desired.Type {
+		c.add(IncompatibilityTypeChanged, path, "type changed from %s to %s", existing.Type, desired.Type)
+		// Nothing else can be meaningfully compared.
+		return
+	}
+	if existing.XIntOrString && !desired.XIntOrString {
+		c.add(IncompatibilityTypeChanged, path, "no longer accepts both integers and strings")
+	}
+
+	c.compareEnum(path, existing, desired)
+	c.compareValidation(path, existing, desired)
+	c.compareProperties(path, existing, desired)
+
+	if existing.Items != nil && existing.Items.Schema != nil && desired.Items != nil && desired.Items.Schema != nil {
+		c.compare(path+"[*]", existing.Items.Schema, desired.Items.Schema)
+	}
+	if existing.AdditionalProperties != nil && existing.AdditionalProperties.Schema != nil &&
+		desired.AdditionalProperties != nil && desired.AdditionalProperties.Schema != nil {
+		c.compare(path+"[*]", existing.AdditionalProperties.Schema, desired.AdditionalProperties.Schema)
+	}
+}
+
+func (c *compatibilityChecker) compareProperties(path string, existing, desired *extv1.JSONSchemaProps) {
+	preservesUnknown := desired.XPreserveUnknownFields != nil && *desired.XPreserveUnknownFields
+	if existing.XPreserveUnknownFields != nil && *existing.XPreserveUnknownFields && !preservesUnknown {
+		c.add(IncompatibilityFieldRemoved, path, "unknown fields are no longer preserved")
+	}
+
+	for _, name := range sortedKeys(existing.Properties) {
+		existingProp := existing.Properties[name]
+		fieldPath := path + "." + name
+		desiredProp, ok := desired.Properties[name]
+		if !ok {
+			if !preservesUnknown {
+				c.add(IncompatibilityFieldRemoved, fieldPath, "field was removed")
+			}
+			continue
+		}
+		c.compare(fieldPath, &existingProp, &desiredProp)
+	}
+
+	for _, name := range desired.Required {
+		if slices.Contains(existing.Required, name) {
+			continue
+		}
+		if prop, ok := desired.Properties[name]; ok && prop.Default != nil {
+			continue
+		}
+		c.add(IncompatibilityRequiredAdded, path+"."+name, "field became required without a default")
+	}
+}
+
+func (c *compatibilityChecker) compareEnum(path string, existing, desired *extv1.JSONSchemaProps) {
+	if len(desired.Enum) == 0 {
+		return
+	}
+	if len(existing.Enum) == 0 {
+		c.add(IncompatibilityEnumNarrowed, path, "enum was added")
+		return
+	}
+	desiredValues := make(map[string]struct{}, len(desired.Enum))
+	for _, v := range desired.Enum {
+		desiredValues[string(v.Raw)] = struct{}{}
+	}
+	var removed []string
+	for _, v := range existing.Enum {
+		if _, ok := desiredValues[string(v.Raw)]; !ok {
+			removed = append(removed, string(v.Raw))
+		}
+	}
+	if len(removed) > 0 {
+		c.add(IncompatibilityEnumNarrowed, path, "enum values removed: %s", strings.Join(removed, ", "))
+	}
+}
+
+func (c *compatibilityChecker) compareValidation(path string, existing, desired *extv1.JSONSchemaProps) {
+	tightened := func(format string, args ...interface{}) {
+		c.add(IncompatibilityValidationTightened, path, format, args...)
+	}
+
+	if lowered(existing.Maximum, desired.Maximum) || (!existing.ExclusiveMaximum && desired.ExclusiveMaximum) {
+		tightened("maximum was lowered")
+	}
+	if raised(existing.Minimum, desired.Minimum) || (!existing.ExclusiveMinimum && desired.ExclusiveMinimum) {
+		tightened("minimum was raised")
+	}
+	if existing.MultipleOf == nil && desired.MultipleOf != nil ||
+		existing.MultipleOf != nil && desired.MultipleOf != nil && *existing.MultipleOf != *desired.MultipleOf {
+		tightened("multipleOf was changed")
+	}
+	if loweredInt(existing.MaxLength, desired.MaxLength) {
+		tightened("maxLength was lowered")
+	}
+	if raisedInt(existing.MinLength, desired.MinLength) {
+		tightened("minLength was raised")
+	}
+	if loweredInt(existing.MaxItems, desired.MaxItems) {
+		tightened("maxItems was lowered")
+	}
+	if raisedInt(existing.MinItems, desired.MinItems) {
+		tightened("minItems was raised")
+	}
+	if loweredInt(existing.MaxProperties, desired.MaxProperties) {
+		tightened("maxProperties was lowered")
+	}
+	if raisedInt(existing.MinProperties, desired.MinProperties) {
+		tightened("minProperties was raised")
+	}
+	if !existing.UniqueItems && desired.UniqueItems {
+		tightened("items must now be unique")
+	}
+	if desired.Pattern != "" && existing.Pattern != desired.Pattern {
+		tightened("pattern changed to %q", desired.Pattern)
+	}
+	if desired.Format != "" && existing.Format != desired.Format {
+		tightened("format changed to %q", desired.Format)
+	}
+	if existing.Nullable && !desired.Nullable {
+		tightened("field is no longer nullable")
+	}
+
+	existingRules := make(map[string]struct{}, len(existing.XValidations))
+	for _, rule := range existing.XValidations {
+		existingRules[rule.Rule] = struct{}{}
+	}
+	for _, rule := range desired.XValidations {
+		if _, ok := existingRules[rule.Rule]; !ok {
+			tightened("validation rule %q was added", rule.Rule)
+		}
+	}
+}
+
+// lowered returns true if a maximum was added or lowered.
+func lowered(existing, desired *float64) bool {
+	return desired != nil && (existing == nil || *desired < *existing)
+}
+
+// raised returns true if a minimum was added or raised.
+func raised(existing, desired *float64) bool {
+	return desired != nil && (existing == nil || *desired > *existing)
+}
+
+func loweredInt(existing, desired *int64) bool {
+	return desired != nil && (existing == nil || *desired < *existing)
+}
+
+func raisedInt(existing, desired *int64) bool {
+	return desired != nil && (existing == nil || *desired > *existing)
+}
+
+func sortedKeys(m map[string]extv1.JSONSchemaProps) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
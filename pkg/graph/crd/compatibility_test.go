@@ -0,0 +1,221 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubernetes-sigs/kro/api/v1alpha1"
+)
+
+func newCompatTestCRD(spec extv1.JSONSchemaProps) *extv1.CustomResourceDefinition {
+	return SynthesizeCRD("kro.run", "v1", "Widget", spec, extv1.JSONSchemaProps{}, true, &v1alpha1.Schema{})
+}
+
+func objectSchema(props map[string]extv1.JSONSchemaProps, required ...string) extv1.JSONSchemaProps {
+	return extv1.JSONSchemaProps{Type: "object", Properties: props, Required: required}
+}
+
+func enumValues(values ...string) []extv1.JSON {
+	out := make([]extv1.JSON, 0, len(values))
+	for _, v := range values {
+		out = append(out, extv1.JSON{Raw: []byte(`"` + v + `"`)})
+	}
+	return out
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing extv1.JSONSchemaProps
+		desired  extv1.JSONSchemaProps
+		want     []IncompatibilityType
+		wantPath string
+	}{
+		{
+			name:     "identical schemas",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{"name": {Type: "string"}}),
+			desired:  objectSchema(map[string]extv1.JSONSchemaProps{"name": {Type: "string"}}),
+		},
+		{
+			name:     "adding an optional field",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{"name": {Type: "string"}}),
+			desired: objectSchema(map[string]extv1.JSONSchemaProps{
+				"name": {Type: "string"}, "size": {Type: "integer"},
+			}),
+		},
+		{
+			name: "adding a required field with a default",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{
+				"name": {Type: "string"},
+			}),
+			desired: objectSchema(map[string]extv1.JSONSchemaProps{
+				"name": {Type: "string"},
+				"size": {Type: "integer", Default: &extv1.JSON{Raw: []byte("1")}},
+			}, "size"),
+		},
+		{
+			name:     "loosening validation",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{"size": {Type: "integer", Maximum: ptr.To(5.0)}}),
+			desired:  objectSchema(map[string]extv1.JSONSchemaProps{"size": {Type: "integer", Maximum: ptr.To(10.0)}}),
+		},
+		{
+			name:     "widening an enum",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{"tier": {Type: "string", Enum: enumValues("a")}}),
+			desired:  objectSchema(map[string]extv1.JSONSchemaProps{"tier": {Type: "string", Enum: enumValues("a", "b")}}),
+		},
+		{
+			name: "removing a field",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{
+				"name": {Type: "string"}, "size": {Type: "integer"},
+			}),
+			desired:  objectSchema(map[string]extv1.JSONSchemaProps{"name": {Type: "string"}}),
+			want:     []IncompatibilityType{IncompatibilityFieldRemoved},
+			wantPath: "spec.size",
+		},
+		{
+			name: "removing a field preserved as unknown",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{
+				"name": {Type: "string"}, "size": {Type: "integer"},
+			}),
+			desired: extv1.JSONSchemaProps{
+				Type:                   "object",
+				Properties:             map[string]extv1.JSONSchemaProps{"name": {Type: "string"}},
+				XPreserveUnknownFields: ptr.To(true),
+			},
+		},
+		{
+			name: "removing a nested field",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{
+				"items": {Type: "array", Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+					Type: "object", Properties: map[string]extv1.JSONSchemaProps{"port": {Type: "integer"}},
+				}}},
+			}),
+			desired: objectSchema(map[string]extv1.JSONSchemaProps{
+				"items": {Type: "array", Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+					Type: "object", Properties: map[string]extv1.JSONSchemaProps{},
+				}}},
+			}),
+			want:     []IncompatibilityType{IncompatibilityFieldRemoved},
+			wantPath: "spec.items[*].port",
+		},
+		{
+			name:     "changing a type",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{"size": {Type: "integer"}}),
+			desired:  objectSchema(map[string]extv1.JSONSchemaProps{"size": {Type: "string"}}),
+			want:     []IncompatibilityType{IncompatibilityTypeChanged},
+			wantPath: "spec.size",
+		},
+		{
+			name:     "adding a required field without default",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{"name": {Type: "string"}}),
+			desired: objectSchema(map[string]extv1.JSONSchemaProps{
+				"name": {Type: "string"}, "size": {Type: "integer"},
+			}, "size"),
+			want:     []IncompatibilityType{IncompatibilityRequiredAdded},
+			wantPath: "spec.size",
+		},
+		{
+			name:     "making an existing field required",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{"name": {Type: "string"}}),
+			desired:  objectSchema(map[string]extv1.JSONSchemaProps{"name": {Type: "string"}}, "name"),
+			want:     []IncompatibilityType{IncompatibilityRequiredAdded},
+			wantPath: "spec.name",
+		},
+		{
+			name:     "narrowing an enum",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{"tier": {Type: "string", Enum: enumValues("a", "b")}}),
+			desired:  objectSchema(map[string]extv1.JSONSchemaProps{"tier": {Type: "string", Enum: enumValues("a")}}),
+			want:     []IncompatibilityType{IncompatibilityEnumNarrowed},
+			wantPath: "spec.tier",
+		},
+		{
+			name:     "adding an enum",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{"tier": {Type: "string"}}),
+			desired:  objectSchema(map[string]extv1.JSONSchemaProps{"tier": {Type: "string", Enum: enumValues("a")}}),
+			want:     []IncompatibilityType{IncompatibilityEnumNarrowed},
+		},
+		{
+			name: "tightening validations",
+			existing: objectSchema(map[string]extv1.JSONSchemaProps{
+				"size": {Type: "integer", Minimum: ptr.To(1.0)},
+				"name": {Type: "string"},
+			}),
+			desired: objectSchema(map[string]extv1.JSONSchemaProps{
+				"size": {Type: "integer", Minimum: ptr.To(2.0), Maximum: ptr.To(10.0)},
+				"name": {Type: "string", MaxLength: ptr.To[int64](10), Pattern: "^[a-z]+$",
+					XValidations: extv1.ValidationRules{{Rule: "self != 'admin'"}}},
+			}),
+			want: []IncompatibilityType{
+				IncompatibilityValidationTightened, IncompatibilityValidationTightened,
+				IncompatibilityValidationTightened, IncompatibilityValidationTightened,
+				IncompatibilityValidationTightened,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckCompatibility(newCompatTestCRD(tt.existing), newCompatTestCRD(tt.desired))
+			var gotTypes []IncompatibilityType
+			for _, i := range got {
+				gotTypes = append(gotTypes, i.Type)
+				assert.Equal(t, "v1", i.Version)
+			}
+			assert.ElementsMatch(t, tt.want, gotTypes, got.String())
+			if tt.wantPath != "" {
+				assert.Equal(t, tt.wantPath, got[0].Path)
+			}
+		})
+	}
+}
+
+func TestCheckCompatibility_CRDWide(t *testing.T) {
+	spec := objectSchema(map[string]extv1.JSONSchemaProps{"name": {Type: "string"}})
+
+	t.Run("scope change", func(t *testing.T) {
+		existing := newCompatTestCRD(spec)
+		desired := newCompatTestCRD(spec)
+		desired.Spec.Scope = extv1.ClusterScoped
+
+		got := CheckCompatibility(existing, desired)
+		assert.Len(t, got, 1)
+		assert.Equal(t, IncompatibilityScopeChanged, got[0].Type)
+		assert.Equal(t, "scope changed from Namespaced to Cluster (ScopeChanged)", got[0].String())
+	})
+
+	t.Run("version removed", func(t *testing.T) {
+		existing := newCompatTestCRD(spec)
+		assert.NoError(t, AddServedVersion(existing, "v1alpha1", spec, true, false))
+		desired := newCompatTestCRD(spec)
+
+		got := CheckCompatibility(existing, desired)
+		assert.Len(t, got, 1)
+		assert.Equal(t, IncompatibilityVersionRemoved, got[0].Type)
+		assert.Equal(t, "v1alpha1", got[0].Version)
+	})
+
+	t.Run("status changes are ignored", func(t *testing.T) {
+		existing := SynthesizeCRD("kro.run", "v1", "Widget", spec, objectSchema(map[string]extv1.JSONSchemaProps{
+			"endpoint": {Type: "string"},
+		}), true, &v1alpha1.Schema{})
+		desired := newCompatTestCRD(spec)
+		assert.Empty(t, CheckCompatibility(existing, desired))
+	})
+}
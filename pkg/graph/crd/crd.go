@@ -27,7 +27,60 @@ import (
 // SynthesizeCRD generates a CustomResourceDefinition for a given API version and kind
 // with the provided spec and status schemas.
 func SynthesizeCRD(group, apiVersion, kind string, spec, status extv1.JSONSchemaProps, statusFieldsOverride bool, rgSchema *v1alpha1.Schema) *extv1.CustomResourceDefinition {
-	return newCRD(group, apiVersion, kind, newCRDSchema(spec, status, statusFieldsOverride), rgSchema.AdditionalPrinterColumns, rgSchema.Metadata)
+	crd := newCRD(group, apiVersion, kind, newCRDSchema(spec, status, statusFieldsOverride), rgSchema.AdditionalPrinterColumns, rgSchema.Metadata)
+	if rgSchema.IsClusterScoped() {
+		crd.Spec.Scope = extv1.ClusterScoped
+	}
+	return crd
+}
+
+// AddServedVersion adds an additional, non-storage version to a CRD generated
+// by SynthesizeCRD. The new version shares the status schema, subresources and
+// printer columns of the storage version, and the CRD is switched to the
+// Webhook conversion strategy. Injecting the webhook client configuration is
+// the responsibility of the caller.
+func AddServedVersion(crd *extv1.CustomResourceDefinition, version string, spec extv1.JSONSchemaProps, served, deprecated bool) error {
+	storage := StorageVersion(crd)
+	if storage == nil {
+		return fmt.Errorf("CRD %s has no storage version", crd.Name)
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version {
+			return fmt.Errorf("CRD %s already has version %q", crd.Name, version)
+		}
+	}
+
+	// newCRDSchema only knows how to build a schema from a spec and status,
+	// reuse the status of the storage version as-is.
+	status := storage.Schema.OpenAPIV3Schema.Properties["status"]
+	newVersion := storage.DeepCopy()
+	newVersion.Name = version
+	newVersion.Served = served
+	newVersion.Storage = false
+	newVersion.Deprecated = deprecated
+	newVersion.Schema = &extv1.CustomResourceValidation{
+		OpenAPIV3Schema: newCRDSchema(spec, *status.DeepCopy(), false),
+	}
+	crd.Spec.Versions = append(crd.Spec.Versions, *newVersion)
+
+	crd.Spec.Conversion = &extv1.CustomResourceConversion{
+		Strategy: extv1.WebhookConverter,
+		Webhook: &extv1.WebhookConversion{
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+	return nil
+}
+
+// StorageVersion returns the storage version of the given CRD, or nil if
+// the CRD has none.
+func StorageVersion(crd *extv1.CustomResourceDefinition) *extv1.CustomResourceDefinitionVersion {
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Storage {
+			return &crd.Spec.Versions[i]
+		}
+	}
+	return nil
 }
 
 func newCRD(group, apiVersion, kind string, schema *extv1.JSONSchemaProps, additionalPrinterColumns []extv1.CustomResourceColumnDefinition, metadata *v1alpha1.CRDMetadata) *extv1.CustomResourceDefinition {
@@ -88,6 +141,18 @@ func newCRDSchema(spec, status extv1.JSONSchemaProps, statusFieldsOverride bool)
 		if _, ok := status.Properties["conditions"]; !ok {
 			status.Properties["conditions"] = defaultConditionsType
 		}
+		if _, ok := status.Properties["graphRevision"]; !ok {
+			status.Properties["graphRevision"] = defaultGraphRevisionType
+		}
+		if _, ok := status.Properties["hooks"]; !ok {
+			status.Properties["hooks"] = defaultHooksType
+		}
+		if _, ok := status.Properties["completionTime"]; !ok {
+			status.Properties["completionTime"] = defaultCompletionTimeType
+		}
+		if _, ok := status.Properties["expiresAt"]; !ok {
+			status.Properties["expiresAt"] = defaultExpiresAtType
+		}
 	}
 
 	return &extv1.JSONSchemaProps{
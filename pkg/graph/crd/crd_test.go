@@ -35,6 +35,7 @@ func TestSynthesizeCRD(t *testing.T) {
 		schema               *v1alpha1.Schema
 		expectedName         string
 		expectedGroup        string
+		expectedScope        extv1.ResourceScope
 		expectedLabels       map[string]string
 		expectedAnnotations  map[string]string
 	}{
@@ -106,6 +107,19 @@ func TestSynthesizeCRD(t *testing.T) {
 			expectedName:  "widgets.kro.com",
 			expectedGroup: "kro.com",
 		},
+		{
+			name:                 "cluster scoped",
+			group:                "kro.com",
+			apiVersion:           "v1",
+			kind:                 "Tenant",
+			spec:                 extv1.JSONSchemaProps{Type: "object"},
+			status:               extv1.JSONSchemaProps{Type: "object"},
+			statusFieldsOverride: true,
+			schema:               &v1alpha1.Schema{Scope: v1alpha1.InstanceScopeCluster},
+			expectedName:         "tenants.kro.com",
+			expectedGroup:        "kro.com",
+			expectedScope:        extv1.ClusterScoped,
+		},
 	}
 
 	for _, tt := range tests {
@@ -117,6 +131,12 @@ func TestSynthesizeCRD(t *testing.T) {
 			assert.Equal(t, tt.kind, crd.Spec.Names.Kind)
 			assert.Equal(t, tt.kind+"List", crd.Spec.Names.ListKind)
 
+			expectedScope := tt.expectedScope
+			if expectedScope == "" {
+				expectedScope = extv1.NamespaceScoped
+			}
+			assert.Equal(t, expectedScope, crd.Spec.Scope)
+
 			require.Len(t, crd.Spec.Versions, 1)
 			version := crd.Spec.Versions[0]
 			assert.Equal(t, tt.apiVersion, version.Name)
@@ -328,6 +348,10 @@ func TestNewCRDSchema(t *testing.T) {
 			if tt.expectedStateField {
 				assert.Contains(t, statusProps.Properties, "state")
 				assert.Equal(t, defaultConditionsType, statusProps.Properties["conditions"])
+				assert.Equal(t, defaultGraphRevisionType, statusProps.Properties["graphRevision"])
+				assert.Equal(t, defaultHooksType, statusProps.Properties["hooks"])
+				assert.Equal(t, defaultCompletionTimeType, statusProps.Properties["completionTime"])
+				assert.Equal(t, defaultExpiresAtType, statusProps.Properties["expiresAt"])
 			}
 
 			if tt.status.Properties != nil {
@@ -340,3 +364,43 @@ func TestNewCRDSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestAddServedVersion(t *testing.T) {
+	crd := SynthesizeCRD("kro.run", "v1", "Widget",
+		extv1.JSONSchemaProps{Type: "object"},
+		extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{
+			"endpoint": {Type: "string"},
+		}},
+		true, &v1alpha1.Schema{},
+	)
+
+	spec := extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{
+		"size": {Type: "integer"},
+	}}
+	require.NoError(t, AddServedVersion(crd, "v1alpha1", spec, true, true))
+
+	require.Len(t, crd.Spec.Versions, 2)
+	storage := StorageVersion(crd)
+	require.NotNil(t, storage)
+	assert.Equal(t, "v1", storage.Name)
+
+	added := crd.Spec.Versions[1]
+	assert.Equal(t, "v1alpha1", added.Name)
+	assert.True(t, added.Served)
+	assert.False(t, added.Storage)
+	assert.True(t, added.Deprecated)
+	assert.Equal(t, spec, added.Schema.OpenAPIV3Schema.Properties["spec"])
+	assert.Equal(t,
+		storage.Schema.OpenAPIV3Schema.Properties["status"],
+		added.Schema.OpenAPIV3Schema.Properties["status"],
+		"status schema is shared with the storage version",
+	)
+	assert.NotNil(t, added.Subresources.Status)
+
+	require.NotNil(t, crd.Spec.Conversion)
+	assert.Equal(t, extv1.WebhookConverter, crd.Spec.Conversion.Strategy)
+	assert.Equal(t, []string{"v1"}, crd.Spec.Conversion.Webhook.ConversionReviewVersions)
+
+	err := AddServedVersion(crd, "v1alpha1", spec, true, false)
+	assert.ErrorContains(t, err, `already has version "v1alpha1"`)
+}
@@ -22,6 +22,30 @@ var (
 	defaultStateType = extv1.JSONSchemaProps{
 		Type: "string",
 	}
+	// defaultGraphRevisionType is the revision of the ResourceGraphDefinition
+	// the instance was last reconciled with.
+	defaultGraphRevisionType = extv1.JSONSchemaProps{
+		Type: "integer",
+	}
+	// defaultHooksType is the latest completed run of each hook of the
+	// instance, keyed by resource ID.
+	defaultHooksType = extv1.JSONSchemaProps{
+		Type: "object",
+		AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+			Schema: &extv1.JSONSchemaProps{Type: "string"},
+		},
+	}
+	// defaultCompletionTimeType is the time a one-shot instance finished.
+	defaultCompletionTimeType = extv1.JSONSchemaProps{
+		Type:   "string",
+		Format: "date-time",
+	}
+	// defaultExpiresAtType is the time the instance expires, as set by its
+	// expiry annotations.
+	defaultExpiresAtType = extv1.JSONSchemaProps{
+		Type:   "string",
+		Format: "date-time",
+	}
 	defaultConditionsType = extv1.JSONSchemaProps{
 		Type: "array",
 		Items: &extv1.JSONSchemaPropsOrArray{
@@ -0,0 +1,80 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import "errors"
+
+// FieldError is an error of the builder attributed to the field of the
+// ResourceGraphDefinition spec it originates from. Its message is the message
+// of the wrapped error.
+type FieldError struct {
+	// ResourceID is the ID of the resource the error originates from, or empty
+	// if it does not originate from a resource.
+	ResourceID string
+	// Field is the path of the field, relative to the resource if ResourceID
+	// is set and to the spec otherwise, e.g. readyWhen,
+	// template.spec.replicas or schema.status.endpoint. It is empty if the
+	// error can't be attributed more precisely.
+	Field string
+
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// specFieldError attributes err to a field of the spec.
+func specFieldError(field string, err error) error {
+	return &FieldError{Field: field, Err: err}
+}
+
+// specError attributes err to a field of the spec, unless it is already
+// attributed to a more specific field.
+func specError(field string, err error) error {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return err
+	}
+	return specFieldError(field, err)
+}
+
+// resourceFieldError attributes err to a field of a resource.
+func resourceFieldError(id, field string, err error) error {
+	return &FieldError{ResourceID: id, Field: field, Err: err}
+}
+
+// resourceError attributes err to a resource, unless it is already attributed
+// to one of its fields.
+func resourceError(id string, err error) error {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return err
+	}
+	return resourceFieldError(id, "", err)
+}
+
+// templateField returns the path of a template field of the node, relative to
+// the resource. Fields of external references are attributed to the reference.
+func templateField(node *Node, path string) string {
+	if node.Meta.Type.IsExternal() {
+		return "externalRef"
+	}
+	return "template." + path
+}
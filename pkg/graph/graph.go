@@ -17,6 +17,7 @@ package graph
 import (
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
+	"github.com/kubernetes-sigs/kro/pkg/conversion"
 	"github.com/kubernetes-sigs/kro/pkg/graph/dag"
 )
 
@@ -41,4 +42,8 @@ type Graph struct {
 
 	// CRD is the generated CustomResourceDefinition for the instance.
 	CRD *extv1.CustomResourceDefinition
+
+	// Converter converts instances between the versions of the generated CRD.
+	// It is nil if the CRD has a single version.
+	Converter *conversion.Converter
 }
@@ -16,6 +16,7 @@ package graph
 
 import (
 	"slices"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -40,6 +41,12 @@ const (
 	MetadataNamePath = "metadata.name"
 	// MetadataNamespacePath is the path to the resource namespace field.
 	MetadataNamespacePath = "metadata.namespace"
+	// ExternalSelectorPath is the path to the label selector in the template
+	// of external collections.
+	ExternalSelectorPath = "selector"
+	// ExternalDefaultPath is the path to the default object in the template
+	// of optional external references.
+	ExternalDefaultPath = "default"
 )
 
 // NodeType identifies the kind of node in the resource graph.
@@ -55,6 +62,9 @@ const (
 	NodeTypeExternal
 	// NodeTypeInstance is the instance node (ID: "instance").
 	NodeTypeInstance
+	// NodeTypeExternalCollection is an external reference selecting objects by
+	// label (read-only, not applied). It resolves to a list of objects.
+	NodeTypeExternalCollection
 )
 
 // String returns a human-readable string for the node type.
@@ -68,11 +78,25 @@ func (t NodeType) String() string {
 		return "External"
 	case NodeTypeInstance:
 		return "Instance"
+	case NodeTypeExternalCollection:
+		return "ExternalCollection"
 	default:
 		return "Unknown"
 	}
 }
 
+// IsList returns true if nodes of this type are exposed to CEL expressions as
+// a list of objects, and readyWhen expressions are evaluated per item.
+func (t NodeType) IsList() bool {
+	return t == NodeTypeCollection || t == NodeTypeExternalCollection
+}
+
+// IsExternal returns true if nodes of this type are read from the cluster
+// instead of being applied.
+func (t NodeType) IsExternal() bool {
+	return t == NodeTypeExternal || t == NodeTypeExternalCollection
+}
+
 // NodeMeta contains immutable metadata about a node.
 // This is grouped separately for clarity and to distinguish
 // identification data from behavioral data.
@@ -88,8 +112,15 @@ type NodeMeta struct {
 	GVR schema.GroupVersionResource
 	// Namespaced indicates if the resource is namespace-scoped.
 	Namespaced bool
+	// Optional indicates that the resource of an external reference may not
+	// exist, in which case it resolves to its default object, if any.
+	Optional bool
 	// Dependencies lists the IDs of nodes this node depends on.
 	Dependencies []string
+	// WaitsFor lists the IDs of nodes that must be ready before this node is
+	// applied, without this node referencing them. It orders hooks with the
+	// other nodes of the graph.
+	WaitsFor []string
 }
 
 // ForEachDimension represents a parsed forEach dimension from an RGD resource.
@@ -126,6 +157,118 @@ type Node struct {
 	// ForEach holds the forEach dimensions for collection resources.
 	// nil or empty means this is not a collection.
 	ForEach []ForEachDimension
+
+	// Drift controls how changes made to the resources outside of kro are
+	// handled.
+	Drift DriftPolicy
+
+	// IgnoreFields are the field paths kro stops applying once the resources
+	// exist, leaving them to other field managers.
+	IgnoreFields []string
+
+	// UpdatePolicy controls whether the resources are updated in place or
+	// deleted and created again when their template changes.
+	UpdatePolicy UpdatePolicy
+
+	// Hook is set if the node is a lifecycle hook.
+	Hook *Hook
+
+	// Completion is set on the instance node of graphs the instances of
+	// which are one-shot workflows.
+	Completion *Completion
+}
+
+// Completion holds the conditions under which a one-shot instance finishes.
+type Completion struct {
+	// SuccessWhen are CEL expressions that must all evaluate to true for the
+	// instance to succeed.
+	SuccessWhen []string
+	// FailureWhen are CEL expressions that fail the instance as soon as one
+	// of them evaluates to true.
+	FailureWhen []string
+	// TTLAfterFinished is the time a finished instance is kept before being
+	// deleted. nil keeps finished instances.
+	TTLAfterFinished *time.Duration
+}
+
+// Hook is the parsed hook of a node.
+type Hook struct {
+	// Phase is when the hook runs.
+	Phase HookPhase
+	// DeleteOnCompletion is true if the resource of the hook is deleted once
+	// the hook completed.
+	DeleteOnCompletion bool
+}
+
+// HookPhase is when a hook runs.
+type HookPhase int
+
+const (
+	// HookPhasePreCreate hooks run before the other nodes are applied.
+	HookPhasePreCreate HookPhase = iota
+	// HookPhasePostReady hooks run once the other nodes are ready.
+	HookPhasePostReady
+	// HookPhasePreDelete hooks run before the resources of the instance are
+	// deleted.
+	HookPhasePreDelete
+)
+
+// String returns a human-readable string for the hook phase.
+func (p HookPhase) String() string {
+	switch p {
+	case HookPhasePreCreate:
+		return "PreCreate"
+	case HookPhasePostReady:
+		return "PostReady"
+	case HookPhasePreDelete:
+		return "PreDelete"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsHook returns true if the node is a hook of the given phase.
+func (n *Node) IsHook(phase HookPhase) bool {
+	return n.Hook != nil && n.Hook.Phase == phase
+}
+
+// DriftPolicy is the parsed drift policy of a node.
+type DriftPolicy struct {
+	// Report is true if drifted fields are reported instead of corrected.
+	Report bool
+	// Ignore holds the field paths on which drift is ignored.
+	Ignore []string
+}
+
+// UpdatePolicy is how changes to the template of a node are rolled out to
+// its resources.
+type UpdatePolicy int
+
+const (
+	// UpdatePolicyUpdate updates resources in place.
+	UpdatePolicyUpdate UpdatePolicy = iota
+	// UpdatePolicyRecreate recreates resources whenever their desired state
+	// changes.
+	UpdatePolicyRecreate
+	// UpdatePolicyRecreateOnImmutableError recreates resources when an
+	// update is rejected because it changes an immutable field.
+	UpdatePolicyRecreateOnImmutableError
+)
+
+// Recreates returns true if resources are recreated instead of being left in
+// error when an update changes an immutable field.
+func (p UpdatePolicy) Recreates() bool {
+	return p != UpdatePolicyUpdate
+}
+
+// IsOptionalValue returns true if the node is exposed to CEL expressions as an
+// optional value: an optional external reference without a default object.
+func (n *Node) IsOptionalValue() bool {
+	if !n.Meta.Optional {
+		return false
+	}
+	_, hasDefault := n.Template.Object[ExternalDefaultPath]
+	return !hasDefault
 }
 
 // DeepCopy creates a deep copy of the Node.
@@ -142,11 +285,35 @@ func (n *Node) DeepCopy() *Node {
 			Type:         n.Meta.Type,
 			GVR:          n.Meta.GVR,
 			Namespaced:   n.Meta.Namespaced,
+			Optional:     n.Meta.Optional,
 			Dependencies: slices.Clone(n.Meta.Dependencies),
+			WaitsFor:     slices.Clone(n.Meta.WaitsFor),
 		},
 		IncludeWhen: slices.Clone(n.IncludeWhen),
 		ReadyWhen:   slices.Clone(n.ReadyWhen),
 		ForEach:     slices.Clone(n.ForEach),
+		Drift: DriftPolicy{
+			Report: n.Drift.Report,
+			Ignore: slices.Clone(n.Drift.Ignore),
+		},
+		IgnoreFields: slices.Clone(n.IgnoreFields),
+		UpdatePolicy: n.UpdatePolicy,
+	}
+
+	if n.Hook != nil {
+		hook := *n.Hook
+		cp.Hook = &hook
+	}
+
+	if n.Completion != nil {
+		completion := *n.Completion
+		completion.SuccessWhen = slices.Clone(n.Completion.SuccessWhen)
+		completion.FailureWhen = slices.Clone(n.Completion.FailureWhen)
+		if n.Completion.TTLAfterFinished != nil {
+			ttl := *n.Completion.TTLAfterFinished
+			completion.TTLAfterFinished = &ttl
+		}
+		cp.Completion = &completion
 	}
 
 	if n.Template != nil {
@@ -106,7 +106,8 @@ func isKROReservedWord(word string) bool {
 // the given resource graph definition.
 func validateResourceGraphDefinitionNamingConventions(rgd *v1alpha1.ResourceGraphDefinition) error {
 	if !isValidKindName(rgd.Spec.Schema.Kind) {
-		return fmt.Errorf("%s: kind '%s' is not a valid KRO kind name: must be UpperCamelCase", ErrNamingConvention, rgd.Spec.Schema.Kind)
+		return specFieldError("schema.kind", fmt.Errorf("%s: kind '%s' is not a valid KRO kind name: must be UpperCamelCase",
+			ErrNamingConvention, rgd.Spec.Schema.Kind))
 	}
 	err := validateResourceIDs(rgd)
 	if err != nil {
@@ -127,15 +128,15 @@ func validateResourceIDs(rgd *v1alpha1.ResourceGraphDefinition) error {
 	seen := make(map[string]struct{})
 	for _, res := range rgd.Spec.Resources {
 		if isKROReservedWord(res.ID) {
-			return fmt.Errorf("id %s is a reserved keyword in KRO", res.ID)
+			return resourceFieldError(res.ID, "id", fmt.Errorf("id %s is a reserved keyword in KRO", res.ID))
 		}
 
 		if !isValidResourceID(res.ID) {
-			return fmt.Errorf("id %s is not a valid KRO resource id: must be lower camelCase", res.ID)
+			return resourceFieldError(res.ID, "id", fmt.Errorf("id %s is not a valid KRO resource id: must be lower camelCase", res.ID))
 		}
 
 		if _, ok := seen[res.ID]; ok {
-			return fmt.Errorf("found duplicate resource IDs %s", res.ID)
+			return resourceFieldError(res.ID, "id", fmt.Errorf("found duplicate resource IDs %s", res.ID))
 		}
 		seen[res.ID] = struct{}{}
 	}
@@ -147,7 +148,7 @@ func validateResourceIDs(rgd *v1alpha1.ResourceGraphDefinition) error {
 	}
 	for _, res := range rgd.Spec.Resources {
 		if err := validateForEachDimensions(res, resourceIDs); err != nil {
-			return err
+			return resourceFieldError(res.ID, "forEach", err)
 		}
 	}
 
@@ -256,5 +257,18 @@ func validateCombinableResourceFields(res *v1alpha1.Resource) error {
 	if hasExternalRef && len(res.ForEach) > 0 {
 		return fmt.Errorf("resource %q: cannot use externalRef with forEach", res.ID)
 	}
+	if hasExternalRef {
+		hasName := res.ExternalRef.Metadata.Name != ""
+		hasSelector := res.ExternalRef.Metadata.Selector != nil
+		if hasName == hasSelector {
+			return fmt.Errorf("resource %q: externalRef must set exactly one of metadata.name or metadata.selector", res.ID)
+		}
+		if res.ExternalRef.Default != nil && !res.ExternalRef.Optional {
+			return fmt.Errorf("resource %q: externalRef default can only be set on optional external references", res.ID)
+		}
+		if res.ExternalRef.Optional && hasSelector {
+			return fmt.Errorf("resource %q: optional externalRef must select a resource by name", res.ID)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,282 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history records the state kro rendered for instances. Every time
+// the rendered child manifests of an instance change, an entry is stored in a
+// ControllerRevision owned by the instance, along with the instance spec and
+// the graph revision they were rendered from.
+package history
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+const (
+	// DefaultLimit is the number of entries kept per instance by default.
+	DefaultLimit = 10
+
+	// HashAnnotation holds the hash of the rendered state recorded in an
+	// entry.
+	HashAnnotation = metadata.LabelKROPrefix + "history-hash"
+	// InstanceGenerationAnnotation holds the generation of the instance an
+	// entry was rendered for.
+	InstanceGenerationAnnotation = metadata.LabelKROPrefix + "instance-generation"
+	// GraphRevisionAnnotation holds the graph revision an entry was rendered
+	// from.
+	GraphRevisionAnnotation = metadata.LabelKROPrefix + "graph-revision"
+
+	encodingGzip = "gzip"
+	// redactedPrefix prefixes the digest replacing redacted values.
+	redactedPrefix = "redacted:sha256:"
+)
+
+// Entry is the state kro rendered for an instance at one point in time.
+type Entry struct {
+	// GraphRevision is the revision of the ResourceGraphDefinition the
+	// resources were rendered from.
+	GraphRevision int64 `json:"graphRevision,omitempty"`
+	// InstanceGeneration is the generation of the instance the resources were
+	// rendered for.
+	InstanceGeneration int64 `json:"instanceGeneration,omitempty"`
+	// Spec is the instance spec the resources were rendered for.
+	Spec map[string]any `json:"spec,omitempty"`
+	// Resources are the rendered child manifests, as applied, with the values
+	// of Secrets redacted, see Redact.
+	Resources []map[string]any `json:"resources"`
+}
+
+// payload is the content of the ControllerRevision data.
+type payload struct {
+	Encoding string `json:"encoding"`
+	Data     []byte `json:"data"`
+}
+
+// Hash returns the hash identifying the rendered state of the entry. Entries
+// rendering the same resources from the same graph revision have the same
+// hash, regardless of the instance generation.
+func Hash(entry *Entry) (string, error) {
+	data, err := json.Marshal(struct {
+		GraphRevision int64            `json:"graphRevision"`
+		Resources     []map[string]any `json:"resources"`
+	}{entry.GraphRevision, entry.Resources})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal history entry: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// Redact returns the manifest with the values of the data and stringData of
+// core/v1 Secrets replaced by their digest, so that the history, readable by
+// anyone allowed to read ControllerRevisions, does not leak them. Changed
+// values still change the digest, and so the hash of the entry. Other
+// manifests are returned as is.
+func Redact(resource map[string]any) map[string]any {
+	if resource["apiVersion"] != "v1" || resource["kind"] != "Secret" {
+		return resource
+	}
+	redacted := make(map[string]any, len(resource))
+	for k, v := range resource {
+		redacted[k] = v
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := resource[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		digests := make(map[string]any, len(values))
+		for k, v := range values {
+			sum := sha256.Sum256([]byte(fmt.Sprint(v)))
+			digests[k] = redactedPrefix + hex.EncodeToString(sum[:])
+		}
+		redacted[field] = digests
+	}
+	return redacted
+}
+
+// Namespace returns the namespace the history of the instance is stored in.
+// The history of cluster scoped instances is stored in the default namespace.
+func Namespace(instance metav1.Object) string {
+	if ns := instance.GetNamespace(); ns != "" {
+		return ns
+	}
+	return metav1.NamespaceDefault
+}
+
+// Name returns the name of the ControllerRevision holding the given revision
+// of the history of an instance.
+func Name(kind, instance string, revision int64) string {
+	return fmt.Sprintf("%s-%s-%d", strings.ToLower(kind), instance, revision)
+}
+
+// Selector returns the label selector matching the history of the instance.
+func Selector(instance metav1.Object) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{metadata.InstanceIDLabel: string(instance.GetUID())})
+}
+
+// NewControllerRevision returns the ControllerRevision recording the entry as
+// the given revision of the history of the instance.
+func NewControllerRevision(
+	instance *unstructured.Unstructured,
+	entry *Entry,
+	revision int64,
+) (*appsv1.ControllerRevision, error) {
+	hash, err := Hash(entry)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal history entry: %w", err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, fmt.Errorf("failed to compress history entry: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress history entry: %w", err)
+	}
+	data, err := json.Marshal(payload{Encoding: encodingGzip, Data: buf.Bytes()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal history entry: %w", err)
+	}
+
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(instance.GetKind(), instance.GetName(), revision),
+			Namespace: Namespace(instance),
+			Labels: map[string]string{
+				metadata.InstanceIDLabel:        string(instance.GetUID()),
+				metadata.InstanceLabel:          instance.GetName(),
+				metadata.InstanceNamespaceLabel: instance.GetNamespace(),
+			},
+			Annotations: map[string]string{
+				HashAnnotation:               hash,
+				InstanceGenerationAnnotation: fmt.Sprint(entry.InstanceGeneration),
+				GraphRevisionAnnotation:      fmt.Sprint(entry.GraphRevision),
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: instance.GetAPIVersion(),
+				Kind:       instance.GetKind(),
+				Name:       instance.GetName(),
+				UID:        instance.GetUID(),
+			}},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: revision,
+	}, nil
+}
+
+// Decode returns the entry recorded in the ControllerRevision.
+func Decode(cr *appsv1.ControllerRevision) (*Entry, error) {
+	var p payload
+	if err := json.Unmarshal(cr.Data.Raw, &p); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal history entry %s: %w", cr.Name, err)
+	}
+	if p.Encoding != encodingGzip {
+		return nil, fmt.Errorf("history entry %s has unsupported encoding %q", cr.Name, p.Encoding)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(p.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress history entry %s: %w", cr.Name, err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress history entry %s: %w", cr.Name, err)
+	}
+	entry := &Entry{}
+	if err := json.Unmarshal(raw, entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal history entry %s: %w", cr.Name, err)
+	}
+	return entry, nil
+}
+
+// List returns the history of the instance, oldest first.
+func List(ctx context.Context, client kubernetes.Interface, instance metav1.Object) ([]appsv1.ControllerRevision, error) {
+	list, err := client.AppsV1().ControllerRevisions(Namespace(instance)).List(ctx, metav1.ListOptions{
+		LabelSelector: Selector(instance).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history of %s: %w", instance.GetName(), err)
+	}
+	revisions := list.Items
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// Rollback pins the instance to the graph revision of the entry. If
+// restoreSpec is true, the spec of the instance is also overwritten with the
+// spec of the entry, otherwise only the graph revision label is patched.
+// instance must be the current state of the instance, client the client of its
+// kind and namespace.
+func Rollback(
+	ctx context.Context,
+	client dynamic.ResourceInterface,
+	instance *unstructured.Unstructured,
+	entry *Entry,
+	restoreSpec bool,
+) (*unstructured.Unstructured, error) {
+	if entry.GraphRevision == 0 {
+		return nil, fmt.Errorf("history entry does not record a graph revision")
+	}
+	pin := strconv.FormatInt(entry.GraphRevision, 10)
+
+	if !restoreSpec {
+		patch, err := json.Marshal(map[string]any{
+			"metadata": map[string]any{"labels": map[string]string{metadata.GraphRevisionLabel: pin}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal patch: %w", err)
+		}
+		return client.Patch(ctx, instance.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+
+	instance = instance.DeepCopy()
+	if entry.Spec != nil {
+		instance.Object["spec"] = runtime.DeepCopyJSONValue(entry.Spec)
+	} else {
+		delete(instance.Object, "spec")
+	}
+	labels := instance.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[metadata.GraphRevisionLabel] = pin
+	instance.SetLabels(labels)
+	return client.Update(ctx, instance, metav1.UpdateOptions{})
+}
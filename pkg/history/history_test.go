@@ -0,0 +1,204 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+func newInstance(namespace string) *unstructured.Unstructured {
+	inst := &unstructured.Unstructured{}
+	inst.SetAPIVersion("kro.run/v1alpha1")
+	inst.SetKind("WebApp")
+	inst.SetNamespace(namespace)
+	inst.SetName("my-app")
+	inst.SetUID("1234")
+	return inst
+}
+
+func newEntry(replicas int64) *Entry {
+	return &Entry{
+		GraphRevision:      2,
+		InstanceGeneration: 5,
+		Spec:               map[string]any{"replicas": replicas},
+		Resources: []map[string]any{{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": "my-app"},
+			"spec":       map[string]any{"replicas": replicas},
+		}},
+	}
+}
+
+func TestControllerRevisionRoundTrip(t *testing.T) {
+	entry := newEntry(3)
+	cr, err := NewControllerRevision(newInstance("team-a"), entry, 7)
+	require.NoError(t, err)
+
+	assert.Equal(t, "webapp-my-app-7", cr.Name)
+	assert.Equal(t, "team-a", cr.Namespace)
+	assert.Equal(t, int64(7), cr.Revision)
+	assert.Equal(t, "1234", cr.Labels[metadata.InstanceIDLabel])
+	assert.Equal(t, "5", cr.Annotations[InstanceGenerationAnnotation])
+	assert.Equal(t, "2", cr.Annotations[GraphRevisionAnnotation])
+	require.Len(t, cr.OwnerReferences, 1)
+	assert.Equal(t, "WebApp", cr.OwnerReferences[0].Kind)
+
+	hash, err := Hash(entry)
+	require.NoError(t, err)
+	assert.Equal(t, hash, cr.Annotations[HashAnnotation])
+
+	decoded, err := Decode(cr)
+	require.NoError(t, err)
+	// JSON decodes numbers as float64.
+	assert.Equal(t, float64(3), decoded.Spec["replicas"])
+	assert.Equal(t, int64(2), decoded.GraphRevision)
+	assert.Equal(t, int64(5), decoded.InstanceGeneration)
+	require.Len(t, decoded.Resources, 1)
+	assert.Equal(t, "Deployment", decoded.Resources[0]["kind"])
+}
+
+func TestHash(t *testing.T) {
+	a, err := Hash(newEntry(3))
+	require.NoError(t, err)
+
+	other := newEntry(3)
+	other.InstanceGeneration = 6
+	b, err := Hash(other)
+	require.NoError(t, err)
+	assert.Equal(t, a, b, "the instance generation should not change the hash")
+
+	c, err := Hash(newEntry(4))
+	require.NoError(t, err)
+	assert.NotEqual(t, a, c)
+}
+
+func TestNamespace(t *testing.T) {
+	assert.Equal(t, "team-a", Namespace(newInstance("team-a")))
+	assert.Equal(t, "default", Namespace(newInstance("")))
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset()
+	inst := newInstance("team-a")
+
+	for _, revision := range []int64{3, 1, 2} {
+		cr, err := NewControllerRevision(inst, newEntry(revision), revision)
+		require.NoError(t, err)
+		_, err = client.AppsV1().ControllerRevisions("team-a").Create(ctx, cr, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	other := newInstance("team-a")
+	other.SetName("other")
+	other.SetUID("5678")
+	cr, err := NewControllerRevision(other, newEntry(1), 1)
+	require.NoError(t, err)
+	_, err = client.AppsV1().ControllerRevisions("team-a").Create(ctx, cr, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	revisions, err := List(ctx, client, inst)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	for i, cr := range revisions {
+		assert.Equal(t, int64(i+1), cr.Revision)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	secret := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]any{"name": "db"},
+		"data":       map[string]any{"password": "c2VjcmV0"},
+		"stringData": map[string]any{"user": "admin"},
+	}
+	redacted := Redact(secret)
+
+	assert.Equal(t, secret["metadata"], redacted["metadata"])
+	data := redacted["data"].(map[string]any)
+	stringData := redacted["stringData"].(map[string]any)
+	assert.Regexp(t, "^redacted:sha256:[0-9a-f]{64}$", data["password"])
+	assert.Regexp(t, "^redacted:sha256:[0-9a-f]{64}$", stringData["user"])
+	// The manifest is not modified.
+	assert.Equal(t, "c2VjcmV0", secret["data"].(map[string]any)["password"])
+
+	// Changed values change the digest.
+	changed := Redact(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"data":       map[string]any{"password": "b3RoZXI="},
+	})
+	assert.NotEqual(t, data["password"], changed["data"].(map[string]any)["password"])
+
+	// Other kinds are left alone.
+	configMap := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"data":       map[string]any{"user": "admin"},
+	}
+	assert.Equal(t, configMap, Redact(configMap))
+}
+
+func TestRollback(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "kro.run", Version: "v1alpha1", Resource: "webapps"}
+	newClient := func() (*unstructured.Unstructured, dynamic.ResourceInterface) {
+		inst := newInstance("default")
+		inst.SetLabels(map[string]string{"team": "a"})
+		inst.Object["spec"] = map[string]any{"replicas": int64(5)}
+		client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{gvr: "WebAppList"}, inst)
+		return inst, client.Resource(gvr).Namespace("default")
+	}
+
+	t.Run("pins the graph revision", func(t *testing.T) {
+		inst, client := newClient()
+		got, err := Rollback(t.Context(), client, inst, newEntry(3), false)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"team": "a", metadata.GraphRevisionLabel: "2"}, got.GetLabels())
+		assert.Equal(t, map[string]any{"replicas": int64(5)}, got.Object["spec"])
+	})
+
+	t.Run("restores the spec", func(t *testing.T) {
+		inst, client := newClient()
+		got, err := Rollback(t.Context(), client, inst, newEntry(3), true)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"team": "a", metadata.GraphRevisionLabel: "2"}, got.GetLabels())
+		assert.Equal(t, map[string]any{"replicas": int64(3)}, got.Object["spec"])
+		// The given instance is not modified.
+		assert.Equal(t, map[string]any{"replicas": int64(5)}, inst.Object["spec"])
+	})
+
+	t.Run("entry without graph revision", func(t *testing.T) {
+		inst, client := newClient()
+		entry := newEntry(3)
+		entry.GraphRevision = 0
+		_, err := Rollback(t.Context(), client, inst, entry, false)
+		assert.Error(t, err)
+	})
+}
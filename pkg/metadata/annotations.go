@@ -0,0 +1,35 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AllowBreakingChangesAnnotation can be set to "true" on a
+	// ResourceGraphDefinition to let kro apply schema changes that are
+	// incompatible with the existing CRD.
+	AllowBreakingChangesAnnotation = LabelKROPrefix + "allow-breaking-changes"
+	// DesiredHashAnnotation records the hash of the desired state applied to
+	// resources that are recreated whenever it changes.
+	DesiredHashAnnotation = LabelKROPrefix + "desired-hash"
+)
+
+// AllowsBreakingChanges returns true if the object explicitly opted into
+// breaking schema changes.
+func AllowsBreakingChanges(meta metav1.Object) bool {
+	return booleanFromString(meta.GetAnnotations()[AllowBreakingChangesAnnotation])
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TTLAnnotation can be set on an instance to delete it once it is older
+	// than the given duration, e.g. "72h".
+	TTLAnnotation = LabelKROPrefix + "ttl"
+	// ExpiresAtAnnotation can be set on an instance to delete it at the given
+	// RFC 3339 time, e.g. "2025-07-01T00:00:00Z".
+	ExpiresAtAnnotation = LabelKROPrefix + "expires-at"
+)
+
+// ExpiresAt returns the time the object expires, and false if it does not
+// expire. The time to live is counted from the creation of the object. If
+// both annotations are set, the object expires at the earliest of the two.
+func ExpiresAt(meta metav1.Object) (time.Time, bool, error) {
+	var expiresAt time.Time
+	annotations := meta.GetAnnotations()
+
+	if v, ok := annotations[TTLAnnotation]; ok && v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return time.Time{}, false, fmt.Errorf("invalid %s annotation %q: must be a positive duration", TTLAnnotation, v)
+		}
+		expiresAt = meta.GetCreationTimestamp().Add(ttl)
+	}
+	if v, ok := annotations[ExpiresAtAnnotation]; ok && v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s annotation %q: must be an RFC 3339 time", ExpiresAtAnnotation, v)
+		}
+		if expiresAt.IsZero() || t.Before(expiresAt) {
+			expiresAt = t
+		}
+	}
+	return expiresAt, !expiresAt.IsZero(), nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExpiresAt(t *testing.T) {
+	created := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        time.Time
+		wantErr     bool
+	}{
+		{name: "no expiry"},
+		{name: "empty annotation", annotations: map[string]string{TTLAnnotation: ""}},
+		{
+			name:        "ttl",
+			annotations: map[string]string{TTLAnnotation: "72h"},
+			want:        created.Add(72 * time.Hour),
+		},
+		{
+			name:        "expires at",
+			annotations: map[string]string{ExpiresAtAnnotation: "2025-06-02T12:00:00Z"},
+			want:        time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "earliest of both",
+			annotations: map[string]string{TTLAnnotation: "1h", ExpiresAtAnnotation: "2025-06-02T12:00:00Z"},
+			want:        created.Add(time.Hour),
+		},
+		{name: "invalid ttl", annotations: map[string]string{TTLAnnotation: "3d"}, wantErr: true},
+		{name: "negative ttl", annotations: map[string]string{TTLAnnotation: "-1h"}, wantErr: true},
+		{name: "invalid time", annotations: map[string]string{ExpiresAtAnnotation: "tomorrow"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := ExpiresAt(&metav1.ObjectMeta{
+				Annotations:       tt.annotations,
+				CreationTimestamp: metav1.NewTime(created),
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, !tt.want.IsZero(), ok)
+			assert.True(t, tt.want.Equal(got), "expected %s, got %s", tt.want, got)
+		})
+	}
+}
@@ -40,6 +40,9 @@ const (
 	CollectionIndexLabel = LabelKROPrefix + "collection-index"
 	CollectionSizeLabel  = LabelKROPrefix + "collection-size"
 
+	// HookRunLabel identifies the run of the hook that created a resource.
+	HookRunLabel = LabelKROPrefix + "hook-run"
+
 	OwnedLabel      = LabelKROPrefix + "owned"
 	KROVersionLabel = LabelKROPrefix + "kro-version"
 
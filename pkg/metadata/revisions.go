@@ -0,0 +1,64 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GraphRevisionLabel can be set on an instance to pin it to a revision of
+	// its ResourceGraphDefinition, e.g. "3". Pinned instances are reconciled
+	// with the graph of that revision instead of the latest one.
+	GraphRevisionLabel = LabelKROPrefix + "graph-revision"
+	// ReadyGraphRevisionLabel is set by kro on the instances of
+	// ResourceGraphDefinitions declaring a rollout policy, to the revision the
+	// instance is Ready with. It lets rollouts follow the readiness of
+	// instances from their metadata alone.
+	ReadyGraphRevisionLabel = LabelKROPrefix + "ready-graph-revision"
+)
+
+// GraphRevisionName returns the name of the GraphRevision holding the given
+// revision of a ResourceGraphDefinition.
+func GraphRevisionName(rgdName string, revision int64) string {
+	return fmt.Sprintf("%s-%d", rgdName, revision)
+}
+
+// PinnedGraphRevision returns the revision the object is pinned to, or 0 if it
+// is not pinned.
+func PinnedGraphRevision(meta metav1.Object) (int64, error) {
+	v, ok := meta.GetLabels()[GraphRevisionLabel]
+	if !ok || v == "" {
+		return 0, nil
+	}
+	revision, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || revision < 1 {
+		return 0, fmt.Errorf("invalid %s label %q: must be a positive integer", GraphRevisionLabel, v)
+	}
+	return revision, nil
+}
+
+// ReadyGraphRevision returns the revision the object is Ready with, or 0 if it
+// is not Ready or the label is invalid.
+func ReadyGraphRevision(meta metav1.Object) int64 {
+	revision, err := strconv.ParseInt(meta.GetLabels()[ReadyGraphRevisionLabel], 10, 64)
+	if err != nil || revision < 1 {
+		return 0
+	}
+	return revision
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGraphRevisionName(t *testing.T) {
+	assert.Equal(t, "webapp-3", GraphRevisionName("webapp", 3))
+}
+
+func TestPinnedGraphRevision(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		want    int64
+		wantErr bool
+	}{
+		{name: "not pinned"},
+		{name: "empty label", labels: map[string]string{GraphRevisionLabel: ""}},
+		{name: "pinned", labels: map[string]string{GraphRevisionLabel: "3"}, want: 3},
+		{name: "not a number", labels: map[string]string{GraphRevisionLabel: "latest"}, wantErr: true},
+		{name: "zero", labels: map[string]string{GraphRevisionLabel: "0"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PinnedGraphRevision(&metav1.ObjectMeta{Labels: tt.labels})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadyGraphRevision(t *testing.T) {
+	assert.Equal(t, int64(0), ReadyGraphRevision(&metav1.ObjectMeta{}))
+	assert.Equal(t, int64(3), ReadyGraphRevision(&metav1.ObjectMeta{Labels: map[string]string{ReadyGraphRevisionLabel: "3"}}))
+	assert.Equal(t, int64(0), ReadyGraphRevision(&metav1.ObjectMeta{Labels: map[string]string{ReadyGraphRevisionLabel: "latest"}}))
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+)
+
+// CompletionOutcome is the outcome of a one-shot instance.
+type CompletionOutcome int
+
+const (
+	// CompletionRunning means neither the success nor the failure
+	// expressions of the instance are met yet.
+	CompletionRunning CompletionOutcome = iota
+	// CompletionSucceeded means all the success expressions are met.
+	CompletionSucceeded
+	// CompletionFailed means one of the failure expressions is met.
+	CompletionFailed
+)
+
+// EvaluateCompletion evaluates the completion expressions of the instance
+// node against the observed state of its dependencies. It returns the outcome
+// and, for failed instances, the failure expression that was met. Expressions
+// referencing data that is not observed yet are not met.
+func (n *Node) EvaluateCompletion() (CompletionOutcome, string, error) {
+	completion := n.Spec.Completion
+	if completion == nil {
+		return CompletionRunning, "", fmt.Errorf("node %q has no completion expressions", n.Spec.Meta.ID)
+	}
+
+	ctx := n.buildContext()
+	singles, collections, _ := n.contextDependencyIDs(nil)
+	if len(n.observed) > 0 {
+		ctx[graph.InstanceNodeID] = withStatusOmitted(n.observed[0].Object)
+		singles = append(singles, graph.InstanceNodeID)
+	}
+	env, err := buildEnv(singles, collections)
+	if err != nil {
+		return CompletionRunning, "", fmt.Errorf("failed to build completion env: %w", err)
+	}
+
+	met := func(expr string) (bool, error) {
+		val, err := evalRawCEL(env, expr, ctx)
+		if err != nil {
+			if isCELDataPending(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("%q: %w", expr, err)
+		}
+		result, _ := val.(bool)
+		return result, nil
+	}
+
+	for _, expr := range completion.FailureWhen {
+		ok, err := met(expr)
+		if err != nil {
+			return CompletionRunning, "", fmt.Errorf("failureWhen %w", err)
+		}
+		if ok {
+			return CompletionFailed, expr, nil
+		}
+	}
+	for _, expr := range completion.SuccessWhen {
+		ok, err := met(expr)
+		if err != nil {
+			return CompletionRunning, "", fmt.Errorf("successWhen %w", err)
+		}
+		if !ok {
+			return CompletionRunning, "", nil
+		}
+	}
+	return CompletionSucceeded, "", nil
+}
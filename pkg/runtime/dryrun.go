@@ -0,0 +1,279 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+)
+
+// DryRunError is a failure found by DryRun. It identifies the resource and
+// the field of its definition that failed, and the expression that failed to
+// evaluate, if any.
+type DryRunError struct {
+	// NodeID is the ID of the resource that failed to render.
+	NodeID string
+	// Field is the field of the resource definition that failed: includeWhen,
+	// forEach, or a path in the resource template.
+	Field string
+	// Expression is the CEL expression that failed to evaluate, or produced
+	// the invalid value. It is empty if the value is not templated.
+	Expression string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *DryRunError) Error() string {
+	if e.Expression == "" {
+		return fmt.Sprintf("resource %q %s: %v", e.NodeID, e.Field, e.Err)
+	}
+	return fmt.Sprintf("resource %q %s: expression %q: %v", e.NodeID, e.Field, e.Expression, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DryRunError) Unwrap() error {
+	return e.Err
+}
+
+// DryRun renders the parts of the graph that only depend on the instance, and
+// returns every failure that no amount of waiting would resolve: includeWhen,
+// forEach and template expressions that fail to evaluate, invalid resource
+// names and namespaces, and collection items sharing the same identity.
+//
+// It never reads the cluster. Expressions that depend on other resources, and
+// expressions that are only pending data, are skipped.
+func (r *Runtime) DryRun() []*DryRunError {
+	var errs []*DryRunError
+	for _, node := range r.Nodes() {
+		errs = append(errs, node.dryRun()...)
+	}
+	return errs
+}
+
+func (n *Node) dryRun() []*DryRunError {
+	// Whether a dependency is ignored is reported on the dependency itself.
+	for _, dep := range n.deps {
+		if ignored, err := dep.IsIgnored(); err != nil || ignored {
+			return nil
+		}
+	}
+
+	included, errs := n.dryRunIncludeWhen()
+	if !included || len(errs) > 0 {
+		return errs
+	}
+
+	if errs := n.dryRunTemplate(); len(errs) > 0 {
+		return errs
+	}
+
+	switch n.Spec.Meta.Type {
+	case graph.NodeTypeResource, graph.NodeTypeCollection:
+	default:
+		// External references are read, not applied.
+		return nil
+	}
+	if n.Spec.Meta.Type == graph.NodeTypeCollection {
+		if errs := n.dryRunForEach(); len(errs) > 0 {
+			return errs
+		}
+	}
+
+	objs, err := n.GetDesiredIdentity()
+	if err != nil {
+		if IsDataPending(err) || isCELDataPending(err) {
+			return nil
+		}
+		return []*DryRunError{{
+			NodeID:     n.Spec.Meta.ID,
+			Field:      "metadata.name",
+			Expression: n.expressionAt("metadata.name"),
+			Err:        err,
+		}}
+	}
+	return n.validateIdentities(objs)
+}
+
+// dryRunIncludeWhen evaluates the includeWhen expressions of the node, and
+// reports whether the node is included.
+func (n *Node) dryRunIncludeWhen() (bool, []*DryRunError) {
+	if len(n.includeWhenExprs) == 0 {
+		return true, nil
+	}
+
+	env, err := buildEnv([]string{graph.InstanceNodeID}, nil)
+	if err != nil {
+		return false, []*DryRunError{{NodeID: n.Spec.Meta.ID, Field: "includeWhen", Err: err}}
+	}
+	ctx := n.buildContext(graph.InstanceNodeID)
+
+	included := true
+	var errs []*DryRunError
+	for _, expr := range n.includeWhenExprs {
+		val, err := evalBoolExpr(env, expr, ctx)
+		if err != nil {
+			if !isCELDataPending(err) {
+				errs = append(errs, &DryRunError{
+					NodeID:     n.Spec.Meta.ID,
+					Field:      "includeWhen",
+					Expression: expr.Expression,
+					Err:        err,
+				})
+			}
+			included = false
+			continue
+		}
+		if !val {
+			included = false
+		}
+	}
+	return included, errs
+}
+
+// dryRunTemplate evaluates the template expressions of the node that only
+// reference the instance.
+func (n *Node) dryRunTemplate() []*DryRunError {
+	var errs []*DryRunError
+	for _, v := range n.templateVars {
+		if !v.Kind.IsStatic() {
+			continue
+		}
+		for _, expr := range v.Expressions {
+			_, _, err := n.evaluateExprsFiltered(map[string]struct{}{expr: {}}, false)
+			if err == nil || IsDataPending(err) {
+				continue
+			}
+			errs = append(errs, &DryRunError{
+				NodeID:     n.Spec.Meta.ID,
+				Field:      v.Path,
+				Expression: expr,
+				Err:        err,
+			})
+		}
+	}
+	return errs
+}
+
+// dryRunForEach evaluates the forEach dimensions of the node.
+func (n *Node) dryRunForEach() []*DryRunError {
+	singles, collections, _ := n.contextDependencyIDs(nil)
+	env, err := buildEnv(singles, collections)
+	if err != nil {
+		return []*DryRunError{{NodeID: n.Spec.Meta.ID, Field: "forEach", Err: err}}
+	}
+	ctx := n.buildContext()
+
+	var errs []*DryRunError
+	for i, dim := range n.Spec.ForEach {
+		if _, err := evalListExpr(env, n.forEachExprs[i], ctx); err != nil && !isCELDataPending(err) {
+			errs = append(errs, &DryRunError{
+				NodeID:     n.Spec.Meta.ID,
+				Field:      "forEach",
+				Expression: dim.Expression,
+				Err:        err,
+			})
+		}
+	}
+	return errs
+}
+
+// validateIdentities checks the rendered names and namespaces the same way
+// the API server would for built-in kinds.
+func (n *Node) validateIdentities(objs []*unstructured.Unstructured) []*DryRunError {
+	var errs []*DryRunError
+	for _, obj := range objs {
+		if msgs := validateName(obj); len(msgs) > 0 {
+			errs = append(errs, &DryRunError{
+				NodeID:     n.Spec.Meta.ID,
+				Field:      "metadata.name",
+				Expression: n.expressionAt("metadata.name"),
+				Err:        fmt.Errorf("invalid name %q: %s", obj.GetName(), strings.Join(msgs, ", ")),
+			})
+		}
+		if ns := obj.GetNamespace(); ns != "" {
+			if msgs := validation.IsDNS1123Label(ns); len(msgs) > 0 {
+				errs = append(errs, &DryRunError{
+					NodeID:     n.Spec.Meta.ID,
+					Field:      "metadata.namespace",
+					Expression: n.expressionAt("metadata.namespace"),
+					Err:        fmt.Errorf("invalid namespace %q: %s", ns, strings.Join(msgs, ", ")),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// expressionAt returns the first expression of the template field at path, or
+// an empty string if the field is not templated.
+func (n *Node) expressionAt(path string) string {
+	for _, v := range n.templateVarsForPaths([]string{path}) {
+		if len(v.Expressions) > 0 {
+			return v.Expressions[0]
+		}
+	}
+	return ""
+}
+
+// nameValidators holds the name validation of the built-in kinds, as done by
+// the API server. The names of other kinds, including custom resources, are
+// left to the API server.
+var nameValidators = map[schema.GroupKind]apivalidation.ValidateNameFunc{
+	{Kind: "ConfigMap"}:                                              apivalidation.NameIsDNSSubdomain,
+	{Kind: "Namespace"}:                                              apivalidation.ValidateNamespaceName,
+	{Kind: "PersistentVolumeClaim"}:                                  apivalidation.NameIsDNSSubdomain,
+	{Kind: "Pod"}:                                                    apivalidation.NameIsDNSSubdomain,
+	{Kind: "Secret"}:                                                 apivalidation.NameIsDNSSubdomain,
+	{Kind: "Service"}:                                                apivalidation.NameIsDNS1035Label,
+	{Kind: "ServiceAccount"}:                                         apivalidation.ValidateServiceAccountName,
+	{Group: "apps", Kind: "DaemonSet"}:                               apivalidation.NameIsDNSSubdomain,
+	{Group: "apps", Kind: "Deployment"}:                              apivalidation.NameIsDNSSubdomain,
+	{Group: "apps", Kind: "ReplicaSet"}:                              apivalidation.NameIsDNSSubdomain,
+	{Group: "apps", Kind: "StatefulSet"}:                             apivalidation.NameIsDNSSubdomain,
+	{Group: "batch", Kind: "CronJob"}:                                apivalidation.NameIsDNSSubdomain,
+	{Group: "batch", Kind: "Job"}:                                    apivalidation.NameIsDNSSubdomain,
+	{Group: "networking.k8s.io", Kind: "Ingress"}:                    apivalidation.NameIsDNSSubdomain,
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:              apivalidation.NameIsDNSSubdomain,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:        path.ValidatePathSegmentName,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}: path.ValidatePathSegmentName,
+	{Group: "rbac.authorization.k8s.io", Kind: "Role"}:               path.ValidatePathSegmentName,
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}:        path.ValidatePathSegmentName,
+}
+
+// validateName returns the reasons the name of obj is invalid. Names are
+// required, and the names of built-in kinds are validated as the API server
+// does.
+func validateName(obj *unstructured.Unstructured) []string {
+	name := obj.GetName()
+	if name == "" {
+		if obj.GetGenerateName() != "" {
+			return nil
+		}
+		return []string{"name is required"}
+	}
+	if validate, ok := nameValidators[obj.GroupVersionKind().GroupKind()]; ok {
+		return validate(name, false)
+	}
+	return nil
+}
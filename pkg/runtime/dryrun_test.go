@@ -0,0 +1,254 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubernetes-sigs/kro/pkg/graph"
+	"github.com/kubernetes-sigs/kro/pkg/graph/variable"
+)
+
+func TestRuntime_DryRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		nodes     []*graph.Node
+		spec      map[string]any
+		wantErrs  []DryRunError
+		errSubstr []string
+	}{
+		{
+			name: "valid instance",
+			nodes: []*graph.Node{
+				dryRunNode("config", graph.NodeTypeResource).
+					withVar("metadata.name", variable.ResourceVariableKindStatic, "schema.spec.name").
+					node,
+			},
+			spec: map[string]any{"name": "app"},
+		},
+		{
+			name: "static expression fails",
+			nodes: []*graph.Node{
+				dryRunNode("config", graph.NodeTypeResource).
+					withVar("data.ratio", variable.ResourceVariableKindStatic, "schema.spec.a / schema.spec.b").
+					node,
+			},
+			spec: map[string]any{"a": int64(1), "b": int64(0)},
+			wantErrs: []DryRunError{
+				{NodeID: "config", Field: "data.ratio", Expression: "schema.spec.a / schema.spec.b"},
+			},
+			errSubstr: []string{"division by zero"},
+		},
+		{
+			name: "pending static expression is skipped",
+			nodes: []*graph.Node{
+				dryRunNode("config", graph.NodeTypeResource).
+					withVar("data.value", variable.ResourceVariableKindStatic, "schema.spec.missing").
+					node,
+			},
+			spec: map[string]any{"name": "app"},
+		},
+		{
+			name: "dynamic expressions are skipped",
+			nodes: []*graph.Node{
+				dryRunNode("first", graph.NodeTypeResource).node,
+				dryRunNode("second", graph.NodeTypeResource).
+					withDependencies("first").
+					withVar("data.value", variable.ResourceVariableKindDynamic, "first.data.a / 0").
+					node,
+			},
+			spec: map[string]any{"name": "app"},
+		},
+		{
+			name: "invalid computed name",
+			nodes: []*graph.Node{
+				dryRunNode("config", graph.NodeTypeResource).
+					withVar("metadata.name", variable.ResourceVariableKindStatic, "schema.spec.name").
+					node,
+			},
+			spec: map[string]any{"name": "My_App"},
+			wantErrs: []DryRunError{
+				{NodeID: "config", Field: "metadata.name", Expression: "schema.spec.name"},
+			},
+			errSubstr: []string{`invalid name "My_App"`},
+		},
+		{
+			name: "RBAC names only need to be path segments",
+			nodes: []*graph.Node{
+				dryRunNode("role", graph.NodeTypeResource).
+					withGVK("rbac.authorization.k8s.io/v1", "Role").
+					withVar("metadata.name", variable.ResourceVariableKindStatic, "schema.spec.name").
+					node,
+			},
+			spec: map[string]any{"name": "system:App"},
+		},
+		{
+			name: "Service names are DNS labels",
+			nodes: []*graph.Node{
+				dryRunNode("service", graph.NodeTypeResource).
+					withGVK("v1", "Service").
+					withVar("metadata.name", variable.ResourceVariableKindStatic, "schema.spec.name").
+					node,
+			},
+			spec: map[string]any{"name": "my.app"},
+			wantErrs: []DryRunError{
+				{NodeID: "service", Field: "metadata.name", Expression: "schema.spec.name"},
+			},
+			errSubstr: []string{`invalid name "my.app"`},
+		},
+		{
+			name: "names of custom kinds are left to the API server",
+			nodes: []*graph.Node{
+				dryRunNode("bucket", graph.NodeTypeResource).
+					withGVK("s3.services.k8s.aws/v1alpha1", "Bucket").
+					withVar("metadata.name", variable.ResourceVariableKindStatic, "schema.spec.name").
+					node,
+			},
+			spec: map[string]any{"name": "My_App"},
+		},
+		{
+			name: "excluded resources are not rendered",
+			nodes: []*graph.Node{
+				dryRunNode("config", graph.NodeTypeResource).
+					withIncludeWhen("schema.spec.enabled").
+					withVar("metadata.name", variable.ResourceVariableKindStatic, "schema.spec.name").
+					node,
+			},
+			spec: map[string]any{"name": "My_App", "enabled": false},
+		},
+		{
+			name: "includeWhen fails",
+			nodes: []*graph.Node{
+				dryRunNode("config", graph.NodeTypeResource).
+					withIncludeWhen("schema.spec.name > 1").
+					node,
+			},
+			spec: map[string]any{"name": "app"},
+			wantErrs: []DryRunError{
+				{NodeID: "config", Field: "includeWhen", Expression: "schema.spec.name > 1"},
+			},
+		},
+		{
+			name: "forEach fails",
+			nodes: []*graph.Node{
+				dryRunNode("configs", graph.NodeTypeCollection).
+					withForEach("region", "schema.spec.name").
+					node,
+			},
+			spec: map[string]any{"name": "app"},
+			wantErrs: []DryRunError{
+				{NodeID: "configs", Field: "forEach", Expression: "schema.spec.name"},
+			},
+			errSubstr: []string{"did not return a list"},
+		},
+		{
+			name: "collection items with the same identity",
+			nodes: []*graph.Node{
+				dryRunNode("configs", graph.NodeTypeCollection).
+					withForEach("region", "schema.spec.regions").
+					withVar("metadata.name", variable.ResourceVariableKindIteration, "schema.spec.name + '-' + region").
+					node,
+			},
+			spec: map[string]any{"name": "app", "regions": []any{"east", "west", "east"}},
+			wantErrs: []DryRunError{
+				{NodeID: "configs", Field: "metadata.name", Expression: "schema.spec.name + '-' + region"},
+			},
+			errSubstr: []string{"duplicate identity"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &graph.Graph{
+				Nodes:    map[string]*graph.Node{},
+				Instance: &graph.Node{Meta: graph.NodeMeta{ID: graph.InstanceNodeID, Type: graph.NodeTypeInstance}},
+			}
+			for _, node := range tt.nodes {
+				g.Nodes[node.Meta.ID] = node
+				g.TopologicalOrder = append(g.TopologicalOrder, node.Meta.ID)
+			}
+			instance := testInstance("test")
+			instance.Object["spec"] = tt.spec
+
+			rt, err := FromGraph(g, instance)
+			require.NoError(t, err)
+
+			errs := rt.DryRun()
+			require.Len(t, errs, len(tt.wantErrs), "errors: %v", errs)
+			for i, want := range tt.wantErrs {
+				assert.Equal(t, want.NodeID, errs[i].NodeID)
+				assert.Equal(t, want.Field, errs[i].Field)
+				assert.Equal(t, want.Expression, errs[i].Expression)
+			}
+			for i, substr := range tt.errSubstr {
+				assert.Contains(t, errs[i].Error(), substr)
+			}
+		})
+	}
+}
+
+type dryRunNodeBuilder struct {
+	node *graph.Node
+}
+
+func dryRunNode(id string, nodeType graph.NodeType) *dryRunNodeBuilder {
+	return &dryRunNodeBuilder{node: &graph.Node{
+		Meta: graph.NodeMeta{ID: id, Type: nodeType, Namespaced: true},
+		Template: &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": id},
+		}},
+	}}
+}
+
+func (b *dryRunNodeBuilder) withGVK(apiVersion, kind string) *dryRunNodeBuilder {
+	b.node.Template.SetAPIVersion(apiVersion)
+	b.node.Template.SetKind(kind)
+	return b
+}
+
+func (b *dryRunNodeBuilder) withDependencies(ids ...string) *dryRunNodeBuilder {
+	b.node.Meta.Dependencies = ids
+	return b
+}
+
+func (b *dryRunNodeBuilder) withIncludeWhen(exprs ...string) *dryRunNodeBuilder {
+	b.node.IncludeWhen = exprs
+	return b
+}
+
+func (b *dryRunNodeBuilder) withForEach(name, expr string) *dryRunNodeBuilder {
+	b.node.ForEach = append(b.node.ForEach, graph.ForEachDimension{Name: name, Expression: expr})
+	return b
+}
+
+func (b *dryRunNodeBuilder) withVar(path string, kind variable.ResourceVariableKind, expr string) *dryRunNodeBuilder {
+	b.node.Variables = append(b.node.Variables, &variable.ResourceField{
+		FieldDescriptor: variable.FieldDescriptor{
+			Path:                 path,
+			Expressions:          []string{expr},
+			StandaloneExpression: true,
+		},
+		Kind: kind,
+	})
+	_ = unstructured.SetNestedField(b.node.Template.Object, "${"+expr+"}", strings.Split(path, ".")...)
+	return b
+}
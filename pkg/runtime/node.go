@@ -19,6 +19,8 @@ import (
 	"maps"
 	"slices"
 
+	"github.com/google/cel-go/common/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	krocel "github.com/kubernetes-sigs/kro/pkg/cel"
@@ -38,6 +40,15 @@ type Node struct {
 
 	desired  []*unstructured.Unstructured
 	observed []*unstructured.Unstructured
+	// missing is set when the resource of an optional external reference
+	// does not exist.
+	missing bool
+	// waitsFor holds the nodes that must be ready before this node is
+	// applied, without being referenced by it.
+	waitsFor []*Node
+	// hookDone is set when the hook of the node completed for the current
+	// run.
+	hookDone bool
 
 	includeWhenExprs []*expressionEvaluationState
 	readyWhenExprs   []*expressionEvaluationState
@@ -129,6 +140,15 @@ func (n *Node) GetDesired() ([]*unstructured.Unstructured, error) {
 				return nil, ErrDataPending
 			}
 		}
+		for _, node := range n.waitsFor {
+			ready, err := node.IsReady()
+			if err != nil {
+				return nil, err
+			}
+			if !ready {
+				return nil, ErrDataPending
+			}
+		}
 	}
 
 	var result []*unstructured.Unstructured
@@ -138,9 +158,9 @@ func (n *Node) GetDesired() ([]*unstructured.Unstructured, error) {
 		result, err = n.softResolve()
 	case graph.NodeTypeCollection:
 		result, err = n.hardResolveCollection(n.templateVars, true)
-	case graph.NodeTypeResource, graph.NodeTypeExternal:
-		// External refs resolve like resources (for name/namespace CEL),
-		// but the caller reads instead of applies.
+	case graph.NodeTypeResource, graph.NodeTypeExternal, graph.NodeTypeExternalCollection:
+		// External refs resolve like resources (for name/namespace/selector
+		// CEL), but the caller reads instead of applies.
 		result, err = n.hardResolveSingleResource(n.templateVars)
 	default:
 		panic(fmt.Sprintf("unknown node type: %v", n.Spec.Meta.Type))
@@ -175,7 +195,7 @@ func (n *Node) GetDesiredIdentity() ([]*unstructured.Unstructured, error) {
 			normalizeNamespaces(result, inst.observed[0].GetNamespace())
 		}
 		return result, nil
-	case graph.NodeTypeResource, graph.NodeTypeExternal:
+	case graph.NodeTypeResource, graph.NodeTypeExternal, graph.NodeTypeExternalCollection:
 		result, err := n.hardResolveSingleResource(vars)
 		if err != nil {
 			return nil, err
@@ -192,9 +212,13 @@ func (n *Node) GetDesiredIdentity() ([]*unstructured.Unstructured, error) {
 	}
 }
 
+// normalizeNamespaces sets the namespace of the objects that do not declare
+// one to the instance namespace. Cluster scoped instances have no namespace, so
+// their namespaced resources fall back to the default namespace.
 func normalizeNamespaces(objs []*unstructured.Unstructured, namespace string) {
-	// TODO: When cluster-scoped instances are supported, either default to
-	// metav1.NamespaceDefault here or enforce namespace presence in graphbuilder.
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
 	for _, obj := range objs {
 		if obj.GetNamespace() != "" {
 			continue
@@ -223,7 +247,7 @@ func (n *Node) DeleteTargets() ([]*unstructured.Unstructured, error) {
 			return orderedIntersection(n.observed, desired), nil
 		}
 		return n.observed, nil
-	case graph.NodeTypeInstance, graph.NodeTypeExternal:
+	case graph.NodeTypeInstance, graph.NodeTypeExternal, graph.NodeTypeExternalCollection:
 		panic(fmt.Sprintf("DeleteTargets called for node type %v", n.Spec.Meta.Type))
 	default:
 		panic(fmt.Sprintf("unknown node type: %v", n.Spec.Meta.Type))
@@ -501,6 +525,60 @@ func (n *Node) SetObserved(observed []*unstructured.Unstructured) {
 	n.observed = observed
 }
 
+// SetMissing records that the resource of an optional external reference does
+// not exist. The node then resolves to its default object, if any, or to an
+// empty optional value, and is considered ready.
+func (n *Node) SetMissing() {
+	n.missing = true
+	n.observed = []*unstructured.Unstructured{}
+	if obj := n.externalDefault(); obj != nil {
+		n.observed = []*unstructured.Unstructured{obj}
+	}
+}
+
+// externalDefault returns the resolved default object of an optional external
+// reference, completed with the identity of the referenced resource.
+func (n *Node) externalDefault() *unstructured.Unstructured {
+	if len(n.desired) == 0 {
+		return nil
+	}
+	desired := n.desired[0]
+	defaultObject, ok, _ := unstructured.NestedMap(desired.Object, graph.ExternalDefaultPath)
+	if !ok {
+		return nil
+	}
+
+	obj := &unstructured.Unstructured{Object: defaultObject}
+	obj.SetAPIVersion(desired.GetAPIVersion())
+	obj.SetKind(desired.GetKind())
+	if obj.GetName() == "" {
+		obj.SetName(desired.GetName())
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(desired.GetNamespace())
+	}
+	return obj
+}
+
+// SetHookDone marks the hook of the node as completed for the current run,
+// whether or not its resource still exists.
+func (n *Node) SetHookDone() {
+	n.hookDone = true
+}
+
+// HasTrueCondition returns true if obj reports the condition with status
+// True.
+func HasTrueCondition(obj *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if ok && condition["type"] == conditionType && condition["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsReady evaluates readyWhen expressions using observed state.
 // Ignored nodes are treated as ready for dependency gating purposes.
 func (n *Node) IsReady() (bool, error) {
@@ -513,6 +591,28 @@ func (n *Node) IsReady() (bool, error) {
 		return true, nil
 	}
 
+	// A missing optional external reference never blocks its dependents.
+	if n.missing {
+		return true, nil
+	}
+
+	// Hooks are ready once they completed for the current run.
+	if n.Spec.Hook != nil {
+		if n.hookDone {
+			return true, nil
+		}
+		if len(n.readyWhenExprs) == 0 {
+			return len(n.observed) > 0 && HasTrueCondition(n.observed[0], "Complete"), nil
+		}
+	}
+
+	if n.Spec.Meta.Type == graph.NodeTypeExternalCollection {
+		// The matched set may legitimately be empty, but it must have been read.
+		if n.observed == nil {
+			return false, nil
+		}
+		return n.areItemsReady(n.observed)
+	}
 	if len(n.readyWhenExprs) == 0 {
 		return true, nil
 	}
@@ -560,6 +660,14 @@ func (n *Node) isCollectionReady() (bool, error) {
 	if len(n.observed) < len(n.desired) {
 		return false, nil
 	}
+	return n.areItemsReady(n.observed)
+}
+
+// areItemsReady evaluates readyWhen expressions against each item.
+func (n *Node) areItemsReady(items []*unstructured.Unstructured) (bool, error) {
+	if len(n.readyWhenExprs) == 0 {
+		return true, nil
+	}
 
 	// Collection readyWhen uses "each" (single item) only.
 	env, err := krocel.DefaultEnvironment(
@@ -569,7 +677,7 @@ func (n *Node) isCollectionReady() (bool, error) {
 		return false, err
 	}
 
-	for i, obj := range n.observed {
+	for i, obj := range items {
 		ctx := map[string]any{graph.EachVarName: obj.Object}
 		for _, expr := range n.readyWhenExprs {
 			// readyWhen for collections must NOT be cached - each item has different "each" context.
@@ -631,18 +739,30 @@ func (n *Node) evaluateForEach() ([]map[string]any, error) {
 func (n *Node) buildContext(only ...string) map[string]any {
 	ctx := make(map[string]any)
 	for depID, dep := range n.deps {
-		if len(dep.observed) == 0 {
+		if dep.observed == nil {
+			continue
+		}
+		// An external collection that was read but matched nothing is an
+		// empty list, and a missing optional external reference is an empty
+		// optional value. Otherwise, nothing observed means missing data.
+		if len(dep.observed) == 0 && dep.Spec.Meta.Type != graph.NodeTypeExternalCollection && !dep.Spec.IsOptionalValue() {
 			continue
 		}
 		if len(only) > 0 && !slices.Contains(only, depID) {
 			continue
 		}
-		if dep.Spec.Meta.Type == graph.NodeTypeCollection {
+		if dep.Spec.Meta.Type.IsList() {
 			items := make([]any, len(dep.observed))
 			for i, obj := range dep.observed {
 				items[i] = obj.Object
 			}
 			ctx[depID] = items
+		} else if dep.Spec.IsOptionalValue() {
+			if len(dep.observed) == 0 {
+				ctx[depID] = types.OptionalNone
+			} else {
+				ctx[depID] = types.OptionalOf(types.DefaultTypeAdapter.NativeToValue(dep.observed[0].Object))
+			}
 		} else {
 			obj := dep.observed[0].Object
 			// For schema (instance), strip status - users should only access spec/metadata.
@@ -673,7 +793,7 @@ func withStatusOmitted(obj map[string]any) map[string]any {
 // - iterators: forEach loop variable names from iterCtx (declared as list(dyn))
 func (n *Node) contextDependencyIDs(iterCtx map[string]any) (singles, collections, iterators []string) {
 	for depID, dep := range n.deps {
-		if dep.Spec.Meta.Type == graph.NodeTypeCollection {
+		if dep.Spec.Meta.Type.IsList() {
 			collections = append(collections, depID)
 		} else {
 			singles = append(singles, depID)
@@ -187,6 +187,14 @@ func TestNode_BuildContext(t *testing.T) {
 			wantKeys:     []string{"buckets"},
 			checkBuckets: true,
 		},
+		{
+			name: "external collection with no matches returns empty list",
+			node: newTestNode("policy", graph.NodeTypeResource).
+				withDep(newTestNode("secrets", graph.NodeTypeExternalCollection).
+					withObserved().build()).
+				build(),
+			wantKeys: []string{"secrets"},
+		},
 		{
 			name: "only filter limits context",
 			node: newTestNode("test", graph.NodeTypeResource).
@@ -236,9 +244,10 @@ func TestNode_ContextDependencyIDs(t *testing.T) {
 				withDep(newTestNode("vpc", graph.NodeTypeResource).build()).
 				withDep(newTestNode("buckets", graph.NodeTypeCollection).build()).
 				withDep(newTestNode("external", graph.NodeTypeExternal).build()).
+				withDep(newTestNode("secrets", graph.NodeTypeExternalCollection).build()).
 				build(),
 			wantSingles:     []string{"schema", "vpc", "external"},
-			wantCollections: []string{"buckets"},
+			wantCollections: []string{"buckets", "secrets"},
 		},
 		{
 			name:          "includes iterator context",
@@ -362,6 +371,38 @@ func TestNode_IsReady_Collection(t *testing.T) {
 				withReadyWhen("each.status.ready == true").build(),
 			wantReady: false,
 		},
+		{
+			name: "external collection not yet listed is not ready",
+			node: newTestNode("secrets", graph.NodeTypeExternalCollection).
+				withReadyWhen("each.status.ready == true").build(),
+			wantReady: false,
+		},
+		{
+			name: "external collection with no matches is ready",
+			node: newTestNode("secrets", graph.NodeTypeExternalCollection).
+				withObserved().
+				withReadyWhen("each.status.ready == true").build(),
+			wantReady: true,
+		},
+		{
+			name: "external collection with an item not ready",
+			node: newTestNode("secrets", graph.NodeTypeExternalCollection).
+				withObserved(
+					map[string]any{"status": map[string]any{"ready": true}},
+					map[string]any{"status": map[string]any{"ready": false}},
+				).
+				withReadyWhen("each.status.ready == true").build(),
+			wantReady: false,
+		},
+		{
+			name: "external collection with all items ready",
+			node: newTestNode("secrets", graph.NodeTypeExternalCollection).
+				withObserved(
+					map[string]any{"status": map[string]any{"ready": true}},
+				).
+				withReadyWhen("each.status.ready == true").build(),
+			wantReady: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -373,6 +414,86 @@ func TestNode_IsReady_Collection(t *testing.T) {
 	}
 }
 
+func TestNode_SetMissing(t *testing.T) {
+	desiredConfig := func(defaultObject map[string]any) *unstructured.Unstructured {
+		obj := newUnstructured("v1", "ConfigMap", "team-a", "overrides")
+		if defaultObject != nil {
+			obj.Object[graph.ExternalDefaultPath] = defaultObject
+		}
+		return obj
+	}
+	defaultObject := map[string]any{"data": map[string]any{"region": "us-west-2"}}
+
+	tests := []struct {
+		name       string
+		config     *Node
+		missing    bool
+		wantRegion string
+		wantName   string
+	}{
+		{
+			name: "missing without default resolves to none",
+			config: newTestNode("config", graph.NodeTypeExternal).
+				withOptional(nil).
+				withDesired(desiredConfig(nil)).
+				withReadyWhen("config.data.ready == 'true'").build(),
+			missing:    true,
+			wantRegion: "none",
+		},
+		{
+			name: "missing with default resolves to the default object",
+			config: newTestNode("config", graph.NodeTypeExternal).
+				withOptional(defaultObject).
+				withDesired(desiredConfig(defaultObject)).
+				withReadyWhen("config.data.ready == 'true'").build(),
+			missing:    true,
+			wantRegion: "us-west-2",
+			wantName:   "overrides",
+		},
+		{
+			name: "found without default resolves to the object",
+			config: newTestNode("config", graph.NodeTypeExternal).
+				withOptional(nil).
+				withDesired(desiredConfig(nil)).
+				withObserved(map[string]any{"data": map[string]any{"region": "eu-west-1"}}).build(),
+			wantRegion: "eu-west-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.missing {
+				tt.config.SetMissing()
+				ready, err := tt.config.IsReady()
+				require.NoError(t, err)
+				assert.True(t, ready, "missing optional references never block dependents")
+			}
+			if tt.wantName != "" {
+				require.Len(t, tt.config.observed, 1)
+				assert.Equal(t, tt.wantName, tt.config.observed[0].GetName())
+				assert.Equal(t, "team-a", tt.config.observed[0].GetNamespace())
+				assert.Equal(t, "ConfigMap", tt.config.observed[0].GetKind())
+			}
+
+			app := newTestNode("app", graph.NodeTypeResource).withDep(tt.config).build()
+			ctx := app.buildContext()
+			require.Contains(t, ctx, "config")
+
+			singles, collections, _ := app.contextDependencyIDs(nil)
+			env, err := buildEnv(singles, collections)
+			require.NoError(t, err)
+
+			expr := `config.?data.?region.orValue("none")`
+			if !tt.config.Spec.IsOptionalValue() {
+				expr = `config.data.region`
+			}
+			region, err := evalRawCEL(env, expr, ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRegion, region)
+		})
+	}
+}
+
 func TestNode_IsIgnored_WithCEL(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1270,6 +1391,38 @@ func TestNode_HardResolveCollection(t *testing.T) {
 	}
 }
 
+func TestNormalizeNamespaces(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		want      []string
+	}{
+		{
+			name:      "namespaced instance",
+			namespace: "team-a",
+			want:      []string{"team-a", "explicit"},
+		},
+		{
+			name:      "cluster scoped instance",
+			namespace: "",
+			want:      []string{"default", "explicit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []*unstructured.Unstructured{
+				newUnstructured("v1", "ConfigMap", "", "implicit"),
+				newUnstructured("v1", "ConfigMap", "explicit", "explicit"),
+			}
+			normalizeNamespaces(objs, tt.namespace)
+			for i, obj := range objs {
+				assert.Equal(t, tt.want[i], obj.GetNamespace())
+			}
+		})
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Test Helpers - Builder pattern for creating test Nodes
 // -----------------------------------------------------------------------------
@@ -1287,6 +1440,7 @@ type testNodeBuilder struct {
 	templateExprs    []*expressionEvaluationState
 	templateVars     []*variable.ResourceField
 	template         *unstructured.Unstructured
+	optional         bool
 }
 
 // newTestNode creates a new test node builder with the given ID and type.
@@ -1319,6 +1473,17 @@ func (b *testNodeBuilder) withObservedUnstructured(objects ...*unstructured.Unst
 	return b
 }
 
+// withOptional marks the node as an optional external reference, with the
+// given default object if not nil.
+func (b *testNodeBuilder) withOptional(defaultObject map[string]any) *testNodeBuilder {
+	b.optional = true
+	b.template = &unstructured.Unstructured{Object: map[string]any{}}
+	if defaultObject != nil {
+		b.template.Object[graph.ExternalDefaultPath] = defaultObject
+	}
+	return b
+}
+
 // withDesired sets desired state.
 func (b *testNodeBuilder) withDesired(objects ...*unstructured.Unstructured) *testNodeBuilder {
 	b.desired = objects
@@ -1401,8 +1566,9 @@ func (b *testNodeBuilder) build() *Node {
 	node := &Node{
 		Spec: &graph.Node{
 			Meta: graph.NodeMeta{
-				ID:   b.id,
-				Type: b.nodeType,
+				ID:       b.id,
+				Type:     b.nodeType,
+				Optional: b.optional,
 			},
 			Template: b.template,
 		},
@@ -1417,3 +1583,140 @@ func (b *testNodeBuilder) build() *Node {
 	}
 	return node
 }
+
+func TestNode_IsReady_Hook(t *testing.T) {
+	hook := func(observed ...map[string]any) *Node {
+		node := newTestNode("migrate", graph.NodeTypeResource).withObserved(observed...).build()
+		node.Spec.Hook = &graph.Hook{Phase: graph.HookPhasePreCreate}
+		return node
+	}
+	job := func(conditionType string) map[string]any {
+		return map[string]any{
+			"metadata": map[string]any{"name": "migrate"},
+			"status": map[string]any{
+				"conditions": []any{map[string]any{"type": conditionType, "status": "True"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		node      *Node
+		done      bool
+		wantReady bool
+	}{
+		{name: "not created", node: hook()},
+		{name: "running", node: hook(map[string]any{"metadata": map[string]any{"name": "migrate"}})},
+		{name: "failed", node: hook(job("Failed"))},
+		{name: "complete", node: hook(job("Complete")), wantReady: true},
+		{name: "done", node: hook(), done: true, wantReady: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.done {
+				tt.node.SetHookDone()
+			}
+			ready, err := tt.node.IsReady()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReady, ready)
+		})
+	}
+}
+
+func TestNode_GetDesired_WaitsFor(t *testing.T) {
+	hook := newTestNode("migrate", graph.NodeTypeResource).build()
+	hook.Spec.Hook = &graph.Hook{Phase: graph.HookPhasePreCreate}
+	node := newTestNode("app", graph.NodeTypeResource).
+		withTemplate(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "app"},
+		}).
+		build()
+	node.waitsFor = []*Node{hook}
+
+	_, err := node.GetDesired()
+	assert.ErrorIs(t, err, ErrDataPending)
+
+	hook.SetHookDone()
+	desired, err := node.GetDesired()
+	require.NoError(t, err)
+	assert.Len(t, desired, 1)
+}
+
+func TestNode_EvaluateCompletion(t *testing.T) {
+	completion := &graph.Completion{
+		SuccessWhen: []string{"export.status.phase == 'Succeeded'", "schema.spec.verify == false || export.status.verified"},
+		FailureWhen: []string{"export.status.phase == 'Failed'"},
+	}
+	instance := func(verify bool, export *Node) *Node {
+		node := newTestNode(graph.InstanceNodeID, graph.NodeTypeInstance).
+			withDep(export).
+			withObserved(map[string]any{
+				"spec":   map[string]any{"verify": verify},
+				"status": map[string]any{"state": "IN_PROGRESS"},
+			}).
+			build()
+		node.Spec.Completion = completion
+		return node
+	}
+	export := func(status map[string]any) *Node {
+		return newTestNode("export", graph.NodeTypeResource).
+			withObserved(map[string]any{"metadata": map[string]any{"name": "export"}, "status": status}).
+			build()
+	}
+
+	tests := []struct {
+		name     string
+		node     *Node
+		want     CompletionOutcome
+		wantExpr string
+	}{
+		{
+			name: "not observed yet",
+			node: instance(false, newTestNode("export", graph.NodeTypeResource).build()),
+			want: CompletionRunning,
+		},
+		{
+			name: "status not populated yet",
+			node: instance(false, export(map[string]any{})),
+			want: CompletionRunning,
+		},
+		{
+			name: "running",
+			node: instance(false, export(map[string]any{"phase": "Running"})),
+			want: CompletionRunning,
+		},
+		{
+			name: "succeeded",
+			node: instance(false, export(map[string]any{"phase": "Succeeded"})),
+			want: CompletionSucceeded,
+		},
+		{
+			name: "not all success expressions met",
+			node: instance(true, export(map[string]any{"phase": "Succeeded", "verified": false})),
+			want: CompletionRunning,
+		},
+		{
+			name:     "failed",
+			node:     instance(false, export(map[string]any{"phase": "Failed"})),
+			want:     CompletionFailed,
+			wantExpr: "export.status.phase == 'Failed'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, expr, err := tt.node.EvaluateCompletion()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, outcome)
+			assert.Equal(t, tt.wantExpr, expr)
+		})
+	}
+
+	t.Run("no completion", func(t *testing.T) {
+		_, _, err := newTestNode(graph.InstanceNodeID, graph.NodeTypeInstance).build().EvaluateCompletion()
+		assert.Error(t, err)
+	})
+}
@@ -105,6 +105,11 @@ func FromGraph(g *graph.Graph, instance *unstructured.Unstructured) (*Runtime, e
 				node.deps[depID] = dep
 			}
 		}
+		for _, id := range node.Spec.Meta.WaitsFor {
+			if dep, ok := rt.nodes[id]; ok {
+				node.waitsFor = append(node.waitsFor, dep)
+			}
+		}
 	}
 
 	// Wire up instance node dependencies.
@@ -0,0 +1,255 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sharding spreads the reconciliation of instances across the
+// replicas of kro. Each replica holds a Lease while it is alive; instances are
+// assigned to the live replicas with a consistent hash ring, so that replicas
+// joining or leaving only move a fraction of the instances.
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubernetes-sigs/kro/pkg/metadata"
+)
+
+// GroupLabel labels the Leases of the replicas sharing instances, with the
+// name of their group.
+const GroupLabel = metadata.LabelKROPrefix + "shard-group"
+
+// Key selects what instances are assigned to replicas by.
+type Key string
+
+const (
+	// KeyInstance assigns each instance, by namespace and name.
+	KeyInstance Key = "instance"
+	// KeyNamespace assigns all the instances of a namespace to the same
+	// replica. Cluster scoped instances are assigned by name.
+	KeyNamespace Key = "namespace"
+)
+
+// Config holds the configuration of a Coordinator.
+type Config struct {
+	// Client is used to manage Leases.
+	Client kubernetes.Interface
+	// Namespace holds the Leases of the replicas.
+	Namespace string
+	// Group is the name of the group of replicas sharing instances. Leases
+	// are named after the group and the identity of their replica.
+	Group string
+	// Identity uniquely identifies this replica. It must be a valid DNS label.
+	Identity string
+	// Key selects what instances are assigned to replicas by.
+	Key Key
+	// LeaseDuration is the time after which a replica that stopped renewing
+	// its Lease is considered gone, and its instances move to other replicas.
+	LeaseDuration time.Duration
+	// RenewPeriod is the interval at which the Lease is renewed and the
+	// members of the group are listed.
+	RenewPeriod time.Duration
+}
+
+// Coordinator maintains the Lease of this replica, follows the live members
+// of its group, and decides which instances this replica owns.
+type Coordinator struct {
+	config Config
+	log    logr.Logger
+
+	mu   sync.RWMutex
+	ring *Ring
+	// onChange are called after the members changed.
+	onChange []func()
+}
+
+// NewCoordinator creates a Coordinator. Until it has renewed its Lease and
+// listed the members of its group, it owns no instance.
+func NewCoordinator(log logr.Logger, config Config) *Coordinator {
+	return &Coordinator{
+		config: config,
+		log:    log.WithName("sharding").WithValues("group", config.Group, "identity", config.Identity),
+		ring:   NewRing(nil),
+	}
+}
+
+// OnChange registers f to be called whenever members join or leave the
+// group. It must be called before Start.
+func (c *Coordinator) OnChange(f func()) {
+	c.onChange = append(c.onChange, f)
+}
+
+// Owns returns true if this replica reconciles the given instance.
+func (c *Coordinator) Owns(instance types.NamespacedName) bool {
+	key := instance.String()
+	if c.config.Key == KeyNamespace && instance.Namespace != "" {
+		key = instance.Namespace
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ring.Owner(key) == c.config.Identity
+}
+
+// Members returns the sorted live members of the group.
+func (c *Coordinator) Members() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ring.Members()
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: every replica
+// takes part in sharding.
+func (c *Coordinator) NeedLeaderElection() bool {
+	return false
+}
+
+// Start renews the Lease of this replica and follows the members of the group
+// until ctx is done. The Lease is then deleted, so that other replicas take
+// over the instances of this one right away.
+func (c *Coordinator) Start(ctx context.Context) error {
+	c.log.Info("Starting shard coordinator")
+	wait.UntilWithContext(ctx, c.sync, c.config.RenewPeriod)
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), c.config.RenewPeriod)
+	defer cancel()
+	err := c.leases().Delete(releaseCtx, c.leaseName(), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to release shard lease: %w", err)
+	}
+	c.log.Info("Released shard lease")
+	return nil
+}
+
+func (c *Coordinator) sync(ctx context.Context) {
+	if err := c.renew(ctx); err != nil {
+		c.log.Error(err, "failed to renew shard lease")
+	}
+	members, err := c.liveMembers(ctx)
+	if err != nil {
+		c.log.Error(err, "failed to list shard members")
+		return
+	}
+	c.setMembers(members)
+}
+
+// renew creates or renews the Lease of this replica.
+func (c *Coordinator) renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(time.Now())
+	lease, err := c.leases().Get(ctx, c.leaseName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.leaseName(),
+				Namespace: c.config.Namespace,
+				Labels:    map[string]string{GroupLabel: c.config.Group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(c.config.Identity),
+				LeaseDurationSeconds: ptr.To(int32(c.config.LeaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		_, err = c.leases().Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	lease.Spec.HolderIdentity = ptr.To(c.config.Identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(c.config.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	_, err = c.leases().Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// liveMembers returns the holders of the Leases of the group that did not
+// expire. Leases expired for long are deleted.
+func (c *Coordinator) liveMembers(ctx context.Context) ([]string, error) {
+	list, err := c.leases().List(ctx, metav1.ListOptions{LabelSelector: GroupLabel + "=" + c.config.Group})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var members []string
+	for i := range list.Items {
+		lease := &list.Items[i]
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil {
+			continue
+		}
+		duration := c.config.LeaseDuration
+		if lease.Spec.LeaseDurationSeconds != nil {
+			duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+		}
+		expiry := lease.Spec.RenewTime.Add(duration)
+		if now.Before(expiry) {
+			members = append(members, *lease.Spec.HolderIdentity)
+			continue
+		}
+		// Replicas that crashed leave their Lease behind.
+		if now.After(expiry.Add(10 * duration)) {
+			c.deleteStaleLease(ctx, lease)
+		}
+	}
+	return members, nil
+}
+
+func (c *Coordinator) deleteStaleLease(ctx context.Context, lease *coordinationv1.Lease) {
+	err := c.leases().Delete(ctx, lease.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: ptr.To(lease.ResourceVersion)},
+	})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		c.log.V(1).Info("failed to delete stale shard lease", "lease", lease.Name, "error", err)
+	}
+}
+
+// setMembers rebuilds the ring if the members changed, and notifies the
+// OnChange callbacks.
+func (c *Coordinator) setMembers(members []string) {
+	slices.Sort(members)
+	c.mu.Lock()
+	if slices.Equal(c.ring.Members(), members) {
+		c.mu.Unlock()
+		return
+	}
+	c.ring = NewRing(members)
+	c.mu.Unlock()
+
+	c.log.Info("Shard members changed", "members", members)
+	shardMembers.Set(float64(len(members)))
+	shardRebalancesTotal.Inc()
+	for _, f := range c.onChange {
+		f()
+	}
+}
+
+func (c *Coordinator) leaseName() string {
+	return c.config.Group + "-" + c.config.Identity
+}
+
+func (c *Coordinator) leases() coordinationv1client.LeaseInterface {
+	return c.config.Client.CoordinationV1().Leases(c.config.Namespace)
+}
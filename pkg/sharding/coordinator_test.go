@@ -0,0 +1,157 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func newTestCoordinator(client kubernetes.Interface, identity string, key Key) *Coordinator {
+	return NewCoordinator(logr.Discard(), Config{
+		Client:        client,
+		Namespace:     "kro-system",
+		Group:         "kro",
+		Identity:      identity,
+		Key:           key,
+		LeaseDuration: 15 * time.Second,
+		RenewPeriod:   5 * time.Second,
+	})
+}
+
+func TestCoordinator_Sync(t *testing.T) {
+	client := fake.NewClientset()
+	a := newTestCoordinator(client, "kro-a", KeyInstance)
+	b := newTestCoordinator(client, "kro-b", KeyInstance)
+
+	changes := 0
+	a.OnChange(func() { changes++ })
+
+	// Before its first sync, a replica owns no instance.
+	assert.False(t, a.Owns(types.NamespacedName{Namespace: "default", Name: "one"}))
+
+	a.sync(t.Context())
+	assert.Equal(t, []string{"kro-a"}, a.Members())
+	assert.Equal(t, 1, changes)
+	assert.True(t, a.Owns(types.NamespacedName{Namespace: "default", Name: "one"}))
+
+	b.sync(t.Context())
+	a.sync(t.Context())
+	assert.Equal(t, []string{"kro-a", "kro-b"}, a.Members())
+	assert.Equal(t, []string{"kro-a", "kro-b"}, b.Members())
+	assert.Equal(t, 2, changes)
+
+	// Renewing without membership changes does not notify.
+	a.sync(t.Context())
+	assert.Equal(t, 2, changes)
+
+	// Each instance is owned by exactly one replica.
+	for i := range 100 {
+		instance := types.NamespacedName{Namespace: "default", Name: fmt.Sprintf("instance-%d", i)}
+		assert.NotEqual(t, a.Owns(instance), b.Owns(instance), "instance %s", instance)
+	}
+
+	lease, err := client.CoordinationV1().Leases("kro-system").Get(t.Context(), "kro-kro-a", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "kro", lease.Labels[GroupLabel])
+	assert.Equal(t, "kro-a", *lease.Spec.HolderIdentity)
+}
+
+func TestCoordinator_ExpiredLeases(t *testing.T) {
+	now := time.Now()
+	newLease := func(identity string, renewed time.Time) *coordinationv1.Lease {
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kro-" + identity,
+				Namespace: "kro-system",
+				Labels:    map[string]string{GroupLabel: "kro"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(identity),
+				LeaseDurationSeconds: ptr.To(int32(15)),
+				RenewTime:            ptr.To(metav1.NewMicroTime(renewed)),
+			},
+		}
+	}
+	client := fake.NewClientset(
+		newLease("kro-live", now),
+		newLease("kro-expired", now.Add(-time.Minute)),
+		newLease("kro-stale", now.Add(-time.Hour)),
+	)
+	c := newTestCoordinator(client, "kro-a", KeyInstance)
+	c.sync(t.Context())
+
+	assert.Equal(t, []string{"kro-a", "kro-live"}, c.Members())
+	leases, err := client.CoordinationV1().Leases("kro-system").List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	var names []string
+	for _, lease := range leases.Items {
+		names = append(names, lease.Name)
+	}
+	// Leases expired for long are deleted.
+	assert.ElementsMatch(t, []string{"kro-kro-a", "kro-kro-live", "kro-kro-expired"}, names)
+}
+
+func TestCoordinator_KeyNamespace(t *testing.T) {
+	client := fake.NewClientset()
+	a := newTestCoordinator(client, "kro-a", KeyNamespace)
+	b := newTestCoordinator(client, "kro-b", KeyNamespace)
+	a.sync(t.Context())
+	b.sync(t.Context())
+	a.sync(t.Context())
+
+	// All the instances of a namespace are owned by the same replica.
+	for i := range 20 {
+		namespace := fmt.Sprintf("team-%d", i)
+		owner := a.Owns(types.NamespacedName{Namespace: namespace, Name: "instance-0"})
+		for j := range 10 {
+			instance := types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("instance-%d", j)}
+			assert.Equal(t, owner, a.Owns(instance), "instance %s", instance)
+		}
+	}
+}
+
+func TestCoordinator_Start(t *testing.T) {
+	client := fake.NewClientset()
+	c := newTestCoordinator(client, "kro-a", KeyInstance)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error)
+	go func() { done <- c.Start(ctx) }()
+
+	require.Eventually(t, func() bool {
+		_, err := client.CoordinationV1().Leases("kro-system").Get(t.Context(), "kro-kro-a", metav1.GetOptions{})
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The Lease is released on shutdown.
+	cancel()
+	require.NoError(t, <-done)
+	leases, err := client.CoordinationV1().Leases("kro-system").List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, leases.Items)
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharding
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		shardMembers,
+		shardRebalancesTotal,
+	)
+}
+
+var (
+	// shardMembers is the number of live replicas sharing instances, as seen
+	// by this replica.
+	shardMembers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "shard_members",
+			Help: "Number of live replicas instances are sharded across",
+		},
+	)
+	// shardRebalancesTotal counts the membership changes moving instances
+	// between replicas.
+	shardRebalancesTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "shard_rebalances_total",
+			Help: "Total number of shard membership changes",
+		},
+	)
+)
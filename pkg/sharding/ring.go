@@ -0,0 +1,90 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharding
+
+import (
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// virtualNodes is the number of points each member has on the ring. More
+// points spread keys more evenly between members.
+const virtualNodes = 128
+
+// Ring is a consistent hash ring. Each key is owned by the first member point
+// following the hash of the key on the ring, so that members joining or
+// leaving only move the keys of their neighbours.
+type Ring struct {
+	members []string
+	points  []uint64
+	owners  map[uint64]string
+}
+
+// NewRing returns a ring of the given members.
+func NewRing(members []string) *Ring {
+	r := &Ring{
+		members: slices.Sorted(slices.Values(members)),
+		owners:  make(map[uint64]string, len(members)*virtualNodes),
+	}
+	for _, member := range r.members {
+		for i := 0; i < virtualNodes; i++ {
+			point := hash(member + "#" + strconv.Itoa(i))
+			// Members are sorted: on the rare collision, the smallest member
+			// keeps the point, so that every replica builds the same ring.
+			if _, ok := r.owners[point]; ok {
+				continue
+			}
+			r.points = append(r.points, point)
+			r.owners[point] = member
+		}
+	}
+	slices.Sort(r.points)
+	return r
+}
+
+// Members returns the sorted members of the ring.
+func (r *Ring) Members() []string {
+	return r.members
+}
+
+// Owner returns the member owning key, or an empty string if the ring has no
+// members.
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// hash returns the FNV-1a hash of s, mixed with the murmur3 finalizer: FNV
+// alone spreads similar strings, such as the points of a member, poorly.
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("default/instance-%d", i)
+	}
+	return keys
+}
+
+func TestRing_Empty(t *testing.T) {
+	r := NewRing(nil)
+	assert.Empty(t, r.Members())
+	assert.Equal(t, "", r.Owner("default/instance"))
+}
+
+func TestRing_Members(t *testing.T) {
+	r := NewRing([]string{"kro-c", "kro-a", "kro-b"})
+	assert.Equal(t, []string{"kro-a", "kro-b", "kro-c"}, r.Members())
+}
+
+func TestRing_Distribution(t *testing.T) {
+	members := []string{"kro-a", "kro-b", "kro-c"}
+	r := NewRing(members)
+
+	keys := testKeys(30000)
+	counts := map[string]int{}
+	for _, key := range keys {
+		counts[r.Owner(key)]++
+	}
+	for _, member := range members {
+		// Each member owns about a third of the keys.
+		assert.InDelta(t, len(keys)/len(members), counts[member], float64(len(keys))*0.1, "member %s", member)
+	}
+}
+
+func TestRing_Stable(t *testing.T) {
+	// Members build the same ring regardless of the order they list each
+	// other in.
+	a := NewRing([]string{"kro-a", "kro-b", "kro-c"})
+	b := NewRing([]string{"kro-c", "kro-a", "kro-b"})
+	for _, key := range testKeys(1000) {
+		assert.Equal(t, a.Owner(key), b.Owner(key))
+	}
+}
+
+func TestRing_MinimalMovement(t *testing.T) {
+	before := NewRing([]string{"kro-a", "kro-b", "kro-c"})
+	after := NewRing([]string{"kro-a", "kro-b", "kro-c", "kro-d"})
+
+	keys := testKeys(10000)
+	moved := 0
+	for _, key := range keys {
+		owner := after.Owner(key)
+		if owner == before.Owner(key) {
+			continue
+		}
+		moved++
+		// Keys only move to the new member.
+		assert.Equal(t, "kro-d", owner, "key %s", key)
+	}
+	// About a quarter of the keys move to the new member.
+	assert.InDelta(t, len(keys)/4, moved, float64(len(keys))*0.1)
+}
@@ -176,3 +176,30 @@ func WithResourceCollection(
 		})
 	}
 }
+
+// WithVersion returns a SchemaOption that adds an additional version with the
+// given spec and conversion mappings to the schema.
+func WithVersion(name string, spec map[string]interface{}, conversion *krov1alpha1.VersionConversion) SchemaOption {
+	rawSpec, err := json.Marshal(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(schema *krov1alpha1.Schema) {
+		schema.Versions = append(schema.Versions, krov1alpha1.SchemaVersion{
+			Name: name,
+			Spec: runtime.RawExtension{
+				Object: &unstructured.Unstructured{Object: spec},
+				Raw:    rawSpec,
+			},
+			Conversion: conversion,
+		})
+	}
+}
+
+// WithScope returns a SchemaOption that sets the scope of the generated CRD.
+func WithScope(scope krov1alpha1.InstanceScope) SchemaOption {
+	return func(schema *krov1alpha1.Schema) {
+		schema.Scope = scope
+	}
+}
@@ -173,6 +173,9 @@ func (e *Environment) setupController() error {
 		dc,
 		e.GraphBuilder,
 		10,
+		nil,
+		false,
+		nil,
 	)
 
 	if err := e.CtrlManager.Add(dc); err != nil {
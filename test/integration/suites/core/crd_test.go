@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/rand"
 
 	krov1alpha1 "github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/controller/resourcegraphdefinition"
 	"github.com/kubernetes-sigs/kro/pkg/metadata"
 	"github.com/kubernetes-sigs/kro/pkg/testutil/generator"
 )
@@ -161,6 +162,74 @@ var _ = Describe("CRD", func() {
 			Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
 		})
 
+		It("should block breaking CRD changes unless explicitly allowed", func(ctx SpecContext) {
+			rgd := generator.NewResourceGraphDefinition("test-crd-breaking",
+				generator.WithSchema(
+					"TestBreaking", "v1alpha1",
+					map[string]interface{}{
+						"field1": "string",
+						"field2": "integer",
+					},
+					nil,
+				),
+			)
+			Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+
+			crdName := "testbreakings.kro.run"
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			Eventually(func(g Gomega, ctx SpecContext) {
+				g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: crdName}, crd)).To(Succeed())
+			}, 10*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+
+			// Remove field2 and change the type of field1
+			Eventually(func(g Gomega, ctx SpecContext) {
+				g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)).To(Succeed())
+				rgd.Spec.Schema.Spec = toRawExtension(map[string]interface{}{
+					"field1": "integer",
+				})
+				g.Expect(env.Client.Update(ctx, rgd)).To(Succeed())
+			}, 10*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+
+			// The update is blocked and reported on the KindReady condition
+			Eventually(func(g Gomega, ctx SpecContext) {
+				g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)).To(Succeed())
+				g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateInactive))
+
+				var kindReady *krov1alpha1.Condition
+				for i := range rgd.Status.Conditions {
+					if rgd.Status.Conditions[i].Type == resourcegraphdefinition.KindReady {
+						kindReady = &rgd.Status.Conditions[i]
+					}
+				}
+				g.Expect(kindReady).ToNot(BeNil())
+				g.Expect(kindReady.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(*kindReady.Reason).To(Equal("BreakingChanges"))
+				g.Expect(*kindReady.Message).To(ContainSubstring("spec.field1: type changed from string to integer"))
+				g.Expect(*kindReady.Message).To(ContainSubstring("spec.field2: field was removed"))
+			}, 20*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+
+			Expect(env.Client.Get(ctx, types.NamespacedName{Name: crdName}, crd)).To(Succeed())
+			props := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties
+			Expect(props["spec"].Properties["field1"].Type).To(Equal("string"))
+
+			// Opting into breaking changes unblocks the update, without touching
+			// the spec.
+			Eventually(func(g Gomega, ctx SpecContext) {
+				g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)).To(Succeed())
+				rgd.Annotations = map[string]string{metadata.AllowBreakingChangesAnnotation: "true"}
+				g.Expect(env.Client.Update(ctx, rgd)).To(Succeed())
+			}, 10*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+
+			Eventually(func(g Gomega, ctx SpecContext) {
+				g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: crdName}, crd)).To(Succeed())
+				props := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties
+				g.Expect(props["spec"].Properties["field1"].Type).To(Equal("integer"))
+				g.Expect(props["spec"].Properties).ToNot(HaveKey("field2"))
+			}, 20*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+
+			Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+		})
+
 		It("should delete CRD when ResourceGraphDefinition is deleted", func(ctx SpecContext) {
 			// Create ResourceGraphDefinition
 			rgd := generator.NewResourceGraphDefinition("test-crd-delete",
@@ -0,0 +1,155 @@
+// Copyright 2025 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	krov1alpha1 "github.com/kubernetes-sigs/kro/api/v1alpha1"
+	"github.com/kubernetes-sigs/kro/pkg/history"
+	"github.com/kubernetes-sigs/kro/pkg/testutil/generator"
+)
+
+var _ = Describe("Rollback", func() {
+	var namespace string
+
+	BeforeEach(func(ctx SpecContext) {
+		namespace = fmt.Sprintf("test-%s", rand.String(5))
+		Expect(env.Client.Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+		})).To(Succeed())
+	})
+
+	AfterEach(func(ctx SpecContext) {
+		Expect(env.Client.Delete(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+		})).To(Succeed())
+	})
+
+	It("should reconcile an instance with the pinned revision without restoring the spec", func(ctx SpecContext) {
+		configMap := func(value string) map[string]interface{} {
+			return map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "${schema.metadata.name}",
+				},
+				"data": map[string]interface{}{
+					"revision": value,
+					"name":     "${schema.spec.name}",
+				},
+			}
+		}
+		rgd := generator.NewResourceGraphDefinition("test-rollback",
+			generator.WithSchema(
+				"TestRollback", "v1alpha1",
+				map[string]interface{}{
+					"name": "string",
+				},
+				nil,
+			),
+			generator.WithResource("configmap", configMap("one"), nil, nil),
+		)
+		Expect(env.Client.Create(ctx, rgd)).To(Succeed())
+		DeferCleanup(func(ctx SpecContext) {
+			Expect(env.Client.Delete(ctx, rgd)).To(Succeed())
+		})
+
+		Eventually(func(g Gomega, ctx SpecContext) {
+			g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)).To(Succeed())
+			g.Expect(rgd.Status.State).To(Equal(krov1alpha1.ResourceGraphDefinitionStateActive))
+		}, 10*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+
+		instance := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": fmt.Sprintf("%s/%s", krov1alpha1.KRODomainName, "v1alpha1"),
+				"kind":       "TestRollback",
+				"metadata": map[string]interface{}{
+					"name":      "test-rollback",
+					"namespace": namespace,
+				},
+				"spec": map[string]interface{}{
+					"name": "first",
+				},
+			},
+		}
+		Expect(env.Client.Create(ctx, instance)).To(Succeed())
+
+		expectConfigMap := func(revision, name string) {
+			GinkgoHelper()
+			Eventually(func(g Gomega, ctx SpecContext) {
+				cm := &corev1.ConfigMap{}
+				g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: "test-rollback", Namespace: namespace}, cm)).
+					To(Succeed())
+				g.Expect(cm.Data).To(Equal(map[string]string{"revision": revision, "name": name}))
+			}, 20*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+		}
+		expectConfigMap("one", "first")
+
+		// Roll out a new revision of the graph, and change the spec.
+		Eventually(func(g Gomega, ctx SpecContext) {
+			g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: rgd.Name}, rgd)).To(Succeed())
+			rgd.Spec.Resources[0].Template = toRawExtension(configMap("two"))
+			g.Expect(env.Client.Update(ctx, rgd)).To(Succeed())
+		}, 10*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+		expectConfigMap("two", "first")
+
+		Eventually(func(g Gomega, ctx SpecContext) {
+			g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: instance.GetName(), Namespace: namespace},
+				instance)).To(Succeed())
+			g.Expect(unstructured.SetNestedField(instance.Object, "second", "spec", "name")).To(Succeed())
+			g.Expect(env.Client.Update(ctx, instance)).To(Succeed())
+		}, 10*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+		expectConfigMap("two", "second")
+
+		// Roll back to the first entry of the history, which was rendered from
+		// the first graph revision.
+		revisions, err := history.List(ctx, env.ClientSet.Kubernetes(), instance)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(revisions).ToNot(BeEmpty())
+		entry, err := history.Decode(&revisions[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entry.GraphRevision).To(Equal(int64(1)))
+
+		gvr := schema.GroupVersionResource{Group: krov1alpha1.KRODomainName, Version: "v1alpha1", Resource: "testrollbacks"}
+		Expect(env.Client.Get(ctx, types.NamespacedName{Name: instance.GetName(), Namespace: namespace},
+			instance)).To(Succeed())
+		_, err = history.Rollback(ctx, env.ClientSet.Dynamic().Resource(gvr).Namespace(namespace),
+			instance, entry, false)
+		Expect(err).ToNot(HaveOccurred())
+
+		// The pinned revision is applied to the current spec.
+		expectConfigMap("one", "second")
+		Eventually(func(g Gomega, ctx SpecContext) {
+			g.Expect(env.Client.Get(ctx, types.NamespacedName{Name: instance.GetName(), Namespace: namespace},
+				instance)).To(Succeed())
+			revision, _, err := unstructured.NestedInt64(instance.Object, "status", "graphRevision")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(revision).To(Equal(int64(1)))
+		}, 20*time.Second, time.Second).WithContext(ctx).Should(Succeed())
+
+		Expect(env.Client.Delete(ctx, instance)).To(Succeed())
+	})
+})